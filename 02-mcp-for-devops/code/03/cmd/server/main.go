@@ -48,20 +48,24 @@ func main() {
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Start server in a goroutine
-	serverErr := make(chan error, 1)
+	serverDone := make(chan error, 1)
 	go func() {
-		if err := mcpServer.Start(ctx); err != nil {
-			serverErr <- err
-		}
+		serverDone <- mcpServer.Start(ctx)
 	}()
 
-	// Wait for shutdown signal or server error
+	// Wait for shutdown signal or for the server to stop on its own
 	select {
 	case sig := <-sigChan:
 		logger.Infof("Received signal %v, shutting down gracefully...", sig)
 		cancel()
-	case err := <-serverErr:
-		logger.Errorf("Server error: %v", err)
+		// Let the server finish the request in flight before exiting
+		if err := <-serverDone; err != nil {
+			logger.Errorf("Server error: %v", err)
+		}
+	case err := <-serverDone:
+		if err != nil {
+			logger.Errorf("Server error: %v", err)
+		}
 		cancel()
 	}
 
@@ -2,7 +2,9 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"kubernetes-mcp-server/internal/config"
@@ -118,12 +120,15 @@ func (s *Server) registerResources() {
 	}
 }
 
-// Start starts the MCP server with stdio transport
+// Start starts the MCP server with stdio transport and serves until stdin is closed or
+// ctx is cancelled
 func (s *Server) Start(ctx context.Context) error {
 	s.logger.Info("Starting Kubernetes MCP Server")
 
-	// Use the convenient ServeStdio function
-	if err := server.ServeStdio(s.mcpServer); err != nil {
+	// Listen stops reading requests once ctx is cancelled; a request already being
+	// handled finishes and its response is written first
+	err := server.NewStdioServer(s.mcpServer).Listen(ctx, os.Stdin, os.Stdout)
+	if err != nil && !errors.Is(err, context.Canceled) {
 		s.logger.Errorf("MCP server error: %v", err)
 		return fmt.Errorf("MCP server failed: %w", err)
 	}
@@ -141,14 +146,21 @@ func (s *Server) handleResourceRead(ctx context.Context, request mcp.ReadResourc
 		return nil, fmt.Errorf("invalid URI format. Expected k8s://<resource-type>/<namespace>/<name>, got: %s", uri)
 	}
 
-	// Parse URI: k8s://<resource-type>/<namespace>/<name>
+	// Parse URI: k8s://<resource-type>/<namespace>/<name>, or k8s://namespace/<name>
+	// since namespaces are cluster-scoped
 	parts := strings.Split(strings.TrimPrefix(uri, "k8s://"), "/")
-	if len(parts) != 3 {
+	var resourceType, namespace, name string
+	switch {
+	case len(parts) == 2 && parts[0] == "namespace":
+		resourceType, name = parts[0], parts[1]
+	case len(parts) == 3 && parts[0] != "namespace":
+		resourceType, namespace, name = parts[0], parts[1], parts[2]
+	case parts[0] == "namespace":
+		return nil, fmt.Errorf("invalid URI format. Expected k8s://namespace/<name>, got %d parts", len(parts))
+	default:
 		return nil, fmt.Errorf("invalid URI format. Expected k8s://<resource-type>/<namespace>/<name>, got %d parts", len(parts))
 	}
 
-	resourceType, namespace, name := parts[0], parts[1], parts[2]
-
 	var resourceTypeEnum types.K8sResourceType
 	switch resourceType {
 	case "pod":
@@ -157,8 +169,12 @@ func (s *Server) handleResourceRead(ctx context.Context, request mcp.ReadResourc
 		resourceTypeEnum = types.ResourceTypeService
 	case "deployment":
 		resourceTypeEnum = types.ResourceTypeDeployment
+	case "configmap":
+		resourceTypeEnum = types.ResourceTypeConfigMap
+	case "namespace":
+		resourceTypeEnum = types.ResourceTypeNamespace
 	default:
-		return nil, fmt.Errorf("unsupported resource type: %s. Supported types: pod, service, deployment", resourceType)
+		return nil, fmt.Errorf("unsupported resource type: %s. Supported types: pod, service, deployment, configmap, namespace", resourceType)
 	}
 
 	content, err := s.k8sClient.GetResource(ctx, &types.ResourceIdentifier{
@@ -1,27 +1,39 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
+	_ "github.com/lib/pq" // PostgreSQL driver for the audit database sink
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"kubernetes-mcp-server/internal/config"
 	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/internal/tracing"
 	"kubernetes-mcp-server/pkg/audit"
 	"kubernetes-mcp-server/pkg/auth"
 	"kubernetes-mcp-server/pkg/k8s"
 	"kubernetes-mcp-server/pkg/mcp"
+	"kubernetes-mcp-server/pkg/preflight"
 	"kubernetes-mcp-server/pkg/rbac"
 	"kubernetes-mcp-server/pkg/security"
+	"kubernetes-mcp-server/pkg/types"
 )
 
 func main() {
@@ -31,32 +43,107 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	// Initialize logger
-	logger := logging.NewLogger("info", "text")
-	logrusLogger := logrus.New()
+	// Initialize loggers from the logging section of the config
+	logger := logging.NewLogger(cfg.Log.Level, cfg.Log.Format)
+	logrusLogger := logging.NewLogger(cfg.Log.Level, cfg.Log.Format).Logger
+	if _, err := logrus.ParseLevel(cfg.Log.Level); err != nil {
+		logger.Warnf("Invalid log level %q, logging at info", cfg.Log.Level)
+	}
+	if cfg.Log.Format != "json" && cfg.Log.Format != "text" {
+		logger.Warnf("Invalid log format %q, logging as text", cfg.Log.Format)
+	}
+	if cfg.Log.SensitiveKeyPattern != "" {
+		if err := logging.SetSensitiveKeyPattern(cfg.Log.SensitiveKeyPattern); err != nil {
+			logger.Fatalf("Failed to set up log redaction: %v", err)
+		}
+	}
 	logger.Info("Starting Kubernetes MCP Server with security features")
 
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, cfg.Server)
+	if err != nil {
+		logger.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warnf("Failed to flush traces: %v", err)
+		}
+	}()
+	if cfg.Tracing.Enabled {
+		logger.Infof("Exporting traces over OTLP/%s to %s", cfg.Tracing.Protocol, cfg.Tracing.Endpoint)
+	}
+
 	// Initialize Kubernetes client
 	k8sClient, err := k8s.NewClient(cfg.K8s.ConfigPath, logger)
 	if err != nil {
 		logger.Fatalf("Failed to create Kubernetes client: %v", err)
 	}
+	k8sClient.SetAllowedNamespaces(cfg.K8s.Namespaces)
 
-	// Test Kubernetes connection
 	ctx := context.Background()
-	if err := k8sClient.HealthCheck(ctx); err != nil {
-		logger.Fatalf("Kubernetes health check failed: %v", err)
+
+	// Fail fast rather than start a server whose every tool call would hang
+	healthCtx, cancelHealth := context.WithTimeout(ctx, startupHealthCheckTimeout)
+	err = k8sClient.HealthCheck(healthCtx)
+	cancelHealth()
+	if err != nil {
+		logger.Fatalf("Kubernetes API server did not answer within %s: %v", startupHealthCheckTimeout, err)
+	}
+
+	if cfg.K8s.InformerCache {
+		if err := k8sClient.EnableCache(ctx, informerResync, informerSyncTimeout); err != nil {
+			logger.Warnf("Informer cache disabled, reading from the API server: %v", err)
+		} else {
+			logger.Info("Serving pod, service and deployment reads from the informer cache")
+		}
 	}
-	logger.Info("Kubernetes connection established successfully")
 
 	// Initialize audit logger
 	auditLogger := audit.NewAuditLogger(logrusLogger)
+	if fileConfig := cfg.Audit.File; fileConfig.Path != "" {
+		auditLogger, err = audit.NewFileAuditLogger(fileConfig.Path, audit.RotationPolicy{
+			MaxSizeMB:   fileConfig.MaxSizeMB,
+			RotateEvery: fileConfig.RotateEvery,
+			MaxAge:      fileConfig.MaxAge,
+			MaxBackups:  fileConfig.MaxBackups,
+			Compress:    fileConfig.Compress,
+		}, logrusLogger)
+		if err != nil {
+			logger.Fatalf("Failed to set up audit log file: %v", err)
+		}
+		logger.Infof("Writing audit events to %s", fileConfig.Path)
+	}
+	defer auditLogger.Close()
+	if cfg.Audit.Database.DSN != "" {
+		dbSink, err := newAuditDBSink(ctx, cfg.Audit.Database, logrusLogger)
+		if err != nil {
+			logger.Fatalf("Failed to set up audit database sink: %v", err)
+		}
+		auditLogger.AddSink(dbSink)
+		logger.Infof("Writing audit events to database table %s", cfg.Audit.Database.Table)
+	}
+	if webhookConfig := cfg.Audit.Webhook; webhookConfig.URL != "" {
+		webhookSink, err := audit.NewWebhookSink(audit.WebhookSinkConfig{
+			URL:        webhookConfig.URL,
+			Headers:    webhookConfig.Headers,
+			Timeout:    webhookConfig.Timeout,
+			MaxRetries: webhookConfig.MaxRetries,
+			BufferSize: webhookConfig.BufferSize,
+		}, logrusLogger)
+		if err != nil {
+			logger.Fatalf("Failed to set up audit webhook sink: %v", err)
+		}
+		auditLogger.AddSink(webhookSink)
+		// The URL may carry a token in its query, so it is not logged
+		logger.Info("Sending audit events to the configured webhook")
+	}
 
 	// Initialize RBAC enforcer
 	rbacEnforcer := rbac.NewRBACEnforcer(logrusLogger)
 
 	// Load RBAC policies from file (optional - will use default policies if file doesn't exist)
-	if policyData, err := os.ReadFile("./configs/rbac-policies.yaml"); err == nil {
+	if policyData, err := os.ReadFile(rbacPolicyPath); err == nil {
 		if err := rbacEnforcer.LoadPolicy(policyData); err != nil {
 			logger.Warnf("Failed to load RBAC policies: %v", err)
 		}
@@ -65,8 +152,251 @@ func main() {
 	}
 
 	// Initialize authenticators
-	// API Key store and authenticator with demo keys
-	apiKeyStore := auth.NewInMemoryAPIKeyStore(logrusLogger)
+	var apiKeyStore auth.APIKeyStore
+	if cfg.Auth.APIKeysFile != "" {
+		fileStore, err := auth.NewFileAPIKeyStore(cfg.Auth.APIKeysFile, logrusLogger)
+		if err != nil {
+			logger.Fatalf("Failed to load API keys: %v", err)
+		}
+		apiKeyStore = fileStore
+		logger.Infof("Loaded API keys from %s", cfg.Auth.APIKeysFile)
+	} else {
+		apiKeyStore = newDemoAPIKeyStore(logrusLogger)
+		logger.Warnf("Using the built-in demo API keys, which expire in %s; set auth.apiKeysFile for real keys", demoAPIKeyTTL)
+	}
+	apiKeyAuth := auth.NewAPIKeyAuthenticator(apiKeyStore, logrusLogger)
+
+	// JWT authenticator
+	jwtAuth, err := newJWTAuthenticator(cfg.Auth.JWT, logrusLogger)
+	if err != nil {
+		logger.Fatalf("Failed to set up JWT authentication: %v", err)
+	}
+
+	// Multi-authenticator that tries API key first, then JWT
+	multiAuth := auth.NewMultiAuthenticator()
+	multiAuth.AddAuthenticator("apikey", apiKeyAuth)
+	multiAuth.AddAuthenticator("jwt", jwtAuth)
+
+	// Reload policies, file-backed API keys, and the log level on SIGHUP
+	go handleReloadSignals(rbacEnforcer, apiKeyStore, logger, logrusLogger)
+
+	// Initialize security middleware
+	securityMiddleware := security.NewSecurityMiddleware(multiAuth, rbacEnforcer, auditLogger, logrusLogger)
+	securityMiddleware.SetRateLimiter(newRateLimiter(cfg.RateLimit))
+
+	// Validate the configuration before serving, failing fast with a full report
+	runPreflight := func(ctx context.Context) *preflight.Report {
+		return preflight.Run(ctx, preflightChecks(cfg, k8sClient, auditLogger))
+	}
+	preflightCtx, cancelPreflight := context.WithTimeout(ctx, startupPreflightTimeout)
+	report := runPreflight(preflightCtx)
+	cancelPreflight()
+	for _, result := range report.Results {
+		switch result.Status {
+		case preflight.StatusPass:
+			logger.Infof("Preflight %s: ok", result.Name)
+		case preflight.StatusWarn:
+			logger.Warnf("Preflight %s: %s", result.Name, result.Message)
+		default:
+			logger.Errorf("Preflight %s: %s", result.Name, result.Message)
+		}
+	}
+	if !report.OK {
+		logger.Fatalf("Preflight checks failed: %s", report.Failures())
+	}
+
+	// Create original MCP server
+	mcpServer := mcp.NewServer(cfg, k8sClient)
+	mcpServer.SetPreflight(runPreflight)
+	if managedKeys, ok := apiKeyStore.(auth.ManagedAPIKeyStore); ok {
+		mcpServer.SetAPIKeyStore(managedKeys)
+	}
+	// Anyone can sign tokens with the public demo secret, so the server only issues
+	// tokens once a private secret is configured
+	if cfg.Auth.JWT.Algorithm == "HS256" && cfg.Auth.JWT.Secret != config.DemoJWTSecret {
+		mcpServer.SetTokenIssuer(jwtAuth, cfg.Auth.JWT.IssuedTokenTTL)
+	} else {
+		logger.Info("server_issue_token is disabled: it needs an HS256 secret other than the demo value")
+	}
+
+	// Wrap with security
+	secureMCPServer := mcp.NewSecureMCPServer(mcpServer, securityMiddleware, logrusLogger)
+
+	if cfg.GRPC.Enabled {
+		go startGRPCServer(secureMCPServer, cfg.GRPC, logger, logrusLogger)
+	}
+
+	// SIGINT and SIGTERM stop the MCP transports gracefully
+	serveCtx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch cfg.Server.Transport {
+	case config.TransportStdio:
+		if err := mcpServer.Start(serveCtx); err != nil {
+			logger.Fatalf("MCP server failed: %v", err)
+		}
+	case config.TransportHTTP, config.TransportSSE:
+		start := secureMCPServer.StartHTTP
+		if cfg.Server.Transport == config.TransportSSE {
+			start = secureMCPServer.StartSSE
+		}
+		if err := start(serveCtx, cfg.Server.Address); err != nil {
+			logger.Fatalf("MCP server failed: %v", err)
+		}
+	case config.TransportDemo:
+		// Start demo HTTP server for testing security features
+		startDemoHTTPServer(secureMCPServer, k8sClient.HealthCheck, security.CORSPolicy(cfg.Server.CORS), 8080, logger)
+	default:
+		logger.Fatalf("Unknown server transport %q: must be stdio, http, sse, or demo", cfg.Server.Transport)
+	}
+}
+
+// rbacPolicyPath is the RBAC policy file loaded at startup and on SIGHUP
+const rbacPolicyPath = "./configs/rbac-policies.yaml"
+
+// demoAPIKeyTTL is how long the built-in demo API keys last after startup
+const demoAPIKeyTTL = 24 * time.Hour
+
+// tracingShutdownTimeout bounds flushing buffered spans when the server stops
+const tracingShutdownTimeout = 5 * time.Second
+
+// startupHealthCheckTimeout bounds the check that the cluster is reachable at startup
+const startupHealthCheckTimeout = 5 * time.Second
+
+// startupPreflightTimeout bounds the preflight run at startup so a slow dependency
+// cannot hold the server back indefinitely
+const startupPreflightTimeout = 15 * time.Second
+
+const (
+	// informerResync is how often the informer cache replays its objects to handlers
+	informerResync = 10 * time.Minute
+	// informerSyncTimeout bounds the informer cache's initial list at startup
+	informerSyncTimeout = 30 * time.Second
+)
+
+// handleReloadSignals reloads runtime configuration each time the process receives SIGHUP.
+// Each part is reloaded independently and keeps its previous state when reloading fails.
+func handleReloadSignals(rbacEnforcer *rbac.RBACEnforcer, apiKeyStore auth.APIKeyStore, logger *logging.Logger, loggers ...*logrus.Logger) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+
+	for range sigChan {
+		logger.Info("Received SIGHUP, reloading configuration")
+
+		if policyData, err := os.ReadFile(rbacPolicyPath); err != nil {
+			logger.Errorf("RBAC policy reload failed, keeping current policy: %v", err)
+		} else if err := rbacEnforcer.LoadPolicy(policyData); err != nil {
+			logger.Errorf("RBAC policy reload failed, keeping current policy: %v", err)
+		} else {
+			logger.Infof("RBAC policy reloaded from %s", rbacPolicyPath)
+		}
+
+		if store, ok := apiKeyStore.(auth.ReloadableAPIKeyStore); ok {
+			if err := store.Reload(); err != nil {
+				logger.Errorf("API key store reload failed, keeping current keys: %v", err)
+			} else {
+				logger.Info("API key store reloaded")
+			}
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			logger.Errorf("Config reload failed, keeping current log level: %v", err)
+			continue
+		}
+		level, err := logrus.ParseLevel(cfg.Log.Level)
+		if err != nil {
+			logger.Errorf("Invalid log level %q, keeping current log level: %v", cfg.Log.Level, err)
+			continue
+		}
+		logger.SetLevel(level)
+		for _, l := range loggers {
+			l.SetLevel(level)
+		}
+		logger.Infof("Log level set to %s", level)
+	}
+}
+
+// preflightChecks lists the configuration checks run at startup and by the server_preflight tool
+func preflightChecks(cfg *config.Config, k8sClient *k8s.Client, auditLogger *audit.AuditLogger) []preflight.Check {
+	checks := []preflight.Check{
+		{
+			// Startup already requires the cluster, so this is a warning: from
+			// server_preflight it reports an outage alongside the other checks
+			Name:     "kubernetes-api",
+			Severity: preflight.SeverityWarning,
+			Run:      k8sClient.HealthCheck,
+		},
+		{
+			Name:     "rbac-policy",
+			Severity: preflight.SeverityError,
+			Run: func(ctx context.Context) error {
+				policyData, err := os.ReadFile(rbacPolicyPath)
+				if err != nil {
+					return fmt.Errorf("cannot read %s: %w", rbacPolicyPath, err)
+				}
+				_, err = rbac.ParsePolicy(policyData)
+				return err
+			},
+		},
+		{
+			// The demo secret is public; it is a warning so the demo setup still starts
+			Name:     "jwt-secret",
+			Severity: preflight.SeverityWarning,
+			Run: func(ctx context.Context) error {
+				if cfg.Auth.JWT.Algorithm != "HS256" {
+					return nil // verified with a public key; there is no secret to check
+				}
+				if cfg.Auth.JWT.Secret == config.DemoJWTSecret {
+					return fmt.Errorf("JWT signing secret is the public demo value; set auth.jwt.secret")
+				}
+				if len(cfg.Auth.JWT.Secret) < 32 {
+					return fmt.Errorf("JWT signing secret is shorter than 32 bytes")
+				}
+				return nil
+			},
+		},
+		{
+			Name:     "audit-sinks",
+			Severity: preflight.SeverityError,
+			Run:      auditLogger.CheckSinks,
+		},
+	}
+
+	if cfg.Auth.JWT.JWKSURL != "" {
+		checks = append(checks, preflight.Check{
+			Name:     "jwks",
+			Severity: preflight.SeverityError,
+			Run: func(ctx context.Context) error {
+				return auth.NewJWKSKeySet(cfg.Auth.JWT.JWKSURL, cfg.Auth.JWT.JWKSCacheTTL, logrus.StandardLogger()).Refresh(ctx)
+			},
+		})
+	}
+
+	if cfg.GRPC.Enabled && cfg.GRPC.CertFile != "" {
+		checks = append(checks, preflight.Check{
+			Name:     "grpc-tls",
+			Severity: preflight.SeverityError,
+			Run: func(ctx context.Context) error {
+				_, err := security.LoadTLSConfig(&security.TLSConfig{
+					CertFile: cfg.GRPC.CertFile,
+					KeyFile:  cfg.GRPC.KeyFile,
+					CAFile:   cfg.GRPC.ClientCAFile,
+				})
+				return err
+			},
+		})
+	}
+
+	return checks
+}
+
+// newDemoAPIKeyStore holds the demo API keys used when no API key file is configured.
+// They expire after demoAPIKeyTTL so a forgotten demo server does not accept the
+// published keys forever.
+func newDemoAPIKeyStore(logger *logrus.Logger) *auth.InMemoryAPIKeyStore {
+	expiresAt := time.Now().Add(demoAPIKeyTTL)
+	apiKeyStore := auth.NewInMemoryAPIKeyStore(logger)
 	apiKeyStore.AddAPIKey("demo-admin-key-67890", &auth.APIKeyInfo{
 		ID:   "admin-key",
 		Name: "Admin Key",
@@ -83,6 +413,7 @@ func main() {
 			"k8s:*", // Wildcard for admin access
 		},
 		CreatedAt: time.Now(),
+		ExpiresAt: &expiresAt,
 	})
 	apiKeyStore.AddAPIKey("demo-user-key-12345", &auth.APIKeyInfo{
 		ID:   "user-key",
@@ -93,120 +424,255 @@ func main() {
 			"k8s:deployments:list",
 		},
 		CreatedAt: time.Now(),
+		ExpiresAt: &expiresAt,
 	})
-	apiKeyAuth := auth.NewAPIKeyAuthenticator(apiKeyStore, logrusLogger)
+	return apiKeyStore
+}
 
-	// JWT authenticator with demo secret
-	jwtAuth := auth.NewJWTAuthenticator([]byte("demo-secret-key-for-jwt-signing-change-in-production"), logrusLogger)
+// newJWTAuthenticator builds the JWT authenticator for the configured algorithm: HS256
+// with the shared secret, or RS256/ES256 with keys from a JWKS URL or a PEM file
+func newJWTAuthenticator(jwtConfig config.JWTConfig, logger *logrus.Logger) (*auth.JWTAuthenticator, error) {
+	claims := auth.ClaimMapping{
+		IdentityClaim:    jwtConfig.IdentityClaim,
+		UserIDClaim:      jwtConfig.UserIDClaim,
+		PermissionsClaim: jwtConfig.PermissionsClaim,
+	}
 
-	// Multi-authenticator that tries API key first, then JWT
-	multiAuth := auth.NewMultiAuthenticator()
-	multiAuth.AddAuthenticator("apikey", apiKeyAuth)
-	multiAuth.AddAuthenticator("jwt", jwtAuth)
+	switch jwtConfig.Algorithm {
+	case "", "HS256":
+		return auth.NewJWTAuthenticatorWithClaims([]byte(jwtConfig.Secret), claims, logger), nil
+	case "RS256", "ES256":
+		if jwtConfig.JWKSURL != "" {
+			keySet := auth.NewJWKSKeySet(jwtConfig.JWKSURL, jwtConfig.JWKSCacheTTL, logger)
+			return auth.NewJWTAuthenticatorWithJWKS(jwtConfig.Algorithm, keySet, claims, logger)
+		}
+		if jwtConfig.PublicKeyFile == "" {
+			return nil, fmt.Errorf("auth.jwt.publicKeyFile or auth.jwt.jwksUrl is required for %s", jwtConfig.Algorithm)
+		}
+		keyData, err := os.ReadFile(jwtConfig.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read JWT public key: %w", err)
+		}
+		publicKey, err := auth.ParsePublicKeyPEM(keyData)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse JWT public key %s: %w", jwtConfig.PublicKeyFile, err)
+		}
+		return auth.NewJWTAuthenticatorWithPublicKey(jwtConfig.Algorithm, publicKey, claims, logger)
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q; use HS256, RS256 or ES256", jwtConfig.Algorithm)
+	}
+}
 
-	// Initialize security middleware
-	securityMiddleware := security.NewSecurityMiddleware(multiAuth, rbacEnforcer, auditLogger, logrusLogger)
+// newAuditDBSink connects to PostgreSQL and prepares the audit table
+// newRateLimiter builds the per-caller rate limiter from the rateLimit config section
+func newRateLimiter(rateConfig config.RateLimitConfig) *security.RateLimiter {
+	perPermission := make(map[rbac.Permission]security.RateLimit, len(rateConfig.Permissions))
+	for permission, rule := range rateConfig.Permissions {
+		perPermission[rbac.Permission(permission)] = security.RateLimit(rule)
+	}
+	return security.NewRateLimiter(security.RateLimit(rateConfig.Default), perPermission)
+}
 
-	// Create original MCP server
-	mcpServer := mcp.NewServer(cfg, k8sClient)
+func newAuditDBSink(ctx context.Context, dbConfig config.AuditDatabaseConfig, logger *logrus.Logger) (*audit.DBSink, error) {
+	db, err := sql.Open("postgres", dbConfig.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to audit database: %w", err)
+	}
 
-	// Wrap with security
-	secureMCPServer := mcp.NewSecureMCPServer(mcpServer, securityMiddleware, logrusLogger)
+	sink, err := audit.NewDBSink(db, audit.DBSinkConfig{
+		Table:         dbConfig.Table,
+		BatchSize:     dbConfig.BatchSize,
+		FlushInterval: dbConfig.FlushInterval,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := sink.EnsureSchema(ctx); err != nil {
+		return nil, err
+	}
+	return sink, nil
+}
+
+// startGRPCServer serves the gRPC tool transport, using TLS when a certificate is configured
+func startGRPCServer(server *mcp.SecureMCPServer, grpcConfig config.GRPCConfig, logger *logging.Logger, logrusLogger *logrus.Logger) {
+	var opts []grpc.ServerOption
+	if grpcConfig.CertFile != "" {
+		tlsConfig, err := security.LoadTLSConfig(&security.TLSConfig{
+			CertFile: grpcConfig.CertFile,
+			KeyFile:  grpcConfig.KeyFile,
+			CAFile:   grpcConfig.ClientCAFile,
+		})
+		if err != nil {
+			logger.Fatalf("Failed to load gRPC TLS configuration: %v", err)
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else {
+		logger.Warn("gRPC transport is running without TLS")
+	}
+
+	listener, err := net.Listen("tcp", grpcConfig.Address)
+	if err != nil {
+		logger.Fatalf("Failed to listen for gRPC on %s: %v", grpcConfig.Address, err)
+	}
+
+	logger.Infof("Starting gRPC transport on %s", grpcConfig.Address)
+	if err := mcp.NewGRPCServer(server, logrusLogger, opts...).Serve(listener); err != nil {
+		logger.Fatalf("gRPC server failed: %v", err)
+	}
+}
+
+// toolResponse is the JSON envelope of every /mcp/tools response
+type toolResponse struct {
+	Success bool                   `json:"success"`
+	Result  map[string]interface{} `json:"result,omitempty"`
+	Error   *types.MCPError        `json:"error,omitempty"`
+}
+
+func writeToolError(w http.ResponseWriter, status int, err *types.MCPError) {
+	writeToolResponse(w, status, toolResponse{Error: err})
+}
+
+// writeToolResponse writes response as JSON with an exact Content-Length
+func writeToolResponse(w http.ResponseWriter, status int, response toolResponse) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		status = http.StatusInternalServerError
+		body, _ = json.Marshal(toolResponse{
+			Error: types.NewError(types.ErrorCodeInternalError, "failed to encode tool result: %v", err),
+		})
+	}
+	body = append(body, '\n')
 
-	// Start demo HTTP server for testing security features
-	// In production, you would integrate with the actual MCP protocol transport
-	startDemoHTTPServer(secureMCPServer, 8080, logger)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	w.Write(body)
 }
 
-func startDemoHTTPServer(server *mcp.SecureMCPServer, port int, logger *logging.Logger) {
+// maxToolRequestBytes bounds the JSON body of a demo tool call
+const maxToolRequestBytes = 4 << 20
+
+// toolArguments reads the arguments of a demo tool call. A JSON object in the body is
+// used as the arguments, so every tool can be called, including those taking maps or
+// lists; simple arguments may also be given as query parameters, which fill in any the
+// body leaves out.
+func toolArguments(w http.ResponseWriter, r *http.Request) (map[string]interface{}, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxToolRequestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %v", err)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return queryArguments(r.URL.Query()), nil
+	}
+
+	var arguments map[string]interface{}
+	if err := json.Unmarshal(body, &arguments); err != nil || arguments == nil {
+		return nil, fmt.Errorf("request body must be a JSON object of tool arguments")
+	}
+	for key, value := range queryArguments(r.URL.Query()) {
+		if _, ok := arguments[key]; !ok && r.URL.Query().Has(key) {
+			arguments[key] = value
+		}
+	}
+	return arguments, nil
+}
+
+// queryArguments builds tool arguments from the query string, for simple calls made
+// without a body. The namespace defaults to "default".
+func queryArguments(query url.Values) map[string]interface{} {
+	arguments := map[string]interface{}{
+		"namespace": query.Get("namespace"),
+	}
+	if arguments["namespace"] == "" {
+		arguments["namespace"] = "default"
+	}
+
+	// Parse additional tool-specific parameters from query string
+	if name := query.Get("name"); name != "" {
+		arguments["name"] = name
+	}
+	if replicasStr := query.Get("replicas"); replicasStr != "" {
+		// Convert replicas to integer
+		if replicas, err := strconv.Atoi(replicasStr); err == nil {
+			arguments["replicas"] = replicas
+		} else {
+			arguments["replicas"] = replicasStr // Keep as string for validation error
+		}
+	}
+	if container := query.Get("container"); container != "" {
+		arguments["container"] = container
+	}
+	if confirmStr := query.Get("confirm"); confirmStr != "" {
+		// Convert confirm to boolean
+		if confirm, err := strconv.ParseBool(confirmStr); err == nil {
+			arguments["confirm"] = confirm
+		} else {
+			arguments["confirm"] = confirmStr // Keep as string for validation error
+		}
+	}
+	return arguments
+}
+
+// healthCheckTimeout bounds the cluster check behind /health and /readyz, so probes get
+// an answer before their own timeout
+const healthCheckTimeout = 3 * time.Second
+
+func startDemoHTTPServer(server *mcp.SecureMCPServer, healthCheck func(context.Context) error, cors security.CORSPolicy, port int, logger *logging.Logger) {
 	mux := http.NewServeMux()
 
-	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// Health and readiness endpoints, failing while the cluster is unreachable
+	mux.HandleFunc("/health", healthHandler(healthCheck, logger))
+	mux.HandleFunc("/readyz", healthHandler(healthCheck, logger))
 
 	// MCP tool execution endpoint
 	mux.HandleFunc("/mcp/tools", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			w.Header().Set("Allow", http.MethodPost)
+			writeToolError(w, http.StatusMethodNotAllowed, types.NewError(types.ErrorCodeInvalidRequest, "method not allowed"))
 			return
 		}
 
 		// Extract tool name and arguments from request
 		toolName := r.URL.Query().Get("tool")
 		if toolName == "" {
-			http.Error(w, "Missing tool parameter", http.StatusBadRequest)
+			writeToolError(w, http.StatusBadRequest, types.NewError(types.ErrorCodeInvalidRequest, "missing tool parameter"))
 			return
 		}
 
-		// Create context with headers for authentication
-		ctx := context.WithValue(r.Context(), mcp.HeadersContextKey, map[string]string{
+		// Create context with headers for authentication, continuing the caller's trace
+		ctx := context.WithValue(tracing.Extract(r.Context(), r.Header), mcp.HeadersContextKey, map[string]string{
 			"Authorization": r.Header.Get("Authorization"),
 		})
 
-		// Demo arguments (in production, parse from request body)
-		arguments := map[string]interface{}{
-			"namespace": r.URL.Query().Get("namespace"),
-		}
-		if arguments["namespace"] == "" {
-			arguments["namespace"] = "default"
-		}
-
-		// Parse additional tool-specific parameters from query string
-		if name := r.URL.Query().Get("name"); name != "" {
-			arguments["name"] = name
-		}
-		if replicasStr := r.URL.Query().Get("replicas"); replicasStr != "" {
-			// Convert replicas to integer
-			if replicas, err := strconv.Atoi(replicasStr); err == nil {
-				arguments["replicas"] = replicas
-			} else {
-				arguments["replicas"] = replicasStr // Keep as string for validation error
-			}
-		}
-		if container := r.URL.Query().Get("container"); container != "" {
-			arguments["container"] = container
-		}
-		if confirmStr := r.URL.Query().Get("confirm"); confirmStr != "" {
-			// Convert confirm to boolean
-			if confirm, err := strconv.ParseBool(confirmStr); err == nil {
-				arguments["confirm"] = confirm
-			} else {
-				arguments["confirm"] = confirmStr // Keep as string for validation error
-			}
+		arguments, err := toolArguments(w, r)
+		if err != nil {
+			writeToolError(w, http.StatusBadRequest, types.NewError(types.ErrorCodeInvalidRequest, "%v", err))
+			return
 		}
 
 		// Execute tool through secure server
 		result, err := server.HandleToolCall(ctx, toolName, arguments)
 		if err != nil {
-			// Determine appropriate HTTP status code based on error type
-			statusCode := http.StatusInternalServerError
-			errorMessage := err.Error()
-
-			// Check for specific error types
-			if strings.Contains(errorMessage, "authentication failed") {
-				statusCode = http.StatusUnauthorized
-			} else if strings.Contains(errorMessage, "access denied") || strings.Contains(errorMessage, "authorization failed") {
-				statusCode = http.StatusForbidden
-			} else if strings.Contains(errorMessage, "validation failed") || strings.Contains(errorMessage, "missing") {
-				statusCode = http.StatusBadRequest
+			var mcpErr *types.MCPError
+			if !errors.As(err, &mcpErr) {
+				mcpErr = types.NewError(types.ErrorCodeInternalError, "%v", err)
 			}
-
-			http.Error(w, fmt.Sprintf("Tool execution failed: %v", err), statusCode)
+			if retryAfter := mcpErr.Data["retryAfterSeconds"]; retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			writeToolError(w, httpStatus(mcpErr.Code), mcpErr)
 			return
 		}
 
-		// Return result (simplified - in production use proper JSON encoding)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, `{"success": true, "result": %v}`, result)
+		writeToolResponse(w, http.StatusOK, toolResponse{Success: true, Result: result})
 	})
 
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      mux,
+		Handler:      cors.Handler(mux),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
@@ -245,3 +711,44 @@ func startDemoHTTPServer(server *mcp.SecureMCPServer, port int, logger *logging.
 
 	logger.Info("Server shutdown complete")
 }
+
+// healthHandler reports whether the server can reach the cluster: 200 and "OK" if so,
+// otherwise 503 with the error
+func healthHandler(healthCheck func(context.Context) error, logger *logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := healthCheck(ctx); err != nil {
+			logger.WithError(err).Warnf("Health check on %s failed", r.URL.Path)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
+
+// httpStatus maps the error code of a failed tool call to the demo endpoint's HTTP status
+func httpStatus(code int) int {
+	switch code {
+	case types.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case types.ErrorCodeForbidden:
+		return http.StatusForbidden
+	case types.ErrorCodeInvalidParams, types.ErrorCodeInvalidRequest, types.ErrorCodeMethodNotFound:
+		return http.StatusBadRequest
+	case types.ErrorCodeResourceNotFound:
+		return http.StatusNotFound
+	case types.ErrorCodeTimeout:
+		return http.StatusGatewayTimeout
+	case types.ErrorCodeClusterUnavailable:
+		return http.StatusServiceUnavailable
+	case types.ErrorCodeRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
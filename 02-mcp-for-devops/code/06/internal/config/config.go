@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,23 +12,189 @@ type Config struct {
 	Server ServerConfig `yaml:"server"`
 	K8s    K8sConfig    `yaml:"kubernetes"`
 	Log    LogConfig    `yaml:"logging"`
+	Auth   AuthConfig   `yaml:"auth"`
+	GRPC   GRPCConfig   `yaml:"grpc"`
+	Audit  AuditConfig  `yaml:"audit"`
+	// RateLimit limits how often each authenticated caller may call tools
+	RateLimit RateLimitConfig `yaml:"rateLimit"`
+	Tracing   TracingConfig   `yaml:"tracing"`
 }
 
 type ServerConfig struct {
 	Name        string `yaml:"name"`
 	Version     string `yaml:"version"`
 	Description string `yaml:"description"`
+	// Transport selects how MCP clients connect: "stdio", "http" (streamable HTTP),
+	// "sse", or "demo" for the simplified HTTP tool endpoint
+	Transport string `yaml:"transport"`
+	// Address is the listen address of the http and sse transports
+	Address string `yaml:"address"`
+	// CORS lets browser clients on other origins call the http, sse and demo transports
+	CORS CORSConfig `yaml:"cors"`
+	// ResourceCache keeps resource reads for a few seconds
+	ResourceCache ResourceCacheConfig `yaml:"resourceCache"`
 }
 
+// ResourceCacheConfig sizes the cache of resource reads. A zero TTL or MaxEntries turns
+// it off.
+type ResourceCacheConfig struct {
+	TTL        time.Duration `yaml:"ttl"`
+	MaxEntries int           `yaml:"maxEntries"`
+	// StatsInterval is how often the cache's counters are logged while the server runs.
+	// Zero logs them only when the server stops.
+	StatsInterval time.Duration `yaml:"statsInterval"`
+}
+
+// CORSConfig lists the web origins allowed to call the HTTP transports from a browser,
+// and the methods and headers they may use. No origins means same-origin only.
+type CORSConfig struct {
+	AllowedOrigins []string      `yaml:"allowedOrigins"`
+	AllowedMethods []string      `yaml:"allowedMethods"`
+	AllowedHeaders []string      `yaml:"allowedHeaders"`
+	MaxAge         time.Duration `yaml:"maxAge"`
+}
+
+// Transports accepted in ServerConfig.Transport
+const (
+	TransportStdio = "stdio"
+	TransportHTTP  = "http"
+	TransportSSE   = "sse"
+	TransportDemo  = "demo"
+)
+
 type K8sConfig struct {
-	ConfigPath string   `yaml:"configPath"`
-	Context    string   `yaml:"context"`
+	ConfigPath string `yaml:"configPath"`
+	Context    string `yaml:"context"`
+	// Namespaces, when non-empty, restricts tools and resources to these namespaces.
+	// Requests naming any other namespace are rejected, and sweeps across namespaces
+	// only visit these.
 	Namespaces []string `yaml:"namespaces"`
+	// InferNamespace lets tool calls omit the namespace when the named resource
+	// exists in exactly one of Namespaces
+	InferNamespace bool `yaml:"inferNamespace"`
+	// ExecAllowedCommands lists the executables k8s_exec_pod may run. Listing a shell
+	// such as sh effectively allows any command.
+	ExecAllowedCommands []string `yaml:"execAllowedCommands"`
+	// LogSidecarContainers names sidecar containers, such as service mesh proxies, that
+	// log tools skip when no container is named
+	LogSidecarContainers []string `yaml:"logSidecarContainers"`
+	// ToolTimeout bounds each tool call, e.g. "45s". Zero keeps the default of 30s.
+	ToolTimeout time.Duration `yaml:"toolTimeout"`
+	// LogToolTimeout replaces ToolTimeout for log retrieval, which can take longer on
+	// chatty pods. Zero keeps the default of 2m.
+	LogToolTimeout time.Duration `yaml:"logToolTimeout"`
+	// InformerCache serves pod, service and deployment reads from a local cache kept
+	// current by watches, instead of asking the API server on every call. It needs
+	// permission to list and watch those resources in every namespace.
+	InformerCache bool `yaml:"informerCache"`
 }
 
 type LogConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
+	// SensitiveKeyPattern is a regular expression of argument and metadata keys whose
+	// values are masked in logs and audit events. Empty keeps the built-in pattern.
+	SensitiveKeyPattern string `yaml:"sensitiveKeyPattern"`
+}
+
+type AuthConfig struct {
+	JWT JWTConfig `yaml:"jwt"`
+	// APIKeysFile is a YAML or JSON file of API keys. When set it replaces the built-in
+	// demo keys.
+	APIKeysFile string `yaml:"apiKeysFile"`
+}
+
+// DemoJWTSecret is the default JWT signing secret. It is public, so preflight flags it.
+const DemoJWTSecret = "demo-secret-key-for-jwt-signing-change-in-production"
+
+// JWTConfig selects how tokens are verified and names the token claims holding the
+// caller's identity and permissions. Dotted paths reach into nested claims, e.g.
+// "realm_access.roles". With Algorithm HS256 tokens are verified with the shared Secret;
+// with RS256 or ES256 they are verified with the PEM public key in PublicKeyFile, or with
+// keys downloaded from JWKSURL and cached for JWKSCacheTTL.
+type JWTConfig struct {
+	Algorithm        string        `yaml:"algorithm"`
+	Secret           string        `yaml:"secret"`
+	PublicKeyFile    string        `yaml:"publicKeyFile"`
+	JWKSURL          string        `yaml:"jwksUrl"`
+	JWKSCacheTTL     time.Duration `yaml:"jwksCacheTTL"`
+	IdentityClaim    string        `yaml:"identityClaim"`
+	UserIDClaim      string        `yaml:"userIdClaim"`
+	PermissionsClaim string        `yaml:"permissionsClaim"`
+	// IssuedTokenTTL is the lifetime of tokens minted by server_issue_token when the call
+	// names none
+	IssuedTokenTTL time.Duration `yaml:"issuedTokenTTL"`
+}
+
+// GRPCConfig controls the optional gRPC transport. When ClientCAFile is set, clients must
+// present a certificate signed by that CA (mTLS).
+type GRPCConfig struct {
+	Enabled      bool   `yaml:"enabled"`
+	Address      string `yaml:"address"`
+	CertFile     string `yaml:"certFile"`
+	KeyFile      string `yaml:"keyFile"`
+	ClientCAFile string `yaml:"clientCAFile"`
+}
+
+type AuditConfig struct {
+	Database AuditDatabaseConfig `yaml:"database"`
+	File     AuditFileConfig     `yaml:"file"`
+	Webhook  AuditWebhookConfig  `yaml:"webhook"`
+}
+
+// AuditFileConfig writes audit events to a dedicated, rotated file at Path instead of
+// the application log. Rotated files are kept forever unless MaxAge or MaxBackups is set.
+type AuditFileConfig struct {
+	Path        string        `yaml:"path"`
+	MaxSizeMB   int           `yaml:"maxSizeMB"`
+	RotateEvery time.Duration `yaml:"rotateEvery"`
+	MaxAge      time.Duration `yaml:"maxAge"`
+	MaxBackups  int           `yaml:"maxBackups"`
+	Compress    bool          `yaml:"compress"`
+}
+
+// AuditDatabaseConfig enables writing audit events to PostgreSQL when DSN is set
+type AuditDatabaseConfig struct {
+	DSN           string        `yaml:"dsn"`
+	Table         string        `yaml:"table"`
+	BatchSize     int           `yaml:"batchSize"`
+	FlushInterval time.Duration `yaml:"flushInterval"`
+}
+
+// RateLimitConfig sets a token bucket per caller. Default applies to every permission
+// not listed in Permissions; a listed permission, such as "k8s:deployments:scale", gets
+// a bucket of its own so stricter limits can be put on mutating actions.
+type RateLimitConfig struct {
+	Default     RateLimitRule            `yaml:"default"`
+	Permissions map[string]RateLimitRule `yaml:"permissions"`
+}
+
+// RateLimitRule allows Burst requests at once, refilled at RequestsPerSecond. A
+// RequestsPerSecond of zero disables the limit.
+type RateLimitRule struct {
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	Burst             int     `yaml:"burst"`
+}
+
+// AuditWebhookConfig ships audit events to a SIEM or log intake when URL is set. Each
+// event is POSTed as JSON with Headers added, retried up to MaxRetries times.
+type AuditWebhookConfig struct {
+	URL        string            `yaml:"url"`
+	Headers    map[string]string `yaml:"headers"`
+	Timeout    time.Duration     `yaml:"timeout"`
+	MaxRetries int               `yaml:"maxRetries"`
+	BufferSize int               `yaml:"bufferSize"`
+}
+
+// TracingConfig exports OpenTelemetry spans to an OTLP collector at Endpoint, a host:port,
+// over Protocol "grpc" or "http". SampleRatio is the share of new traces recorded; calls
+// that arrive with a sampled trace context are always recorded.
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"`
+	Protocol    string  `yaml:"protocol"`
+	Insecure    bool    `yaml:"insecure"`
+	SampleRatio float64 `yaml:"sampleRatio"`
 }
 
 func Load() (*Config, error) {
@@ -36,15 +203,62 @@ func Load() (*Config, error) {
 			Name:        "k8s-mcp-server",
 			Version:     "1.0.0",
 			Description: "Kubernetes MCP Server for AI-powered cluster management",
+			Transport:   TransportDemo,
+			Address:     ":8080",
+			CORS: CORSConfig{
+				AllowedMethods: []string{"GET", "POST", "DELETE", "OPTIONS"},
+				AllowedHeaders: []string{"Authorization", "Content-Type", "Mcp-Session-Id", "Mcp-Protocol-Version", "Last-Event-ID", "traceparent", "tracestate"},
+				MaxAge:         10 * time.Minute,
+			},
+			ResourceCache: ResourceCacheConfig{
+				TTL:           5 * time.Second,
+				MaxEntries:    256,
+				StatsInterval: 5 * time.Minute,
+			},
 		},
 		K8s: K8sConfig{
 			ConfigPath: filepath.Join(os.Getenv("HOME"), ".kube", "config"),
-			Namespaces: []string{"default"},
+			ExecAllowedCommands: []string{
+				"cat", "ls", "df", "ps", "hostname", "date", "uname", "id", "nslookup", "getent",
+			},
+			LogSidecarContainers: []string{"istio-proxy", "linkerd-proxy"},
 		},
 		Log: LogConfig{
 			Level:  "info",
 			Format: "json",
 		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				Algorithm:        "HS256",
+				Secret:           DemoJWTSecret,
+				JWKSCacheTTL:     10 * time.Minute,
+				IssuedTokenTTL:   time.Hour,
+				IdentityClaim:    "username",
+				UserIDClaim:      "user_id",
+				PermissionsClaim: "permissions",
+			},
+		},
+		GRPC: GRPCConfig{
+			Address: ":9090",
+		},
+		Audit: AuditConfig{
+			Database: AuditDatabaseConfig{
+				Table:         "audit_events",
+				BatchSize:     100,
+				FlushInterval: 2 * time.Second,
+			},
+			File: AuditFileConfig{
+				MaxSizeMB: 100,
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Default: RateLimitRule{RequestsPerSecond: 10, Burst: 20},
+		},
+		Tracing: TracingConfig{
+			Endpoint:    "localhost:4317",
+			Protocol:    "grpc",
+			SampleRatio: 1,
+		},
 	}
 
 	if configFile := os.Getenv("CONFIG_FILE"); configFile != "" {
@@ -57,5 +271,11 @@ func Load() (*Config, error) {
 		}
 	}
 
+	// The signing secret may come from the environment, so it needn't be written to the
+	// config file
+	if secret := os.Getenv("MCP_JWT_SECRET"); secret != "" {
+		cfg.Auth.JWT.Secret = secret
+	}
+
 	return cfg, nil
 }
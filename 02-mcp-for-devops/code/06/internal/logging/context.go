@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random ID for correlating the logs and audit events of one request
+func NewRequestID() string {
+	return uuid.NewString()
+}
+
+// WithRequestID returns ctx carrying the request ID id
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID in ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestFields adds the request ID in ctx, if any, to fields
+func RequestFields(ctx context.Context, fields logrus.Fields) logrus.Fields {
+	if id := RequestID(ctx); id != "" {
+		fields["request_id"] = id
+	}
+	return fields
+}
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"context"
 	"os"
 	"time"
 
@@ -38,23 +39,27 @@ func NewLogger(level, format string) *Logger {
 	return &Logger{Logger: logger}
 }
 
-// LogMCPRequest logs MCP requests with context
-func (l *Logger) LogMCPRequest(method, uri string, params interface{}) {
-	l.WithFields(logrus.Fields{
+// LogMCPRequest logs MCP requests with context. For a tool call, uri is the tool name and
+// params its arguments, which are redacted with RedactArguments.
+func (l *Logger) LogMCPRequest(ctx context.Context, method, uri string, params interface{}) {
+	if arguments, ok := params.(map[string]interface{}); ok {
+		params = RedactArguments(uri, arguments)
+	}
+	l.WithFields(RequestFields(ctx, logrus.Fields{
 		"component": "mcp",
 		"method":    method,
 		"uri":       uri,
 		"params":    params,
-	}).Info("Processing MCP request")
+	})).Info("Processing MCP request")
 }
 
 // LogMCPResponse logs MCP responses with timing
-func (l *Logger) LogMCPResponse(method string, duration time.Duration, err error) {
-	fields := logrus.Fields{
+func (l *Logger) LogMCPResponse(ctx context.Context, method string, duration time.Duration, err error) {
+	fields := RequestFields(ctx, logrus.Fields{
 		"component": "mcp",
 		"method":    method,
 		"duration":  duration.String(),
-	}
+	})
 
 	if err != nil {
 		l.WithFields(fields).WithError(err).Error("MCP request failed")
@@ -64,14 +69,14 @@ func (l *Logger) LogMCPResponse(method string, duration time.Duration, err error
 }
 
 // LogK8sOperation logs Kubernetes operations
-func (l *Logger) LogK8sOperation(operation, namespace, resource string, duration time.Duration, err error) {
-	fields := logrus.Fields{
+func (l *Logger) LogK8sOperation(ctx context.Context, operation, namespace, resource string, duration time.Duration, err error) {
+	fields := RequestFields(ctx, logrus.Fields{
 		"component": "kubernetes",
 		"operation": operation,
 		"namespace": namespace,
 		"resource":  resource,
 		"duration":  duration.String(),
-	}
+	})
 
 	if err != nil {
 		l.WithFields(fields).WithError(err).Error("Kubernetes operation failed")
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// RedactedValue replaces masked values in logs and audit events
+const RedactedValue = "[REDACTED]"
+
+// DefaultSensitiveKeyPattern matches argument and metadata keys whose values are masked
+// wherever they appear, such as a ConfigMap key named DB_PASSWORD
+const DefaultSensitiveKeyPattern = `(?i)(password|passwd|secret|token|api[-_]?key|credential|private[-_]?key)`
+
+// sensitiveToolArguments lists, per tool, arguments whose values are always masked. Map
+// values keep their keys, so logs still show which keys were sent.
+var sensitiveToolArguments = map[string]map[string]bool{
+	"k8s_create_secret": {"data": true, "binaryData": true},
+}
+
+var sensitiveKeys atomic.Pointer[regexp.Regexp]
+
+func init() {
+	sensitiveKeys.Store(regexp.MustCompile(DefaultSensitiveKeyPattern))
+}
+
+// SetSensitiveKeyPattern replaces DefaultSensitiveKeyPattern as the pattern of keys
+// whose values are masked
+func SetSensitiveKeyPattern(pattern string) error {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid sensitive key pattern %q: %w", pattern, err)
+	}
+	sensitiveKeys.Store(compiled)
+	return nil
+}
+
+// RedactArguments returns a copy of a tool call's arguments that is safe to log: the
+// tool's sensitive arguments and every value under a sensitive key are masked. Arguments
+// of the operations of a batch are masked by their own tool's rules.
+func RedactArguments(toolName string, arguments map[string]interface{}) map[string]interface{} {
+	if arguments == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(arguments))
+	for key, value := range arguments {
+		if sensitiveToolArguments[toolName][key] {
+			redacted[key] = maskValue(value)
+		} else {
+			redacted[key] = redactValue(key, value)
+		}
+	}
+	return redacted
+}
+
+// RedactFields returns a copy of fields, such as audit event metadata, with every value
+// under a sensitive key masked
+func RedactFields(fields map[string]interface{}) map[string]interface{} {
+	return RedactArguments("", fields)
+}
+
+// redactValue masks value if key is sensitive, and otherwise redacts within it
+func redactValue(key string, value interface{}) interface{} {
+	if sensitiveKeys.Load().MatchString(key) {
+		return RedactedValue
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// An operation of a batch: {"tool": ..., "arguments": {...}}
+		if tool, ok := v["tool"].(string); ok {
+			if arguments, ok := v["arguments"].(map[string]interface{}); ok {
+				operation := RedactArguments("", v)
+				operation["arguments"] = RedactArguments(tool, arguments)
+				return operation
+			}
+		}
+		return RedactArguments("", v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = redactValue("", item)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// maskValue masks every value in value, keeping the keys of a map
+func maskValue(value interface{}) interface{} {
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return RedactedValue
+	}
+	masked := make(map[string]interface{}, len(entries))
+	for key := range entries {
+		masked[key] = RedactedValue
+	}
+	return masked
+}
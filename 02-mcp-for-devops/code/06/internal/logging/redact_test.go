@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactArguments(t *testing.T) {
+	secret := map[string]interface{}{
+		"namespace": "default",
+		"name":      "db-credentials",
+		"data":      map[string]interface{}{"username": "admin", "password": "hunter2"},
+	}
+	configMap := map[string]interface{}{
+		"namespace": "default",
+		"name":      "app-config",
+		"data":      map[string]interface{}{"LOG_LEVEL": "debug", "DB_PASSWORD": "hunter3", "api_key": "abc123"},
+	}
+	batch := map[string]interface{}{
+		"operations": []interface{}{
+			map[string]interface{}{"tool": "k8s_create_secret", "arguments": secret},
+		},
+	}
+
+	for _, tt := range []struct {
+		tool      string
+		arguments map[string]interface{}
+	}{
+		{"k8s_create_secret", secret},
+		{"k8s_create_configmap", configMap},
+		{"k8s_batch", batch},
+	} {
+		logged := fmt.Sprint(RedactArguments(tt.tool, tt.arguments))
+		for _, value := range []string{"admin", "hunter2", "hunter3", "abc123"} {
+			if strings.Contains(logged, value) {
+				t.Errorf("%s: logged arguments contain %q: %s", tt.tool, value, logged)
+			}
+		}
+		if !strings.Contains(logged, "default") {
+			t.Errorf("%s: logged arguments lost the namespace: %s", tt.tool, logged)
+		}
+	}
+
+	redacted := RedactArguments("k8s_create_configmap", configMap)
+	if data := redacted["data"].(map[string]interface{}); data["LOG_LEVEL"] != "debug" || data["DB_PASSWORD"] != RedactedValue {
+		t.Errorf("only sensitive ConfigMap keys should be masked, got %v", data)
+	}
+	if configMap["data"].(map[string]interface{})["DB_PASSWORD"] != "hunter3" {
+		t.Error("redaction modified the arguments themselves")
+	}
+}
+
+func TestSetSensitiveKeyPattern(t *testing.T) {
+	defer SetSensitiveKeyPattern(DefaultSensitiveKeyPattern)
+
+	if err := SetSensitiveKeyPattern("(?i)^pin$"); err != nil {
+		t.Fatal(err)
+	}
+	redacted := RedactFields(map[string]interface{}{"pin": "1234", "password": "kept"})
+	if redacted["pin"] != RedactedValue || redacted["password"] != "kept" {
+		t.Errorf("got %v, want only pin masked", redacted)
+	}
+	if err := SetSensitiveKeyPattern("("); err == nil {
+		t.Error("accepted an invalid pattern")
+	}
+}
@@ -0,0 +1,55 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWrapTransportTracesRequestsWithinATrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+
+	// A request outside any trace is passed through untraced
+	get := func(ctx context.Context) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/pods", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	get(context.Background())
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "HandleToolCall")
+	get(ctx)
+	parent.End()
+
+	if len(traceparents) != 2 || traceparents[0] != "" || traceparents[1] == "" {
+		t.Fatalf("traceparent headers sent: %q", traceparents)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected the API call and its parent span, got %d spans", len(spans))
+	}
+	apiCall := spans[0]
+	if apiCall.Name() != "kubernetes GET" || apiCall.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("unexpected API call span %q with parent %s", apiCall.Name(), apiCall.Parent().SpanID())
+	}
+}
@@ -0,0 +1,224 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultDBTable         = "audit_events"
+	defaultDBBatchSize     = 100
+	defaultDBFlushInterval = 2 * time.Second
+	defaultDBBufferSize    = 10000
+	dbColumnsPerEvent      = 12
+)
+
+var tableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// DBSinkConfig controls where and how often a DBSink writes
+type DBSinkConfig struct {
+	Table         string
+	BatchSize     int
+	FlushInterval time.Duration
+	BufferSize    int
+}
+
+// DBSink writes audit events to a SQL table with batched multi-row inserts. Events are
+// queued in memory and flushed when a batch fills or FlushInterval elapses; when the
+// queue is full new events are dropped rather than blocking requests. The statements
+// use PostgreSQL syntax.
+type DBSink struct {
+	db     *sql.DB
+	config DBSinkConfig
+	logger *logrus.Logger
+
+	events    chan *AuditEvent
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewDBSink starts a sink that writes to db. Zero config values are replaced with defaults.
+func NewDBSink(db *sql.DB, config DBSinkConfig, logger *logrus.Logger) (*DBSink, error) {
+	if config.Table == "" {
+		config.Table = defaultDBTable
+	}
+	if !tableNamePattern.MatchString(config.Table) {
+		return nil, fmt.Errorf("invalid audit table name %q", config.Table)
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultDBBatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaultDBFlushInterval
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultDBBufferSize
+	}
+
+	s := &DBSink{
+		db:     db,
+		config: config,
+		logger: logger,
+		events: make(chan *AuditEvent, config.BufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// EnsureSchema creates the audit table and its indexes if they do not exist
+func (s *DBSink) EnsureSchema(ctx context.Context) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	event_id      TEXT PRIMARY KEY,
+	timestamp     TIMESTAMPTZ NOT NULL,
+	event_type    TEXT NOT NULL,
+	request_id    TEXT,
+	user_name     TEXT NOT NULL,
+	action        TEXT NOT NULL,
+	resource      TEXT,
+	namespace     TEXT,
+	result        TEXT NOT NULL,
+	error_message TEXT,
+	metadata      JSONB,
+	duration_ms   BIGINT
+)`, s.config.Table),
+		// Tables created before request IDs were recorded lack the column
+		fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS request_id TEXT`, s.config.Table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_request_idx ON %[1]s (request_id)`, s.config.Table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_user_idx ON %[1]s (user_name, timestamp)`, s.config.Table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_action_idx ON %[1]s (action, timestamp)`, s.config.Table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %[1]s_timestamp_idx ON %[1]s (timestamp)`, s.config.Table),
+	}
+
+	for _, statement := range statements {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create audit schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// Check verifies the audit table accepts inserts by writing a probe row inside a
+// transaction that is rolled back
+func (s *DBSink) Check(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		"INSERT INTO %s (event_id, timestamp, event_type, user_name, action, result) VALUES ($1, $2, 'preflight', 'preflight', 'preflight', 'success')",
+		s.config.Table), "preflight_"+generateEventID(), time.Now())
+	return err
+}
+
+// Write queues an event for the next batch
+func (s *DBSink) Write(ctx context.Context, event *AuditEvent) error {
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return fmt.Errorf("audit database queue is full, dropping event %s", event.EventID)
+	}
+}
+
+// Close flushes queued events and stops the background writer
+func (s *DBSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.events)
+		<-s.done
+	})
+	return nil
+}
+
+func (s *DBSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*AuditEvent, 0, s.config.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.insert(context.Background(), batch); err != nil {
+			s.logger.WithError(err).WithField("events", len(batch)).Error("Failed to write audit events to database")
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= s.config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// insert writes a batch with a single multi-row INSERT
+func (s *DBSink) insert(ctx context.Context, batch []*AuditEvent) error {
+	var query strings.Builder
+	fmt.Fprintf(&query, "INSERT INTO %s (event_id, timestamp, event_type, request_id, user_name, action, resource, namespace, result, error_message, metadata, duration_ms) VALUES ", s.config.Table)
+
+	args := make([]interface{}, 0, len(batch)*dbColumnsPerEvent)
+	for i, event := range batch {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for col := 0; col < dbColumnsPerEvent; col++ {
+			if col > 0 {
+				query.WriteString(", ")
+			}
+			fmt.Fprintf(&query, "$%d", i*dbColumnsPerEvent+col+1)
+		}
+		query.WriteString(")")
+
+		var metadata []byte
+		if len(event.Metadata) > 0 {
+			var err error
+			if metadata, err = json.Marshal(event.Metadata); err != nil {
+				return fmt.Errorf("failed to encode metadata for event %s: %w", event.EventID, err)
+			}
+		}
+
+		args = append(args,
+			event.EventID,
+			event.Timestamp,
+			event.EventType,
+			event.RequestID,
+			event.User,
+			event.Action,
+			event.Resource,
+			event.Namespace,
+			event.Result,
+			event.ErrorMessage,
+			metadata,
+			event.Duration.Milliseconds(),
+		)
+	}
+	query.WriteString(" ON CONFLICT (event_id) DO NOTHING")
+
+	_, err := s.db.ExecContext(ctx, query.String(), args...)
+	return err
+}
@@ -0,0 +1,110 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const defaultAuditFileMaxSizeMB = 100
+
+// RotationPolicy controls when an audit file is rotated and how many rotated files are
+// kept. Zero MaxAge and MaxBackups keep every rotated file, which is usually what an
+// audit trail needs.
+type RotationPolicy struct {
+	// MaxSizeMB rotates the file once it reaches this size. Zero means 100 MB.
+	MaxSizeMB int
+	// RotateEvery also rotates the file on a fixed interval, e.g. 24h. Zero disables
+	// time-based rotation.
+	RotateEvery time.Duration
+	// MaxAge removes rotated files older than this
+	MaxAge time.Duration
+	// MaxBackups removes the oldest rotated files beyond this count
+	MaxBackups int
+	// Compress gzips rotated files
+	Compress bool
+}
+
+// RotatingFile is an append-only file that rotates itself according to a RotationPolicy.
+// Rotated files are renamed with a timestamp beside the original.
+type RotatingFile struct {
+	file *lumberjack.Logger
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// OpenRotatingFile opens path for appending, creating it and its directory if needed.
+// The file is opened immediately so a path that is not writable fails at startup rather
+// than on the first event.
+func OpenRotatingFile(path string, policy RotationPolicy) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log file: %w", err)
+	}
+	f.Close()
+
+	maxSize := policy.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = defaultAuditFileMaxSizeMB
+	}
+	maxAgeDays := 0
+	if policy.MaxAge > 0 {
+		// lumberjack counts whole days; round up so files are never removed early
+		maxAgeDays = int((policy.MaxAge + 24*time.Hour - 1) / (24 * time.Hour))
+	}
+
+	r := &RotatingFile{
+		file: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    maxSize,
+			MaxAge:     maxAgeDays,
+			MaxBackups: policy.MaxBackups,
+			Compress:   policy.Compress,
+		},
+		stop: make(chan struct{}),
+	}
+
+	if policy.RotateEvery > 0 {
+		r.done.Add(1)
+		go r.rotateEvery(policy.RotateEvery)
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	return r.file.Write(p)
+}
+
+// Rotate closes the current file, renames it, and starts a new one
+func (r *RotatingFile) Rotate() error {
+	return r.file.Rotate()
+}
+
+// Close stops time-based rotation and closes the file
+func (r *RotatingFile) Close() error {
+	close(r.stop)
+	r.done.Wait()
+	return r.file.Close()
+}
+
+func (r *RotatingFile) rotateEvery(interval time.Duration) {
+	defer r.done.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			// Errors surface on the next Write, which reopens the file
+			_ = r.file.Rotate()
+		case <-r.stop:
+			return
+		}
+	}
+}
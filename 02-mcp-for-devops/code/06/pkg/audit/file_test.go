@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAuditLoggerWithOutputWritesJSONLines(t *testing.T) {
+	var appLog, output bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&appLog)
+
+	auditLogger := NewAuditLoggerWithOutput(&output, logger)
+	auditLogger.LogAuthentication(context.Background(), "alice", "apikey", true, "")
+	auditLogger.LogAuthorization(context.Background(), "alice", "list", "pods", "default", false)
+
+	scanner := bufio.NewScanner(&output)
+	var events []AuditEvent
+	for scanner.Scan() {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("line %q is not an audit event: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	if len(events) != 2 || events[0].EventType != "authentication" || events[1].Result != "denied" {
+		t.Errorf("unexpected events %+v", events)
+	}
+	if appLog.Len() != 0 {
+		t.Errorf("audit events leaked into the application log: %s", appLog.String())
+	}
+}
+
+func TestFileAuditLoggerRotates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit", "audit.log")
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	file, err := OpenRotatingFile(path, RotationPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLogger := NewAuditLoggerWithOutput(file, logger)
+	auditLogger.LogAuthentication(context.Background(), "alice", "apikey", true, "")
+	if err := file.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	auditLogger.LogAuthentication(context.Background(), "bob", "jwt", true, "")
+	if err := auditLogger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the current and one rotated file, found %d", len(entries))
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(current, []byte(`"user":"bob"`)) || bytes.Contains(current, []byte(`"user":"alice"`)) {
+		t.Errorf("current file holds %s", current)
+	}
+}
@@ -4,15 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+
+	"kubernetes-mcp-server/internal/logging"
 )
 
 type AuditEvent struct {
 	Timestamp    time.Time              `json:"timestamp"`
 	EventID      string                 `json:"event_id"`
 	EventType    string                 `json:"event_type"`
+	RequestID    string                 `json:"request_id,omitempty"` // shared by every event of one request
 	User         string                 `json:"user"`
 	Action       string                 `json:"action"`
 	Resource     string                 `json:"resource"`
@@ -25,6 +31,11 @@ type AuditEvent struct {
 
 type AuditLogger struct {
 	logger *logrus.Logger
+	sinks  []Sink
+
+	// output, when set, receives events as JSON lines instead of the application log
+	outputMu sync.Mutex
+	output   io.Writer
 }
 
 func NewAuditLogger(logger *logrus.Logger) *AuditLogger {
@@ -33,6 +44,64 @@ func NewAuditLogger(logger *logrus.Logger) *AuditLogger {
 	}
 }
 
+// NewAuditLoggerWithOutput returns an audit logger that writes each event to output as
+// one JSON line, keeping the audit trail out of the application log. logger only
+// reports audit failures. Close closes output if it is an io.Closer.
+func NewAuditLoggerWithOutput(output io.Writer, logger *logrus.Logger) *AuditLogger {
+	return &AuditLogger{
+		logger: logger,
+		output: output,
+	}
+}
+
+// NewFileAuditLogger returns an audit logger writing to the file at path, rotated
+// according to policy
+func NewFileAuditLogger(path string, policy RotationPolicy, logger *logrus.Logger) (*AuditLogger, error) {
+	file, err := OpenRotatingFile(path, policy)
+	if err != nil {
+		return nil, err
+	}
+	return NewAuditLoggerWithOutput(file, logger), nil
+}
+
+// AddSink registers an additional destination for audit events. Sinks must be
+// added before the logger is used.
+func (a *AuditLogger) AddSink(sink Sink) {
+	a.sinks = append(a.sinks, sink)
+}
+
+// CheckSinks verifies that every sink implementing Checker can accept events
+func (a *AuditLogger) CheckSinks(ctx context.Context) error {
+	for _, sink := range a.sinks {
+		checker, ok := sink.(Checker)
+		if !ok {
+			continue
+		}
+		if err := checker.Check(ctx); err != nil {
+			return fmt.Errorf("audit sink %T is not writable: %w", sink, err)
+		}
+	}
+	return nil
+}
+
+// Close closes every registered sink and the output
+func (a *AuditLogger) Close() error {
+	var firstErr error
+	for _, sink := range a.sinks {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if closer, ok := a.output.(io.Closer); ok {
+		a.outputMu.Lock()
+		defer a.outputMu.Unlock()
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
 func (a *AuditLogger) LogEvent(ctx context.Context, event *AuditEvent) {
 	// Set timestamp if not provided
 	if event.Timestamp.IsZero() {
@@ -44,6 +113,13 @@ func (a *AuditLogger) LogEvent(ctx context.Context, event *AuditEvent) {
 		event.EventID = generateEventID()
 	}
 
+	if event.RequestID == "" {
+		event.RequestID = logging.RequestID(ctx)
+	}
+
+	// Metadata goes to every sink, so it gets the same redaction as the request log
+	event.Metadata = logging.RedactFields(event.Metadata)
+
 	// Log as structured JSON for easy parsing
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
@@ -51,15 +127,41 @@ func (a *AuditLogger) LogEvent(ctx context.Context, event *AuditEvent) {
 		return
 	}
 
+	if a.output != nil {
+		a.writeOutput(eventJSON)
+	} else {
+		a.logToApplicationLog(event, eventJSON)
+	}
+
+	for _, sink := range a.sinks {
+		if err := sink.Write(ctx, event); err != nil {
+			a.logger.WithError(err).Warn("Failed to write audit event to sink")
+		}
+	}
+}
+
+func (a *AuditLogger) writeOutput(eventJSON []byte) {
+	a.outputMu.Lock()
+	defer a.outputMu.Unlock()
+	if _, err := a.output.Write(append(eventJSON, '\n')); err != nil {
+		a.logger.WithError(err).Error("Failed to write audit event")
+	}
+}
+
+func (a *AuditLogger) logToApplicationLog(event *AuditEvent, eventJSON []byte) {
 	// Use structured logging with audit-specific fields
-	a.logger.WithFields(logrus.Fields{
+	fields := logrus.Fields{
 		"audit":      true,
 		"event_type": event.EventType,
 		"user":       event.User,
 		"action":     event.Action,
 		"result":     event.Result,
 		"duration":   event.Duration.Milliseconds(),
-	}).Info(string(eventJSON))
+	}
+	if event.RequestID != "" {
+		fields["request_id"] = event.RequestID
+	}
+	a.logger.WithFields(fields).Info(string(eventJSON))
 }
 
 func (a *AuditLogger) LogMCPRequest(ctx context.Context, user, action, resource, namespace string, startTime time.Time, err error) {
@@ -130,7 +232,26 @@ func (a *AuditLogger) LogAuthorization(ctx context.Context, user, action, resour
 	a.LogEvent(ctx, event)
 }
 
+// LogRateLimit records a request refused because the caller exceeded their rate limit
+func (a *AuditLogger) LogRateLimit(ctx context.Context, user, action, resource, namespace, permission string, retryAfter time.Duration) {
+	event := &AuditEvent{
+		EventType: "rate_limit",
+		User:      user,
+		Action:    action,
+		Resource:  resource,
+		Namespace: namespace,
+		Result:    "denied",
+		Metadata: map[string]interface{}{
+			"permission":          permission,
+			"retry_after_seconds": retryAfter.Seconds(),
+		},
+	}
+
+	a.LogEvent(ctx, event)
+}
+
+// generateEventID returns a random (version 4) UUID, unique across concurrent events and
+// replicas and not guessable from the event time
 func generateEventID() string {
-	// Simple event ID generation - in production, use UUID
-	return fmt.Sprintf("evt_%d", time.Now().UnixNano())
+	return uuid.NewString()
 }
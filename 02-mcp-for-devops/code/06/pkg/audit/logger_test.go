@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateEventIDIsUniqueUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := generateEventID()
+		if _, err := uuid.Parse(id); err != nil {
+			t.Fatalf("event ID %q is not a UUID: %v", id, err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate event ID %q", id)
+		}
+		seen[id] = true
+	}
+}
@@ -0,0 +1,16 @@
+package audit
+
+import "context"
+
+// Sink persists audit events in addition to the structured log output.
+// Write is called on the request path, so implementations that do I/O
+// should buffer and write asynchronously.
+type Sink interface {
+	Write(ctx context.Context, event *AuditEvent) error
+	Close() error
+}
+
+// Checker is implemented by sinks that can verify they are able to accept events
+type Checker interface {
+	Check(ctx context.Context) error
+}
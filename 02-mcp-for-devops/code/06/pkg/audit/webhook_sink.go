@@ -0,0 +1,185 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWebhookTimeout      = 5 * time.Second
+	defaultWebhookMaxRetries   = 3
+	defaultWebhookRetryBackoff = 500 * time.Millisecond
+	defaultWebhookBufferSize   = 10000
+	// webhookCloseTimeout bounds how long Close keeps delivering queued events to an
+	// endpoint that may be down
+	webhookCloseTimeout = 10 * time.Second
+)
+
+// WebhookSinkConfig controls where and how a WebhookSink delivers events
+type WebhookSinkConfig struct {
+	URL string
+	// Headers are added to every request, e.g. an Authorization header for the collector
+	Headers map[string]string
+	// Timeout bounds each delivery attempt
+	Timeout time.Duration
+	// MaxRetries is how many times a failed delivery is retried, with exponential backoff
+	// starting at RetryBackoff
+	MaxRetries   int
+	RetryBackoff time.Duration
+	BufferSize   int
+}
+
+// WebhookSink POSTs each audit event as JSON to an HTTP endpoint, such as a Splunk HTTP
+// Event Collector or a Datadog log intake. Events are queued in memory and delivered in
+// the background; when the queue is full new events are dropped rather than blocking
+// requests. Connection errors, 429 and 5xx responses are retried; other responses are
+// treated as permanent failures.
+type WebhookSink struct {
+	config WebhookSinkConfig
+	client *http.Client
+	logger *logrus.Logger
+
+	events    chan *AuditEvent
+	done      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// NewWebhookSink starts a sink that delivers to config.URL. Zero config values are
+// replaced with defaults; a negative MaxRetries disables retries.
+func NewWebhookSink(config WebhookSinkConfig, logger *logrus.Logger) (*WebhookSink, error) {
+	endpoint, err := url.Parse(config.URL)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid audit webhook URL %q: must be an http or https URL", config.URL)
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultWebhookTimeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaultWebhookMaxRetries
+	} else if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaultWebhookRetryBackoff
+	}
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaultWebhookBufferSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &WebhookSink{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		logger: logger,
+		events: make(chan *AuditEvent, config.BufferSize),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	go s.run()
+	return s, nil
+}
+
+// Write queues an event for delivery
+func (s *WebhookSink) Write(ctx context.Context, event *AuditEvent) error {
+	select {
+	case s.events <- event:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook queue is full, dropping event %s", event.EventID)
+	}
+}
+
+// Close delivers queued events and stops the background sender. Events still queued
+// after webhookCloseTimeout are dropped.
+func (s *WebhookSink) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.events)
+		select {
+		case <-s.done:
+		case <-time.After(webhookCloseTimeout):
+			s.cancel()
+			<-s.done
+		}
+		s.cancel()
+	})
+	return nil
+}
+
+func (s *WebhookSink) run() {
+	defer close(s.done)
+	for event := range s.events {
+		if s.ctx.Err() != nil {
+			continue
+		}
+		if err := s.deliver(event); err != nil {
+			s.logger.WithError(err).WithField("event_id", event.EventID).Error("Failed to deliver audit event to webhook")
+		}
+	}
+}
+
+// deliver POSTs event, retrying transient failures
+func (s *WebhookSink) deliver(event *AuditEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	backoff := s.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.config.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-s.ctx.Done():
+			return err
+		}
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (s *WebhookSink) post(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("webhook returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("webhook returned %s", resp.Status)
+	}
+}
@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestWebhookSinkRetriesAndDelivers(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		received []AuditEvent
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if r.Header.Get("Authorization") != "Splunk token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Fail the first attempt so the sink has to retry
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event AuditEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("request body is not an audit event: %v", err)
+		}
+		received = append(received, event)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sink, err := NewWebhookSink(WebhookSinkConfig{
+		URL:          server.URL,
+		Headers:      map[string]string{"Authorization": "Splunk token"},
+		RetryBackoff: time.Millisecond,
+	}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auditLogger := NewAuditLoggerWithOutput(io.Discard, logger)
+	auditLogger.AddSink(sink)
+	auditLogger.LogAuthentication(context.Background(), "alice", "apikey", true, "")
+	if err := auditLogger.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 2 {
+		t.Errorf("expected one retry, got %d attempts", attempts)
+	}
+	if len(received) != 1 || received[0].User != "alice" || received[0].EventType != "authentication" {
+		t.Errorf("unexpected events delivered: %+v", received)
+	}
+}
+
+func TestWebhookSinkDoesNotRetryClientErrors(t *testing.T) {
+	var attempts int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	sink, err := NewWebhookSink(WebhookSinkConfig{URL: server.URL, RetryBackoff: time.Millisecond}, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(context.Background(), &AuditEvent{EventID: "evt_1"}); err != nil {
+		t.Fatal(err)
+	}
+	sink.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 1 {
+		t.Errorf("a 400 response was retried: %d attempts", attempts)
+	}
+}
+
+func TestNewWebhookSinkRejectsInvalidURL(t *testing.T) {
+	for _, url := range []string{"", "ftp://example.com", "not a url"} {
+		if _, err := NewWebhookSink(WebhookSinkConfig{URL: url}, logrus.New()); err == nil {
+			t.Errorf("URL %q accepted", url)
+		}
+	}
+}
@@ -2,8 +2,13 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -14,6 +19,35 @@ type APIKeyStore interface {
 	RevokeAPIKey(ctx context.Context, keyID string) error
 }
 
+// ManagedAPIKeyStore is implemented by stores that can issue and rotate keys
+type ManagedAPIKeyStore interface {
+	APIKeyStore
+	// CreateAPIKey generates and stores a new key. A zero ttl creates a key that does
+	// not expire. The key itself is returned only here.
+	CreateAPIKey(ctx context.Context, name string, permissions []string, ttl time.Duration) (string, *APIKeyInfo, error)
+	// RotateAPIKey issues a replacement for a key, with the same name, permissions and
+	// lifetime, and expires the old key after grace so clients can switch over
+	RotateAPIKey(ctx context.Context, keyID string, grace time.Duration) (string, *APIKeyInfo, error)
+	// GetAPIKey returns a copy of the info of the key with keyID
+	GetAPIKey(ctx context.Context, keyID string) (*APIKeyInfo, error)
+}
+
+// ReloadableAPIKeyStore is implemented by stores whose keys can be re-read from
+// their backing source without restarting the server.
+type ReloadableAPIKeyStore interface {
+	APIKeyStore
+	Reload() error
+}
+
+// ErrAPIKeyNotFound is returned when no key has the given ID
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// ErrAPIKeyExpired is returned when rotating a key that has already expired
+var ErrAPIKeyExpired = errors.New("API key has expired")
+
+// apiKeyPrefix marks generated keys so they are easy to recognize, e.g. by secret scanners
+const apiKeyPrefix = "mcp_"
+
 type APIKeyInfo struct {
 	ID          string     `json:"id"`
 	Name        string     `json:"name"`
@@ -24,10 +58,13 @@ type APIKeyInfo struct {
 }
 
 type InMemoryAPIKeyStore struct {
+	mu     sync.RWMutex
 	keys   map[string]*APIKeyInfo
 	logger *logrus.Logger
 }
 
+var _ ManagedAPIKeyStore = (*InMemoryAPIKeyStore)(nil)
+
 func NewInMemoryAPIKeyStore(logger *logrus.Logger) *InMemoryAPIKeyStore {
 	return &InMemoryAPIKeyStore{
 		keys:   make(map[string]*APIKeyInfo),
@@ -36,11 +73,15 @@ func NewInMemoryAPIKeyStore(logger *logrus.Logger) *InMemoryAPIKeyStore {
 }
 
 func (s *InMemoryAPIKeyStore) AddAPIKey(key string, info *APIKeyInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.keys[key] = info
 }
 
 func (s *InMemoryAPIKeyStore) ValidateAPIKey(ctx context.Context, key string) (*APIKeyInfo, error) {
 	// Use constant-time comparison to prevent timing attacks
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	var found *APIKeyInfo
 	for storedKey, info := range s.keys {
 		if subtle.ConstantTimeCompare([]byte(key), []byte(storedKey)) == 1 {
@@ -73,6 +114,8 @@ func (s *InMemoryAPIKeyStore) ValidateAPIKey(ctx context.Context, key string) (*
 }
 
 func (s *InMemoryAPIKeyStore) RevokeAPIKey(ctx context.Context, keyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for key, info := range s.keys {
 		if info.ID == keyID {
 			delete(s.keys, key)
@@ -80,7 +123,108 @@ func (s *InMemoryAPIKeyStore) RevokeAPIKey(ctx context.Context, keyID string) er
 			return nil
 		}
 	}
-	return fmt.Errorf("API key not found: %s", keyID)
+	return fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+}
+
+func (s *InMemoryAPIKeyStore) CreateAPIKey(ctx context.Context, name string, permissions []string, ttl time.Duration) (string, *APIKeyInfo, error) {
+	key, info, err := newAPIKey(name, permissions, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	s.AddAPIKey(key, info)
+	s.logger.WithField("key_id", info.ID).Info("API key created")
+	return key, info, nil
+}
+
+func (s *InMemoryAPIKeyStore) GetAPIKey(ctx context.Context, keyID string) (*APIKeyInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, info := range s.keys {
+		if info.ID == keyID {
+			found := *info
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+}
+
+func (s *InMemoryAPIKeyStore) RotateAPIKey(ctx context.Context, keyID string, grace time.Duration) (string, *APIKeyInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var old *APIKeyInfo
+	for _, info := range s.keys {
+		if info.ID == keyID {
+			old = info
+			break
+		}
+	}
+	if old == nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+	}
+
+	key, replacement, oldExpiry, err := rotateAPIKey(old, grace)
+	if err != nil {
+		return "", nil, err
+	}
+	old.ExpiresAt = &oldExpiry
+	s.keys[key] = replacement
+
+	s.logger.WithFields(logrus.Fields{
+		"key_id":         keyID,
+		"replacement_id": replacement.ID,
+		"old_expires_at": oldExpiry,
+	}).Info("API key rotated")
+	return key, replacement, nil
+}
+
+// newAPIKey generates a high-entropy key and the info for it
+func newAPIKey(name string, permissions []string, ttl time.Duration) (string, *APIKeyInfo, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	id := make([]byte, 6)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, fmt.Errorf("failed to generate API key ID: %w", err)
+	}
+
+	now := time.Now()
+	info := &APIKeyInfo{
+		ID:          "key-" + hex.EncodeToString(id),
+		Name:        name,
+		Permissions: permissions,
+		CreatedAt:   now,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		info.ExpiresAt = &expiresAt
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), info, nil
+}
+
+// rotateAPIKey creates the replacement for old, keeping its name, permissions and
+// lifetime, and returns when old should now expire: after grace, or sooner if it was
+// due to expire anyway. An expired key can't be rotated, since that would renew it.
+func rotateAPIKey(old *APIKeyInfo, grace time.Duration) (string, *APIKeyInfo, time.Time, error) {
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(time.Now()) {
+		return "", nil, time.Time{}, fmt.Errorf("%w: %s", ErrAPIKeyExpired, old.ID)
+	}
+
+	var ttl time.Duration
+	if old.ExpiresAt != nil {
+		ttl = old.ExpiresAt.Sub(old.CreatedAt)
+	}
+	key, replacement, err := newAPIKey(old.Name, old.Permissions, ttl)
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+
+	oldExpiry := time.Now().Add(grace)
+	if old.ExpiresAt != nil && old.ExpiresAt.Before(oldExpiry) {
+		oldExpiry = *old.ExpiresAt
+	}
+	return key, replacement, oldExpiry, nil
 }
 
 // maskAPIKey shows only the first 8 characters for logging
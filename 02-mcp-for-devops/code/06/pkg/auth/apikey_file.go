@@ -0,0 +1,345 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// apiKeyFile is the on-disk format of a FileAPIKeyStore. JSON files parse as well,
+// since JSON is a subset of YAML.
+type apiKeyFile struct {
+	Keys []apiKeyFileEntry `yaml:"keys"`
+}
+
+// apiKeyFileEntry is one key in the file. Operators may write the key itself in Key, or
+// only its SHA-256 digest in KeySHA256 so the file holds no usable secrets. Keys added
+// through the store are always written as digests.
+type apiKeyFileEntry struct {
+	ID          string     `yaml:"id"`
+	Name        string     `yaml:"name"`
+	Key         string     `yaml:"key,omitempty"`
+	KeySHA256   string     `yaml:"keySha256,omitempty"`
+	Permissions []string   `yaml:"permissions"`
+	CreatedAt   time.Time  `yaml:"createdAt"`
+	ExpiresAt   *time.Time `yaml:"expiresAt,omitempty"`
+}
+
+// FileAPIKeyStore keeps API keys in a YAML or JSON file, so keys survive restarts and
+// replicas can share one file. Changes made through the store are written to the file
+// immediately and atomically; edits made to the file by hand are picked up by Reload.
+type FileAPIKeyStore struct {
+	path   string
+	logger *logrus.Logger
+
+	mu      sync.RWMutex
+	entries []apiKeyFileEntry
+	// keys maps the SHA-256 digest of each key to its info
+	keys map[[sha256.Size]byte]*APIKeyInfo
+}
+
+var (
+	_ ReloadableAPIKeyStore = (*FileAPIKeyStore)(nil)
+	_ ManagedAPIKeyStore    = (*FileAPIKeyStore)(nil)
+)
+
+// NewFileAPIKeyStore loads the API keys in path. A missing file is treated as an empty
+// store and is created by the first AddAPIKey.
+func NewFileAPIKeyStore(path string, logger *logrus.Logger) (*FileAPIKeyStore, error) {
+	s := &FileAPIKeyStore{path: path, logger: logger}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload re-reads the key file, replacing the keys in memory. If the file cannot be
+// read or parsed the current keys are kept.
+func (s *FileAPIKeyStore) Reload() error {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		data, err = nil, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read API key file %s: %w", s.path, err)
+	}
+
+	var file apiKeyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse API key file %s: %w", s.path, err)
+	}
+	keys, err := indexAPIKeys(file.Keys)
+	if err != nil {
+		return fmt.Errorf("invalid API key file %s: %w", s.path, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Carry last-used times across reloads; they are not persisted
+	for digest, info := range keys {
+		if previous, ok := s.keys[digest]; ok && previous.ID == info.ID {
+			info.LastUsed = previous.LastUsed
+		}
+	}
+	s.entries = file.Keys
+	s.keys = keys
+	return nil
+}
+
+// AddAPIKey stores a key and writes it to the file as a SHA-256 digest
+func (s *FileAPIKeyStore) AddAPIKey(key string, info *APIKeyInfo) error {
+	digest := sha256.Sum256([]byte(key))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.entries {
+		if existing.ID == info.ID {
+			return fmt.Errorf("API key ID already exists: %s", info.ID)
+		}
+	}
+
+	entries := append(append([]apiKeyFileEntry(nil), s.entries...), apiKeyFileEntry{
+		ID:          info.ID,
+		Name:        info.Name,
+		KeySHA256:   hex.EncodeToString(digest[:]),
+		Permissions: info.Permissions,
+		CreatedAt:   info.CreatedAt,
+		ExpiresAt:   info.ExpiresAt,
+	})
+	if err := s.persist(entries); err != nil {
+		return err
+	}
+
+	s.entries = entries
+	s.keys[digest] = info
+	s.logger.WithField("key_id", info.ID).Info("API key added")
+	return nil
+}
+
+// CreateAPIKey generates a key and adds it to the file
+func (s *FileAPIKeyStore) CreateAPIKey(ctx context.Context, name string, permissions []string, ttl time.Duration) (string, *APIKeyInfo, error) {
+	key, info, err := newAPIKey(name, permissions, ttl)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := s.AddAPIKey(key, info); err != nil {
+		return "", nil, err
+	}
+	return key, info, nil
+}
+
+// GetAPIKey returns a copy of the info of the key with keyID
+func (s *FileAPIKeyStore) GetAPIKey(ctx context.Context, keyID string) (*APIKeyInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, info := range s.keys {
+		if info.ID == keyID {
+			found := *info
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+}
+
+// RotateAPIKey writes the replacement key and the old key's new expiry to the file in
+// one atomic replace
+func (s *FileAPIKeyStore) RotateAPIKey(ctx context.Context, keyID string, grace time.Duration) (string, *APIKeyInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var old *APIKeyInfo
+	for _, info := range s.keys {
+		if info.ID == keyID {
+			old = info
+			break
+		}
+	}
+	if old == nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+	}
+
+	key, replacement, oldExpiry, err := rotateAPIKey(old, grace)
+	if err != nil {
+		return "", nil, err
+	}
+	digest := sha256.Sum256([]byte(key))
+
+	entries := make([]apiKeyFileEntry, 0, len(s.entries)+1)
+	for _, entry := range s.entries {
+		if entry.ID == keyID {
+			entry.ExpiresAt = &oldExpiry
+		}
+		entries = append(entries, entry)
+	}
+	entries = append(entries, apiKeyFileEntry{
+		ID:          replacement.ID,
+		Name:        replacement.Name,
+		KeySHA256:   hex.EncodeToString(digest[:]),
+		Permissions: replacement.Permissions,
+		CreatedAt:   replacement.CreatedAt,
+		ExpiresAt:   replacement.ExpiresAt,
+	})
+	if err := s.persist(entries); err != nil {
+		return "", nil, err
+	}
+
+	s.entries = entries
+	old.ExpiresAt = &oldExpiry
+	s.keys[digest] = replacement
+	s.logger.WithFields(logrus.Fields{
+		"key_id":         keyID,
+		"replacement_id": replacement.ID,
+		"old_expires_at": oldExpiry,
+	}).Info("API key rotated")
+	return key, replacement, nil
+}
+
+func (s *FileAPIKeyStore) ValidateAPIKey(ctx context.Context, key string) (*APIKeyInfo, error) {
+	digest := sha256.Sum256([]byte(key))
+
+	// Rotation changes expiry times, so hold the lock through the expiry check
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found *APIKeyInfo
+	for storedDigest, info := range s.keys {
+		// Use constant-time comparison to prevent timing attacks
+		if subtle.ConstantTimeCompare(digest[:], storedDigest[:]) == 1 {
+			found = info
+			break
+		}
+	}
+
+	if found == nil {
+		s.logger.WithField("key_prefix", maskAPIKey(key)).Warn("Invalid API key attempted")
+		return nil, fmt.Errorf("invalid API key")
+	}
+
+	if found.ExpiresAt != nil && time.Now().After(*found.ExpiresAt) {
+		s.logger.WithField("key_id", found.ID).Warn("Expired API key attempted")
+		return nil, fmt.Errorf("API key expired")
+	}
+
+	now := time.Now()
+	found.LastUsed = &now
+
+	s.logger.WithFields(logrus.Fields{
+		"key_id":   found.ID,
+		"key_name": found.Name,
+	}).Info("API key authenticated successfully")
+
+	return found, nil
+}
+
+// RevokeAPIKey removes a key and writes the file without it
+func (s *FileAPIKeyStore) RevokeAPIKey(ctx context.Context, keyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]apiKeyFileEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		if entry.ID != keyID {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == len(s.entries) {
+		return fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+	}
+
+	if err := s.persist(entries); err != nil {
+		return err
+	}
+
+	s.entries = entries
+	for digest, info := range s.keys {
+		if info.ID == keyID {
+			delete(s.keys, digest)
+		}
+	}
+	s.logger.WithField("key_id", keyID).Info("API key revoked")
+	return nil
+}
+
+// persist writes entries to a temporary file beside the key file and renames it into
+// place, so readers and other replicas never see a partly written file. Callers hold mu.
+func (s *FileAPIKeyStore) persist(entries []apiKeyFileEntry) error {
+	data, err := yaml.Marshal(apiKeyFile{Keys: entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode API keys: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace API key file: %w", err)
+	}
+	return nil
+}
+
+// indexAPIKeys checks file entries and indexes them by key digest
+func indexAPIKeys(entries []apiKeyFileEntry) (map[[sha256.Size]byte]*APIKeyInfo, error) {
+	keys := make(map[[sha256.Size]byte]*APIKeyInfo, len(entries))
+	ids := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if entry.ID == "" {
+			return nil, fmt.Errorf("key %d has no id", i+1)
+		}
+		if ids[entry.ID] {
+			return nil, fmt.Errorf("duplicate key id %s", entry.ID)
+		}
+		ids[entry.ID] = true
+
+		var digest [sha256.Size]byte
+		switch {
+		case entry.Key != "" && entry.KeySHA256 != "":
+			return nil, fmt.Errorf("key %s sets both key and keySha256", entry.ID)
+		case entry.Key != "":
+			digest = sha256.Sum256([]byte(entry.Key))
+		case entry.KeySHA256 != "":
+			decoded, err := hex.DecodeString(entry.KeySHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("key %s has an invalid keySha256; expected 64 hex characters", entry.ID)
+			}
+			copy(digest[:], decoded)
+		default:
+			return nil, fmt.Errorf("key %s sets neither key nor keySha256", entry.ID)
+		}
+		if _, exists := keys[digest]; exists {
+			return nil, fmt.Errorf("key %s duplicates another key", entry.ID)
+		}
+
+		keys[digest] = &APIKeyInfo{
+			ID:          entry.ID,
+			Name:        entry.Name,
+			Permissions: entry.Permissions,
+			CreatedAt:   entry.CreatedAt,
+			ExpiresAt:   entry.ExpiresAt,
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileAPIKeyStorePersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.yaml")
+	handWritten := "keys:\n  - id: ops\n    name: Ops Key\n    key: ops-key-0123456789\n    permissions: [\"k8s:pods:list\"]\n"
+	if err := os.WriteFile(path, []byte(handWritten), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewFileAPIKeyStore(path, quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ValidateAPIKey(context.Background(), "ops-key-0123456789"); err != nil {
+		t.Fatalf("hand-written key: %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	err = store.AddAPIKey("ci-key-abcdefghijkl", &APIKeyInfo{
+		ID:          "ci",
+		Name:        "CI Key",
+		Permissions: []string{"k8s:deployments:list"},
+		CreatedAt:   time.Now(),
+		ExpiresAt:   &expires,
+	})
+	if err != nil {
+		t.Fatalf("AddAPIKey: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "ci-key-abcdefghijkl") {
+		t.Error("an added key was written to the file in plain text")
+	}
+
+	// A new store, as after a restart or on another replica, sees the added key
+	restarted, err := NewFileAPIKeyStore(path, quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := restarted.ValidateAPIKey(context.Background(), "ci-key-abcdefghijkl")
+	if err != nil {
+		t.Fatalf("added key after restart: %v", err)
+	}
+	if info.ExpiresAt == nil || !info.ExpiresAt.Equal(expires) {
+		t.Errorf("expiry not persisted: %v", info.ExpiresAt)
+	}
+
+	if err := restarted.RevokeAPIKey(context.Background(), "ops"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ValidateAPIKey(context.Background(), "ops-key-0123456789"); err == nil {
+		t.Error("a revoked key still validates after reload")
+	}
+}
+
+func TestFileAPIKeyStoreReloadKeepsKeysOnBadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.yaml")
+	store, err := NewFileAPIKeyStore(path, quietLogger())
+	if err != nil {
+		t.Fatalf("missing file should start empty: %v", err)
+	}
+	if err := store.AddAPIKey("first-key-0123456789", &APIKeyInfo{ID: "first", Name: "First"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte("keys:\n  - id: broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Reload(); err == nil {
+		t.Fatal("reload accepted a key with neither key nor keySha256")
+	}
+	if _, err := store.ValidateAPIKey(context.Background(), "first-key-0123456789"); err != nil {
+		t.Errorf("failed reload dropped existing keys: %v", err)
+	}
+}
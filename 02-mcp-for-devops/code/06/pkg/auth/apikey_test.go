@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateAndRotateAPIKey(t *testing.T) {
+	fileStore, err := NewFileAPIKeyStore(filepath.Join(t.TempDir(), "keys.yaml"), quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]ManagedAPIKeyStore{
+		"memory": NewInMemoryAPIKeyStore(quietLogger()),
+		"file":   fileStore,
+	}
+
+	for name, store := range stores {
+		ctx := context.Background()
+		key, info, err := store.CreateAPIKey(ctx, "ci", []string{"k8s:pods:list"}, 24*time.Hour)
+		if err != nil {
+			t.Fatalf("%s: CreateAPIKey: %v", name, err)
+		}
+		if !strings.HasPrefix(key, apiKeyPrefix) || len(key) < 40 {
+			t.Errorf("%s: generated key %q is too short or unprefixed", name, key)
+		}
+		if info.ExpiresAt == nil {
+			t.Fatalf("%s: key created with a ttl has no expiry", name)
+		}
+		if found, err := store.GetAPIKey(ctx, info.ID); err != nil || found.Permissions[0] != "k8s:pods:list" {
+			t.Errorf("%s: GetAPIKey returned %+v, %v", name, found, err)
+		}
+
+		newKey, replacement, err := store.RotateAPIKey(ctx, info.ID, 0)
+		if err != nil {
+			t.Fatalf("%s: RotateAPIKey: %v", name, err)
+		}
+		if replacement.ID == info.ID || replacement.Name != "ci" || replacement.ExpiresAt == nil {
+			t.Errorf("%s: unexpected replacement %+v", name, replacement)
+		}
+
+		// With no grace period the old key stops working at once
+		time.Sleep(time.Millisecond)
+		if _, err := store.ValidateAPIKey(ctx, key); err == nil {
+			t.Errorf("%s: rotated key still validates", name)
+		}
+		if _, err := store.ValidateAPIKey(ctx, newKey); err != nil {
+			t.Errorf("%s: replacement key: %v", name, err)
+		}
+
+		if _, err := store.GetAPIKey(ctx, "missing"); !errors.Is(err, ErrAPIKeyNotFound) {
+			t.Errorf("%s: getting a missing key returned %v", name, err)
+		}
+		if _, _, err := store.RotateAPIKey(ctx, "missing", time.Hour); !errors.Is(err, ErrAPIKeyNotFound) {
+			t.Errorf("%s: rotating a missing key returned %v", name, err)
+		}
+	}
+}
+
+func TestRotateExpiredAPIKeyFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	fileStore, err := NewFileAPIKeyStore(path, quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoryStore := NewInMemoryAPIKeyStore(quietLogger())
+	stores := map[string]ManagedAPIKeyStore{"memory": memoryStore, "file": fileStore}
+	keyCount := map[string]func() int{
+		"memory": func() int { return len(memoryStore.keys) },
+		"file": func() int {
+			// Count what was persisted, as a restarted server would see it
+			restarted, err := NewFileAPIKeyStore(path, quietLogger())
+			if err != nil {
+				t.Fatal(err)
+			}
+			return len(restarted.keys)
+		},
+	}
+
+	for name, store := range stores {
+		ctx := context.Background()
+		_, info, err := store.CreateAPIKey(ctx, "ci", []string{"k8s:pods:list"}, time.Millisecond)
+		if err != nil {
+			t.Fatalf("%s: CreateAPIKey: %v", name, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		// Rotating would hand out a fresh key with a full lifetime
+		if _, _, err := store.RotateAPIKey(ctx, info.ID, time.Hour); !errors.Is(err, ErrAPIKeyExpired) {
+			t.Errorf("%s: rotating an expired key returned %v, want ErrAPIKeyExpired", name, err)
+		}
+		if count := keyCount[name](); count != 1 {
+			t.Errorf("%s: %d keys after a refused rotation, want only the expired one", name, count)
+		}
+	}
+}
+
+func TestRotateAPIKeyKeepsOldKeyDuringGrace(t *testing.T) {
+	store := NewInMemoryAPIKeyStore(quietLogger())
+	ctx := context.Background()
+	key, info, err := store.CreateAPIKey(ctx, "ops", []string{"k8s:*"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := store.RotateAPIKey(ctx, info.ID, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ValidateAPIKey(ctx, key); err != nil {
+		t.Errorf("old key rejected during its grace period: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// jwksFetchTimeout bounds a single JWKS download
+	jwksFetchTimeout = 10 * time.Second
+	// jwksMinRefreshInterval stops tokens with made-up key IDs, or an expired cache while
+	// the provider is unreachable, from making the server download the JWKS on every request
+	jwksMinRefreshInterval = 10 * time.Second
+	// maxJWKSBytes bounds the size of a JWKS document
+	maxJWKSBytes = 1 << 20
+)
+
+// JWKSKeySet holds the signing keys an identity provider publishes at a JWKS URL,
+// indexed by key ID. Keys are fetched on first use and again once they are older than
+// the TTL, so rotated keys are picked up without a restart.
+type JWKSKeySet struct {
+	url    string
+	ttl    time.Duration
+	client *http.Client
+	logger *logrus.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	// fetchMu serializes downloads so concurrent requests share one refresh
+	fetchMu       sync.Mutex
+	lastAttemptAt time.Time
+}
+
+// NewJWKSKeySet creates a key set that downloads keys from url and caches them for ttl
+func NewJWKSKeySet(url string, ttl time.Duration, logger *logrus.Logger) *JWKSKeySet {
+	return &JWKSKeySet{
+		url:    url,
+		ttl:    ttl,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		logger: logger,
+		keys:   map[string]crypto.PublicKey{},
+	}
+}
+
+// Key returns the public key with the given key ID. The JWKS is downloaded again when
+// the cache has expired, and once more when kid is unknown, in case the provider has
+// rotated to a key published after the last download. Downloads are at least
+// jwksMinRefreshInterval apart, and the cached keys are served in between even once
+// expired. An empty kid selects the only key when the set holds exactly one.
+func (s *JWKSKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if s.expired() {
+		if err := s.refreshThrottled(ctx); err != nil {
+			// Keep serving the keys we have; the provider may be briefly unreachable
+			s.logger.WithError(err).Warn("Failed to refresh JWKS, using cached keys")
+		}
+	}
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	if err := s.refreshThrottled(ctx); err != nil {
+		return nil, fmt.Errorf("unknown signing key %q and JWKS refresh failed: %w", kid, err)
+	}
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// Refresh downloads the JWKS and replaces the cached keys
+func (s *JWKSKeySet) Refresh(ctx context.Context) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	return s.fetch(ctx)
+}
+
+// refreshThrottled refreshes the keys unless another request has just tried to, whether
+// or not that attempt succeeded
+func (s *JWKSKeySet) refreshThrottled(ctx context.Context) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+	if time.Since(s.lastAttemptAt) < jwksMinRefreshInterval {
+		return nil
+	}
+	return s.fetch(ctx)
+}
+
+// fetch downloads and parses the JWKS. Callers hold fetchMu.
+func (s *JWKSKeySet) fetch(ctx context.Context) error {
+	s.lastAttemptAt = time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid JWKS URL: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: %s returned %s", s.url, resp.Status)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&document); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// One malformed or unsupported key must not take the others down
+			s.logger.WithError(err).WithField("kid", jwk.Kid).Warn("Skipping JWKS key")
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("JWKS at %s has no usable signing keys", s.url)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+
+	s.logger.WithField("keys", len(keys)).Info("Loaded JWKS signing keys")
+	return nil
+}
+
+func (s *JWKSKeySet) expired() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Since(s.fetchedAt) > s.ttl
+}
+
+func (s *JWKSKeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey is the subset of RFC 7517 needed to read RSA and P-256 public keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if _, err := key.ECDH(); err != nil {
+			return nil, fmt.Errorf("invalid EC point: %w", err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeJWKInt decodes a base64url-encoded big-endian integer
+func decodeJWKInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty value")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer publishes the public halves of keys and counts downloads
+type jwksServer struct {
+	mu      sync.Mutex
+	keys    map[string]*rsa.PrivateKey
+	fetches atomic.Int32
+}
+
+func (s *jwksServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.fetches.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for kid, key := range s.keys {
+		document.Keys = append(document.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	_ = json.NewEncoder(w).Encode(document)
+}
+
+func (s *jwksServer) publish(kid string, key *rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[kid] = key
+}
+
+func tokenWithKid(t *testing.T, kid string, key *rsa.PrivateKey) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "alice",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestJWKSAuthenticatorSelectsKeyByKid(t *testing.T) {
+	first, _ := rsa.GenerateKey(rand.Reader, 2048)
+	second, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := &jwksServer{keys: map[string]*rsa.PrivateKey{"k1": first, "k2": second}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	keySet := NewJWKSKeySet(ts.URL, time.Hour, quietLogger())
+	authenticator, err := NewJWTAuthenticatorWithJWKS("RS256", keySet, DefaultClaimMapping(), quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, kid := range []string{"k1", "k2"} {
+		key := map[string]*rsa.PrivateKey{"k1": first, "k2": second}[kid]
+		if _, err := authenticator.Authenticate(context.Background(), tokenWithKid(t, kid, key)); err != nil {
+			t.Errorf("%s: %v", kid, err)
+		}
+	}
+	// A token claiming one key but signed with the other must fail
+	if _, err := authenticator.Authenticate(context.Background(), tokenWithKid(t, "k1", second)); err == nil {
+		t.Error("accepted a token signed with a different key than its kid names")
+	}
+	if got := server.fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 while the cache is fresh", got)
+	}
+}
+
+func TestJWKSRefreshesOnceForUnknownKid(t *testing.T) {
+	original, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rotated, _ := rsa.GenerateKey(rand.Reader, 2048)
+	server := &jwksServer{keys: map[string]*rsa.PrivateKey{"old": original}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	keySet := NewJWKSKeySet(ts.URL, time.Hour, quietLogger())
+	authenticator, err := NewJWTAuthenticatorWithJWKS("RS256", keySet, DefaultClaimMapping(), quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), tokenWithKid(t, "old", original)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The provider rotates keys; the first token with the new kid triggers a refresh
+	server.publish("new", rotated)
+	keySet.lastAttemptAt = time.Time{}
+	if _, err := authenticator.Authenticate(context.Background(), tokenWithKid(t, "new", rotated)); err != nil {
+		t.Fatalf("token with rotated key: %v", err)
+	}
+
+	// Unknown kids right after a refresh are rejected without another download
+	fetches := server.fetches.Load()
+	if _, err := authenticator.Authenticate(context.Background(), tokenWithKid(t, "bogus", rotated)); err == nil {
+		t.Error("accepted a token with an unknown kid")
+	}
+	if got := server.fetches.Load(); got != fetches {
+		t.Errorf("unknown kid caused %d extra downloads within the refresh interval", got-fetches)
+	}
+}
+
+func TestJWKSRefreshIsThrottledWhileProviderIsDown(t *testing.T) {
+	var fetches atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	// Keys from an earlier download, now past their TTL
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	keySet := NewJWKSKeySet(ts.URL, time.Minute, quietLogger())
+	keySet.keys["k1"] = &key.PublicKey
+	keySet.fetchedAt = time.Now().Add(-time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := keySet.Key(context.Background(), "k1"); err != nil {
+				t.Errorf("stale key not served while the provider is down: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("JWKS fetched %d times, want 1 within the refresh interval", got)
+	}
+
+	// The next attempt waits for the interval to pass
+	keySet.lastAttemptAt = time.Now().Add(-jwksMinRefreshInterval)
+	if _, err := keySet.Key(context.Background(), "k1"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("JWKS fetched %d times, want a second attempt after the interval", got)
+	}
+}
@@ -2,7 +2,12 @@ package auth
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,64 +18,215 @@ type JWTClaims struct {
 	UserID      string   `json:"user_id"`
 	Username    string   `json:"username"`
 	Permissions []string `json:"permissions"`
+	// TokenUse is "refresh" on refresh tokens and empty on access tokens
+	TokenUse string `json:"token_use,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ClaimMapping names the token claims that carry the caller's identity and permissions.
+// Claim names may be dotted paths into nested objects (e.g. "realm_access.roles"), which
+// lets tokens from arbitrary identity providers be consumed without matching our claim names.
+type ClaimMapping struct {
+	IdentityClaim    string
+	UserIDClaim      string
+	PermissionsClaim string
+}
+
+// DefaultClaimMapping matches the claims written by GenerateToken
+func DefaultClaimMapping() ClaimMapping {
+	return ClaimMapping{
+		IdentityClaim:    "username",
+		UserIDClaim:      "user_id",
+		PermissionsClaim: "permissions",
+	}
+}
+
+// TokenIssuer signs access tokens, as a JWTAuthenticator holding a signing secret does
+type TokenIssuer interface {
+	GenerateToken(userID, username string, permissions []string, expiresIn time.Duration) (string, error)
+}
+
 type JWTAuthenticator struct {
 	secretKey []byte
+	// publicKey verifies RS256 or ES256 tokens, as named by algorithm, in place of the
+	// shared HMAC secret. keySet does the same with keys looked up by the token's kid.
+	publicKey crypto.PublicKey
+	keySet    *JWKSKeySet
+	algorithm string
+	claims    ClaimMapping
 	logger    *logrus.Logger
+
+	// refreshTokens holds the refresh tokens issued by GenerateTokenPair that have not
+	// been used or revoked, by token ID
+	refreshMu     sync.Mutex
+	refreshTokens map[string]refreshRecord
 }
 
 func NewJWTAuthenticator(secretKey []byte, logger *logrus.Logger) *JWTAuthenticator {
+	return NewJWTAuthenticatorWithClaims(secretKey, DefaultClaimMapping(), logger)
+}
+
+// NewJWTAuthenticatorWithClaims creates a JWT authenticator that reads identity and
+// permissions from the given claims. Empty fields fall back to the defaults.
+func NewJWTAuthenticatorWithClaims(secretKey []byte, claims ClaimMapping, logger *logrus.Logger) *JWTAuthenticator {
 	return &JWTAuthenticator{
 		secretKey: secretKey,
+		claims:    withDefaultClaims(claims),
 		logger:    logger,
 	}
 }
 
-func (a *JWTAuthenticator) Authenticate(ctx context.Context, tokenString string) (*AuthInfo, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// withDefaultClaims fills empty fields of a claim mapping with the defaults
+func withDefaultClaims(claims ClaimMapping) ClaimMapping {
+	defaults := DefaultClaimMapping()
+	if claims.IdentityClaim == "" {
+		claims.IdentityClaim = defaults.IdentityClaim
+	}
+	if claims.UserIDClaim == "" {
+		claims.UserIDClaim = defaults.UserIDClaim
+	}
+	if claims.PermissionsClaim == "" {
+		claims.PermissionsClaim = defaults.PermissionsClaim
+	}
+	return claims
+}
+
+// NewJWTAuthenticatorWithPublicKey creates a JWT authenticator that verifies tokens
+// signed by an identity provider's private key, so the server never holds a signing
+// key. algorithm is RS256 or ES256 and must match the key type; tokens signed with any
+// other algorithm, HMAC included, are rejected. Such an authenticator cannot issue tokens.
+func NewJWTAuthenticatorWithPublicKey(algorithm string, publicKey crypto.PublicKey, claims ClaimMapping, logger *logrus.Logger) (*JWTAuthenticator, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		if algorithm != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("an RSA public key needs algorithm RS256, not %q", algorithm)
+		}
+	case *ecdsa.PublicKey:
+		if algorithm != jwt.SigningMethodES256.Alg() {
+			return nil, fmt.Errorf("an ECDSA public key needs algorithm ES256, not %q", algorithm)
 		}
-		return a.secretKey, nil
-	})
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+
+	return &JWTAuthenticator{
+		publicKey: publicKey,
+		algorithm: algorithm,
+		claims:    withDefaultClaims(claims),
+		logger:    logger,
+	}, nil
+}
+
+// NewJWTAuthenticatorWithJWKS creates a JWT authenticator that verifies RS256 or ES256
+// tokens with the identity provider key named by each token's kid header
+func NewJWTAuthenticatorWithJWKS(algorithm string, keySet *JWKSKeySet, claims ClaimMapping, logger *logrus.Logger) (*JWTAuthenticator, error) {
+	if algorithm != jwt.SigningMethodRS256.Alg() && algorithm != jwt.SigningMethodES256.Alg() {
+		return nil, fmt.Errorf("JWKS verification needs algorithm RS256 or ES256, not %q", algorithm)
+	}
+
+	return &JWTAuthenticator{
+		keySet:    keySet,
+		algorithm: algorithm,
+		claims:    withDefaultClaims(claims),
+		logger:    logger,
+	}, nil
+}
+
+// ParsePublicKeyPEM parses a PEM-encoded RSA or ECDSA public key or certificate
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("not a PEM-encoded RSA or ECDSA public key")
+}
+
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, tokenString string) (*AuthInfo, error) {
+	token, err := jwt.ParseWithClaims(tokenString, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return a.verificationKey(ctx, token)
+	}, jwt.WithExpirationRequired())
 
 	if err != nil {
 		a.logger.WithError(err).Warn("JWT token validation failed")
 		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
-	claims, ok := token.Claims.(*JWTClaims)
+	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok || !token.Valid {
 		a.logger.Warn("Invalid JWT token claims")
 		return nil, fmt.Errorf("invalid token claims")
 	}
+	if claimString(claims, "token_use") == refreshTokenUse {
+		a.logger.Warn("Refresh token presented as an access token")
+		return nil, fmt.Errorf("refresh tokens cannot be used to authenticate requests")
+	}
 
-	// Additional validation
-	if time.Now().After(claims.ExpiresAt.Time) {
-		a.logger.WithField("username", claims.Username).Warn("Expired JWT token attempted")
-		return nil, fmt.Errorf("token expired")
+	identity := claimString(claims, a.claims.IdentityClaim)
+	if identity == "" {
+		// Every IdP sets the standard subject claim, so fall back to it
+		identity = claimString(claims, "sub")
+	}
+	if identity == "" {
+		a.logger.WithField("identity_claim", a.claims.IdentityClaim).Warn("JWT token has no identity claim")
+		return nil, fmt.Errorf("token is missing identity claim %q", a.claims.IdentityClaim)
+	}
+
+	userID := claimString(claims, a.claims.UserIDClaim)
+	if userID == "" {
+		userID = claimString(claims, "sub")
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
 	a.logger.WithFields(logrus.Fields{
-		"user_id":  claims.UserID,
-		"username": claims.Username,
+		"user_id":  userID,
+		"username": identity,
 	}).Info("JWT authentication successful")
 
 	return &AuthInfo{
 		Type:        "jwt",
-		Identity:    claims.Username,
-		Permissions: claims.Permissions,
+		Identity:    identity,
+		Permissions: claimStrings(claims, a.claims.PermissionsClaim),
 		Metadata: map[string]interface{}{
-			"user_id":    claims.UserID,
-			"expires_at": claims.ExpiresAt.Time,
+			"user_id":    userID,
+			"expires_at": expiresAt.Time,
 		},
 	}, nil
 }
 
+// verificationKey returns the key to check a token's signature with, after checking the
+// token is signed with the algorithm this authenticator expects. Accepting the token's
+// own choice of algorithm would let an HS256 token signed with the public key pass.
+func (a *JWTAuthenticator) verificationKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	if a.publicKey != nil || a.keySet != nil {
+		if token.Method.Alg() != a.algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+	}
+	if a.publicKey != nil {
+		return a.publicKey, nil
+	}
+	if a.keySet != nil {
+		kid, _ := token.Header["kid"].(string)
+		return a.keySet.Key(ctx, kid)
+	}
+
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return a.secretKey, nil
+}
+
 func (a *JWTAuthenticator) GenerateToken(userID, username string, permissions []string, expiresIn time.Duration) (string, error) {
+	if a.secretKey == nil {
+		return "", fmt.Errorf("cannot issue tokens: this authenticator only verifies %s tokens", a.algorithm)
+	}
+
 	claims := &JWTClaims{
 		UserID:      userID,
 		Username:    username,
@@ -86,3 +242,53 @@ func (a *JWTAuthenticator) GenerateToken(userID, username string, permissions []
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(a.secretKey)
 }
+
+// lookupClaim resolves a dotted claim path such as "realm_access.roles"
+func lookupClaim(claims jwt.MapClaims, path string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(claims)
+	for _, part := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// claimString returns a claim as a string, or "" if it is missing or not a string
+func claimString(claims jwt.MapClaims, path string) string {
+	value, ok := lookupClaim(claims, path)
+	if !ok {
+		return ""
+	}
+	str, _ := value.(string)
+	return str
+}
+
+// claimStrings returns a claim as a list of strings. Arrays are used as-is and a
+// single string is split on spaces, which covers OAuth2 "scope"-style claims.
+func claimStrings(claims jwt.MapClaims, path string) []string {
+	value, ok := lookupClaim(claims, path)
+	if !ok {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
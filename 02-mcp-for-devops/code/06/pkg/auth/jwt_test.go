@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+func quietLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func signedToken(t *testing.T, method jwt.SigningMethod, key interface{}) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"username": "alice",
+		"exp":      time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+func TestPublicKeyAuthenticatorVerifiesRS256AndES256(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		algorithm string
+		public    interface{}
+		token     string
+	}{
+		{"RS256", &rsaKey.PublicKey, signedToken(t, jwt.SigningMethodRS256, rsaKey)},
+		{"ES256", &ecKey.PublicKey, signedToken(t, jwt.SigningMethodES256, ecKey)},
+	}
+	for _, tt := range tests {
+		authenticator, err := NewJWTAuthenticatorWithPublicKey(tt.algorithm, tt.public, DefaultClaimMapping(), quietLogger())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.algorithm, err)
+		}
+		info, err := authenticator.Authenticate(context.Background(), tt.token)
+		if err != nil {
+			t.Fatalf("%s: Authenticate: %v", tt.algorithm, err)
+		}
+		if info.Identity != "alice" {
+			t.Errorf("%s: identity = %q, want alice", tt.algorithm, info.Identity)
+		}
+	}
+}
+
+func TestPublicKeyAuthenticatorRejectsOtherAlgorithms(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	publicKey, err := ParsePublicKeyPEM(publicPEM)
+	if err != nil {
+		t.Fatalf("ParsePublicKeyPEM: %v", err)
+	}
+	authenticator, err := NewJWTAuthenticatorWithPublicKey("RS256", publicKey, DefaultClaimMapping(), quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An attacker who knows the public key must not be able to use it as an HMAC secret
+	forged := signedToken(t, jwt.SigningMethodHS256, publicPEM)
+	if _, err := authenticator.Authenticate(context.Background(), forged); err == nil {
+		t.Error("accepted an HS256 token signed with the public key")
+	}
+
+	if _, err := NewJWTAuthenticatorWithPublicKey("ES256", publicKey, DefaultClaimMapping(), quietLogger()); err == nil {
+		t.Error("accepted ES256 with an RSA key")
+	}
+	if _, err := authenticator.GenerateToken("u1", "alice", nil, time.Hour); err == nil {
+		t.Error("a verify-only authenticator issued a token")
+	}
+}
+
+func TestHMACAuthenticatorStillVerifiesIssuedTokens(t *testing.T) {
+	authenticator := NewJWTAuthenticator([]byte("a-test-secret-that-is-at-least-32-bytes"), quietLogger())
+	token, err := authenticator.GenerateToken("u1", "alice", []string{"k8s:pods:list"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := authenticator.Authenticate(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if info.Identity != "alice" || len(info.Permissions) != 1 {
+		t.Errorf("unexpected auth info %+v", info)
+	}
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// refreshTokenUse marks refresh tokens in the token_use claim
+const refreshTokenUse = "refresh"
+
+// TokenPair is a short-lived access token and the refresh token that renews it
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// refreshRecord is what the server remembers about an outstanding refresh token. A
+// refresh carries these values into the new tokens, so a refresh token cannot be edited
+// to widen its permissions even with the signing key.
+type refreshRecord struct {
+	userID      string
+	username    string
+	permissions []string
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	expiresAt   time.Time
+}
+
+// GenerateTokenPair issues an access token valid for accessTTL and a refresh token valid
+// for refreshTTL. RefreshToken exchanges the refresh token for a new pair, so a long AI
+// session can keep its access tokens short-lived without re-authenticating.
+func (a *JWTAuthenticator) GenerateTokenPair(userID, username string, permissions []string, accessTTL, refreshTTL time.Duration) (*TokenPair, error) {
+	return a.issueTokenPair(refreshRecord{
+		userID:      userID,
+		username:    username,
+		permissions: permissions,
+		accessTTL:   accessTTL,
+		refreshTTL:  refreshTTL,
+	})
+}
+
+// RefreshToken validates a refresh token and returns a new token pair. The refresh token
+// is rotated: it is invalidated and the new pair carries its replacement, so each refresh
+// token works once.
+func (a *JWTAuthenticator) RefreshToken(refresh string) (*TokenPair, error) {
+	if a.secretKey == nil {
+		return nil, fmt.Errorf("cannot refresh tokens: this authenticator only verifies %s tokens", a.algorithm)
+	}
+
+	claims := &JWTClaims{}
+	_, err := jwt.ParseWithClaims(refresh, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return a.secretKey, nil
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		a.logger.WithError(err).Warn("Refresh token validation failed")
+		return nil, fmt.Errorf("invalid refresh token: %w", err)
+	}
+	if claims.TokenUse != refreshTokenUse || claims.ID == "" {
+		return nil, fmt.Errorf("invalid refresh token: not a refresh token")
+	}
+
+	a.refreshMu.Lock()
+	record, ok := a.refreshTokens[claims.ID]
+	delete(a.refreshTokens, claims.ID)
+	a.refreshMu.Unlock()
+	if !ok {
+		// Already used or revoked. A reused refresh token may have been stolen.
+		a.logger.WithFields(logrus.Fields{
+			"user_id":  claims.UserID,
+			"token_id": claims.ID,
+		}).Warn("Rejected refresh token that was already used or revoked")
+		return nil, fmt.Errorf("invalid refresh token: already used or revoked")
+	}
+
+	pair, err := a.issueTokenPair(record)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"user_id":  record.userID,
+		"username": record.username,
+	}).Info("Refresh token rotated")
+	return pair, nil
+}
+
+// RevokeRefreshToken invalidates an outstanding refresh token by its ID (the jti claim).
+// It reports whether the token was outstanding.
+func (a *JWTAuthenticator) RevokeRefreshToken(tokenID string) bool {
+	a.refreshMu.Lock()
+	defer a.refreshMu.Unlock()
+	_, ok := a.refreshTokens[tokenID]
+	delete(a.refreshTokens, tokenID)
+	return ok
+}
+
+// RevokeUserRefreshTokens invalidates every outstanding refresh token issued to a user
+// and returns how many were revoked
+func (a *JWTAuthenticator) RevokeUserRefreshTokens(userID string) int {
+	a.refreshMu.Lock()
+	defer a.refreshMu.Unlock()
+	revoked := 0
+	for id, record := range a.refreshTokens {
+		if record.userID == userID {
+			delete(a.refreshTokens, id)
+			revoked++
+		}
+	}
+	return revoked
+}
+
+// issueTokenPair signs a new access token and refresh token and records the refresh token
+func (a *JWTAuthenticator) issueTokenPair(record refreshRecord) (*TokenPair, error) {
+	accessToken, err := a.GenerateToken(record.userID, record.username, record.permissions, record.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	record.expiresAt = now.Add(record.refreshTTL)
+	claims := &JWTClaims{
+		UserID:   record.userID,
+		Username: record.username,
+		TokenUse: refreshTokenUse,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(record.expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "k8s-mcp-server",
+		},
+	}
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	a.refreshMu.Lock()
+	if a.refreshTokens == nil {
+		a.refreshTokens = map[string]refreshRecord{}
+	}
+	// Drop expired records so the store only holds tokens that could still be used
+	for id, outstanding := range a.refreshTokens {
+		if now.After(outstanding.expiresAt) {
+			delete(a.refreshTokens, id)
+		}
+	}
+	a.refreshTokens[tokenID] = record
+	a.refreshMu.Unlock()
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  now.Add(record.accessTTL),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: record.expiresAt,
+	}, nil
+}
+
+// newTokenID returns a random token ID for the jti claim
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenRotates(t *testing.T) {
+	authenticator := NewJWTAuthenticator([]byte("a-test-secret-that-is-at-least-32-bytes"), quietLogger())
+	pair, err := authenticator.GenerateTokenPair("u1", "alice", []string{"k8s:pods:list"}, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := authenticator.Authenticate(context.Background(), pair.AccessToken); err != nil {
+		t.Fatalf("access token: %v", err)
+	}
+	if _, err := authenticator.Authenticate(context.Background(), pair.RefreshToken); err == nil {
+		t.Error("a refresh token authenticated a request")
+	}
+	if _, err := authenticator.RefreshToken(pair.AccessToken); err == nil {
+		t.Error("an access token was accepted as a refresh token")
+	}
+
+	renewed, err := authenticator.RefreshToken(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+	info, err := authenticator.Authenticate(context.Background(), renewed.AccessToken)
+	if err != nil {
+		t.Fatalf("renewed access token: %v", err)
+	}
+	if info.Identity != "alice" || len(info.Permissions) != 1 || info.Permissions[0] != "k8s:pods:list" {
+		t.Errorf("renewed token lost the caller's identity or permissions: %+v", info)
+	}
+
+	// The old refresh token was rotated out and must not work again
+	if _, err := authenticator.RefreshToken(pair.RefreshToken); err == nil {
+		t.Error("a used refresh token was accepted again")
+	}
+	if _, err := authenticator.RefreshToken(renewed.RefreshToken); err != nil {
+		t.Errorf("the rotated refresh token: %v", err)
+	}
+}
+
+func TestRevokeUserRefreshTokens(t *testing.T) {
+	authenticator := NewJWTAuthenticator([]byte("a-test-secret-that-is-at-least-32-bytes"), quietLogger())
+	alice, _ := authenticator.GenerateTokenPair("u1", "alice", nil, time.Minute, time.Hour)
+	bob, _ := authenticator.GenerateTokenPair("u2", "bob", nil, time.Minute, time.Hour)
+
+	if revoked := authenticator.RevokeUserRefreshTokens("u1"); revoked != 1 {
+		t.Errorf("revoked %d tokens, want 1", revoked)
+	}
+	if _, err := authenticator.RefreshToken(alice.RefreshToken); err == nil {
+		t.Error("a revoked refresh token was accepted")
+	}
+	if _, err := authenticator.RefreshToken(bob.RefreshToken); err != nil {
+		t.Errorf("another user's refresh token: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// informerCache serves pod, service and deployment reads from shared informers, which
+// keep a local copy of those objects current through a watch on the API server
+type informerCache struct {
+	factory     informers.SharedInformerFactory
+	pods        corelisters.PodLister
+	services    corelisters.ServiceLister
+	deployments appslisters.DeploymentLister
+	// watermark is when the cache last heard from the API server, in Unix nanoseconds.
+	// It is zero while a watch is failing, so reads go to the API server until the
+	// informer relists.
+	watermark atomic.Int64
+}
+
+type liveReadsKey struct{}
+
+// WithLiveReads returns a context whose reads bypass the informer cache, for flows that
+// must see the result of a change they just made
+func WithLiveReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, liveReadsKey{}, true)
+}
+
+// EnableCache starts shared informers for pods, services and deployments in every
+// namespace and serves unpaged list and get reads from them once they have synced.
+// Informers resync every resync period and stop when ctx is done. If the initial sync
+// does not finish within syncTimeout, for example because the caller cannot list in
+// every namespace, the cache stays disabled and reads keep going to the API server.
+func (c *Client) EnableCache(ctx context.Context, resync, syncTimeout time.Duration) error {
+	ic := &informerCache{factory: informers.NewSharedInformerFactory(c.clientset, resync)}
+
+	touch := func(interface{}) { ic.watermark.Store(time.Now().UnixNano()) }
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    touch,
+		UpdateFunc: func(_, obj interface{}) { touch(obj) },
+		DeleteFunc: touch,
+	}
+	watchFailed := func(r *cache.Reflector, err error) {
+		// A watch closing normally is reopened without losing events
+		if !errors.Is(err, io.EOF) {
+			ic.watermark.Store(0)
+		}
+		cache.DefaultWatchErrorHandler(r, err)
+	}
+
+	sharedInformers := []cache.SharedIndexInformer{
+		ic.factory.Core().V1().Pods().Informer(),
+		ic.factory.Core().V1().Services().Informer(),
+		ic.factory.Apps().V1().Deployments().Informer(),
+	}
+	for _, informer := range sharedInformers {
+		if _, err := informer.AddEventHandler(handler); err != nil {
+			return fmt.Errorf("failed to watch the informer cache: %w", err)
+		}
+		if err := informer.SetWatchErrorHandler(watchFailed); err != nil {
+			return fmt.Errorf("failed to watch the informer cache: %w", err)
+		}
+	}
+	ic.pods = ic.factory.Core().V1().Pods().Lister()
+	ic.services = ic.factory.Core().V1().Services().Lister()
+	ic.deployments = ic.factory.Apps().V1().Deployments().Lister()
+
+	ic.factory.Start(ctx.Done())
+
+	syncCtx, cancel := context.WithTimeout(ctx, syncTimeout)
+	defer cancel()
+	for informerType, synced := range ic.factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			ic.factory.Shutdown()
+			return fmt.Errorf("informer cache for %v did not sync within %s", informerType, syncTimeout)
+		}
+	}
+
+	ic.watermark.Store(time.Now().UnixNano())
+	c.cache = ic
+	return nil
+}
+
+// CacheWatermark returns when the informer cache last heard from the API server. It is
+// zero when the cache is disabled or its watch is failing.
+func (c *Client) CacheWatermark() time.Time {
+	if c.cache == nil {
+		return time.Time{}
+	}
+	if nanos := c.cache.watermark.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+// cacheFor returns the informer cache if it may serve a read in ctx: it is enabled, its
+// watch is healthy, and the caller did not ask for live reads
+func (c *Client) cacheFor(ctx context.Context) *informerCache {
+	if c.cache == nil || c.cache.watermark.Load() == 0 {
+		return nil
+	}
+	if live, _ := ctx.Value(liveReadsKey{}).(bool); live {
+		return nil
+	}
+	return c.cache
+}
+
+// cacheable reports whether a list can be served from the cache, which holds whole
+// collections and cannot apply field selectors or page through results
+func (o ListOptions) cacheable() bool {
+	return o.FieldSelector == "" && o.Limit == 0 && o.Continue == ""
+}
+
+// cachedList lists objects from a lister in the order the API server returns them
+func cachedList[T metav1.Object](list func(labels.Selector) ([]T, error)) ([]T, error) {
+	items, err := list(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+	return items, nil
+}
+
+// getPod reads a pod from the cache, or from the API server when the cache cannot serve
+// it. A pod missing from the cache is looked up live, since it may have just been created.
+func (c *Client) getPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
+	if ic := c.cacheFor(ctx); ic != nil {
+		if pod, err := ic.pods.Pods(namespace).Get(name); !apierrors.IsNotFound(err) {
+			return pod, err
+		}
+	}
+	return retryOnTransient(ctx, func() (*corev1.Pod, error) {
+		return c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+}
+
+// getService reads a service like getPod reads a pod
+func (c *Client) getService(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	if ic := c.cacheFor(ctx); ic != nil {
+		if service, err := ic.services.Services(namespace).Get(name); !apierrors.IsNotFound(err) {
+			return service, err
+		}
+	}
+	return retryOnTransient(ctx, func() (*corev1.Service, error) {
+		return c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+}
+
+// getDeployment reads a deployment like getPod reads a pod
+func (c *Client) getDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	if ic := c.cacheFor(ctx); ic != nil {
+		if deployment, err := ic.deployments.Deployments(namespace).Get(name); !apierrors.IsNotFound(err) {
+			return deployment, err
+		}
+	}
+	return retryOnTransient(ctx, func() (*appsv1.Deployment, error) {
+		return c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+}
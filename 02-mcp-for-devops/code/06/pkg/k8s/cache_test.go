@@ -0,0 +1,49 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCacheForHonoursLiveReadsAndWatchHealth(t *testing.T) {
+	ic := &informerCache{}
+	ic.watermark.Store(time.Now().UnixNano())
+	c := &Client{cache: ic}
+
+	if c.cacheFor(context.Background()) == nil {
+		t.Error("a synced cache should serve reads")
+	}
+	if c.cacheFor(WithLiveReads(context.Background())) != nil {
+		t.Error("WithLiveReads should bypass the cache")
+	}
+
+	ic.watermark.Store(0) // watch failing
+	if c.cacheFor(context.Background()) != nil {
+		t.Error("a cache whose watch is failing should not serve reads")
+	}
+	if !c.CacheWatermark().IsZero() {
+		t.Error("CacheWatermark should be zero while the watch is failing")
+	}
+
+	if (&Client{}).cacheFor(context.Background()) != nil {
+		t.Error("a client without a cache should read live")
+	}
+}
+
+func TestListOptionsCacheable(t *testing.T) {
+	tests := []struct {
+		opts ListOptions
+		want bool
+	}{
+		{ListOptions{}, true},
+		{ListOptions{FieldSelector: "status.phase=Running"}, false},
+		{ListOptions{Limit: 10}, false},
+		{ListOptions{Continue: "token"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.cacheable(); got != tt.want {
+			t.Errorf("%+v.cacheable() = %v, want %v", tt.opts, got, tt.want)
+		}
+	}
+}
@@ -4,26 +4,53 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime"
+	"net/http"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	typesv1 "k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
+	"k8s.io/client-go/util/retry"
+	metricsclient "k8s.io/metrics/pkg/client/clientset/versioned"
 
 	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/internal/tracing"
 	"kubernetes-mcp-server/pkg/types"
 )
 
 type Client struct {
-	clientset *kubernetes.Clientset
-	logger    *logging.Logger
+	clientset     *kubernetes.Clientset
+	restConfig    *rest.Config
+	dynamicClient dynamic.Interface
+	metricsClient metricsclient.Interface
+	restMapper    meta.ResettableRESTMapper
+	// shortcutMapper also resolves kubectl short names such as "deploy" or "hpa"
+	shortcutMapper meta.RESTMapper
+	// cache serves reads from shared informers once EnableCache succeeds; nil otherwise
+	cache *informerCache
+	// allowedNamespaces, when non-empty, limits sweeps across namespaces to these
+	allowedNamespaces []string
+	logger            *logging.Logger
 }
 
 func NewClient(configPath string, logger *logging.Logger) (*Client, error) {
@@ -31,18 +58,46 @@ func NewClient(configPath string, logger *logging.Logger) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to build kubernetes config: %w", err)
 	}
+	// Trace API calls made while serving a traced tool call
+	config.Wrap(tracing.WrapTransport)
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic kubernetes client: %w", err)
+	}
+
+	metricsClient, err := metricsclient.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics client: %w", err)
+	}
+
+	cachedDiscovery := memory.NewMemCacheClient(clientset.Discovery())
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery)
+
 	return &Client{
-		clientset: clientset,
-		logger:    logger,
+		clientset:     clientset,
+		restConfig:    config,
+		dynamicClient: dynamicClient,
+		metricsClient: metricsClient,
+		restMapper:    restMapper,
+		shortcutMapper: restmapper.NewShortcutExpander(restMapper, cachedDiscovery, func(warning string) {
+			logger.Warn(warning)
+		}),
+		logger: logger,
 	}, nil
 }
 
+// SetAllowedNamespaces limits tools that sweep every namespace to namespaces. An empty
+// list sweeps the whole cluster.
+func (c *Client) SetAllowedNamespaces(namespaces []string) {
+	c.allowedNamespaces = namespaces
+}
+
 func buildConfig(configPath string) (*rest.Config, error) {
 	// Try in-cluster config first
 	if config, err := rest.InClusterConfig(); err == nil {
@@ -59,117 +114,287 @@ func buildConfig(configPath string) (*rest.Config, error) {
 	return clientcmd.BuildConfigFromFlags("", configPath)
 }
 
+// defaultHealthCheckTimeout bounds HealthCheck when ctx carries no deadline of its own
+const defaultHealthCheckTimeout = 5 * time.Second
+
+// HealthCheck reports whether the API server answers before ctx's deadline, or within
+// defaultHealthCheckTimeout when ctx has none, so an unreachable cluster fails the check
+// rather than hanging it
 func (c *Client) HealthCheck(ctx context.Context) error {
-	_, err := c.clientset.Discovery().ServerVersion()
-	if err != nil {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultHealthCheckTimeout)
+		defer cancel()
+	}
+
+	if _, err := c.serverVersion(ctx); err != nil {
 		return fmt.Errorf("kubernetes cluster not reachable: %w", err)
 	}
 	return nil
 }
 
-func (c *Client) GetClusterInfo(ctx context.Context) (map[string]interface{}, error) {
-	version, err := c.clientset.Discovery().ServerVersion()
+// serverVersion reads the API server's version. Unlike Discovery().ServerVersion, the
+// request is cancelled with ctx.
+func (c *Client) serverVersion(ctx context.Context) (*version.Info, error) {
+	body, err := c.clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get server version: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("%w: %v", ctxErr, err)
+		}
+		return nil, err
 	}
 
-	info := map[string]interface{}{
-		"serverVersion": version.String(),
-		"platform":      version.Platform,
-		"buildDate":     version.BuildDate,
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode server version: %w", err)
 	}
+	return &info, nil
+}
 
-	return info, nil
+// MaxListLimit caps the page size callers may request from a single list call
+const MaxListLimit = 1000
+
+// ListOptions filters and pages a list call. The zero value lists everything in one call.
+type ListOptions struct {
+	// FieldSelector filters on the server, e.g. "status.phase=Running"
+	FieldSelector string
+	// Limit caps the number of items returned; 0 means no limit
+	Limit int64
+	// Continue is the token returned by the previous page
+	Continue string
+}
+
+func (o ListOptions) toMeta() metav1.ListOptions {
+	return metav1.ListOptions{
+		FieldSelector: o.FieldSelector,
+		Limit:         o.Limit,
+		Continue:      o.Continue,
+	}
 }
 
-func (c *Client) ListPods(ctx context.Context, namespace string) ([]PodInfo, error) {
-	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+// ListPods lists pods in a namespace. It also returns the continue token for the next
+// page, which is empty on the last page.
+func (c *Client) ListPods(ctx context.Context, namespace string, opts ListOptions) ([]PodInfo, string, error) {
+	if ic := c.cacheFor(ctx); ic != nil && opts.cacheable() {
+		if pods, err := cachedList(ic.pods.Pods(namespace).List); err == nil {
+			podInfos := make([]PodInfo, 0, len(pods))
+			for _, pod := range pods {
+				podInfos = append(podInfos, newPodInfo(pod))
+			}
+			return podInfos, "", nil
+		}
+	}
+
+	pods, err := retryOnTransient(ctx, func() (*corev1.PodList, error) {
+		return c.clientset.CoreV1().Pods(namespace).List(ctx, opts.toMeta())
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		return nil, "", fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 	}
 
 	var podInfos []PodInfo
-	for _, pod := range pods.Items {
-		podInfo := PodInfo{
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			Status:    string(pod.Status.Phase),
-			Phase:     string(pod.Status.Phase),
-			Node:      pod.Spec.NodeName,
-			Labels:    pod.Labels,
-			CreatedAt: pod.CreationTimestamp.Time,
-			Restarts:  getTotalRestarts(&pod),
-		}
-		podInfos = append(podInfos, podInfo)
+	for i := range pods.Items {
+		podInfos = append(podInfos, newPodInfo(&pods.Items[i]))
 	}
 
-	return podInfos, nil
+	return podInfos, pods.Continue, nil
 }
 
-func (c *Client) ListServices(ctx context.Context, namespace string) ([]ServiceInfo, error) {
-	services, err := c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+// PodSearchCriteria filters pods in a cluster-wide search
+type PodSearchCriteria struct {
+	LabelSelector string
+	NameContains  string
+	Phase         string
+}
+
+// SearchPods searches pods across all namespaces one page at a time. The returned
+// cursor resumes the search where this page stopped.
+func (c *Client) SearchPods(ctx context.Context, criteria PodSearchCriteria, limit int, cursor string) (*SweepPage[PodInfo], error) {
+	namespaces, err := c.namespaceNames(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+		return nil, err
 	}
 
-	var serviceInfos []ServiceInfo
-	for _, svc := range services.Items {
-		var ports []ServicePort
-		for _, port := range svc.Spec.Ports {
-			ports = append(ports, ServicePort{
-				Name:       port.Name,
-				Port:       port.Port,
-				TargetPort: port.TargetPort.String(),
-				Protocol:   string(port.Protocol),
-			})
+	return SweepNamespaces(ctx, namespaces, limit, cursor, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]PodInfo, string, error) {
+		opts.LabelSelector = criteria.LabelSelector
+		pods, err := retryOnTransient(ctx, func() (*corev1.PodList, error) {
+			return c.clientset.CoreV1().Pods(namespace).List(ctx, opts)
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
 		}
 
-		serviceInfo := ServiceInfo{
-			Name:      svc.Name,
-			Namespace: svc.Namespace,
-			Type:      string(svc.Spec.Type),
-			ClusterIP: svc.Spec.ClusterIP,
-			Ports:     ports,
-			Labels:    svc.Labels,
-			CreatedAt: svc.CreationTimestamp.Time,
+		var podInfos []PodInfo
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if criteria.NameContains != "" && !strings.Contains(pod.Name, criteria.NameContains) {
+				continue
+			}
+			if criteria.Phase != "" && !strings.EqualFold(string(pod.Status.Phase), criteria.Phase) {
+				continue
+			}
+			podInfos = append(podInfos, newPodInfo(pod))
+		}
+		return podInfos, pods.Continue, nil
+	})
+}
+
+// ListServices lists services in a namespace, returning the continue token for the next page
+func (c *Client) ListServices(ctx context.Context, namespace string, opts ListOptions) ([]ServiceInfo, string, error) {
+	if ic := c.cacheFor(ctx); ic != nil && opts.cacheable() {
+		if services, err := cachedList(ic.services.Services(namespace).List); err == nil {
+			serviceInfos := make([]ServiceInfo, 0, len(services))
+			for _, service := range services {
+				serviceInfos = append(serviceInfos, newServiceInfo(service))
+			}
+			return serviceInfos, "", nil
 		}
-		serviceInfos = append(serviceInfos, serviceInfo)
 	}
 
-	return serviceInfos, nil
+	services, err := retryOnTransient(ctx, func() (*corev1.ServiceList, error) {
+		return c.clientset.CoreV1().Services(namespace).List(ctx, opts.toMeta())
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+	}
+
+	var serviceInfos []ServiceInfo
+	for i := range services.Items {
+		serviceInfos = append(serviceInfos, newServiceInfo(&services.Items[i]))
+	}
+
+	return serviceInfos, services.Continue, nil
 }
 
-func (c *Client) ListDeployments(ctx context.Context, namespace string) ([]DeploymentInfo, error) {
-	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+// GetPodServices returns the services in the pod's namespace whose selector matches the pod's labels
+func (c *Client) GetPodServices(ctx context.Context, namespace, podName string) ([]ServiceInfo, error) {
+	pod, err := retryOnTransient(ctx, func() (*corev1.Pod, error) {
+		return c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, podName, err)
 	}
 
-	var deploymentInfos []DeploymentInfo
-	for _, deploy := range deployments.Items {
-		strategy := "RollingUpdate"
-		if deploy.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
-			strategy = "Recreate"
+	services, err := retryOnTransient(ctx, func() (*corev1.ServiceList, error) {
+		return c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in namespace %s: %w", namespace, err)
+	}
+
+	var matching []ServiceInfo
+	for i := range services.Items {
+		svc := &services.Items[i]
+		// Services without a selector have manually managed endpoints and never select pods
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		if labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			matching = append(matching, newServiceInfo(svc))
 		}
+	}
 
-		deploymentInfo := DeploymentInfo{
-			Name:            deploy.Name,
-			Namespace:       deploy.Namespace,
-			TotalReplicas:   *deploy.Spec.Replicas,
-			ReadyReplicas:   deploy.Status.ReadyReplicas,
-			UpdatedReplicas: deploy.Status.UpdatedReplicas,
-			Labels:          deploy.Labels,
-			CreatedAt:       deploy.CreationTimestamp.Time,
-			Strategy:        strategy,
+	return matching, nil
+}
+
+// ListDeployments lists deployments in a namespace, returning the continue token for the next page
+func (c *Client) ListDeployments(ctx context.Context, namespace string, opts ListOptions) ([]DeploymentInfo, string, error) {
+	if ic := c.cacheFor(ctx); ic != nil && opts.cacheable() {
+		if deployments, err := cachedList(ic.deployments.Deployments(namespace).List); err == nil {
+			deploymentInfos := make([]DeploymentInfo, 0, len(deployments))
+			for _, deploy := range deployments {
+				deploymentInfos = append(deploymentInfos, newDeploymentInfo(deploy))
+			}
+			return deploymentInfos, "", nil
 		}
-		deploymentInfos = append(deploymentInfos, deploymentInfo)
 	}
 
-	return deploymentInfos, nil
+	deployments, err := retryOnTransient(ctx, func() (*appsv1.DeploymentList, error) {
+		return c.clientset.AppsV1().Deployments(namespace).List(ctx, opts.toMeta())
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+	}
+
+	var deploymentInfos []DeploymentInfo
+	for i := range deployments.Items {
+		deploymentInfos = append(deploymentInfos, newDeploymentInfo(&deployments.Items[i]))
+	}
+
+	return deploymentInfos, deployments.Continue, nil
+}
+
+// newDeploymentInfo summarizes a deployment
+func newDeploymentInfo(deploy *appsv1.Deployment) DeploymentInfo {
+	strategy := "RollingUpdate"
+	if deploy.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		strategy = "Recreate"
+	}
+
+	return DeploymentInfo{
+		Name:            deploy.Name,
+		Namespace:       deploy.Namespace,
+		TotalReplicas:   *deploy.Spec.Replicas,
+		ReadyReplicas:   deploy.Status.ReadyReplicas,
+		UpdatedReplicas: deploy.Status.UpdatedReplicas,
+		Labels:          deploy.Labels,
+		CreatedAt:       deploy.CreationTimestamp.Time,
+		Strategy:        strategy,
+	}
+}
+
+func (c *Client) ListStatefulSets(ctx context.Context, namespace string) ([]StatefulSetInfo, error) {
+	statefulSets, err := retryOnTransient(ctx, func() (*appsv1.StatefulSetList, error) {
+		return c.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets in namespace %s: %w", namespace, err)
+	}
+
+	var statefulSetInfos []StatefulSetInfo
+	for i := range statefulSets.Items {
+		statefulSetInfos = append(statefulSetInfos, newStatefulSetInfo(&statefulSets.Items[i]))
+	}
+
+	return statefulSetInfos, nil
+}
+
+func (c *Client) ListDaemonSets(ctx context.Context, namespace string) ([]DaemonSetInfo, error) {
+	daemonSets, err := retryOnTransient(ctx, func() (*appsv1.DaemonSetList, error) {
+		return c.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets in namespace %s: %w", namespace, err)
+	}
+
+	var daemonSetInfos []DaemonSetInfo
+	for i := range daemonSets.Items {
+		daemonSetInfos = append(daemonSetInfos, newDaemonSetInfo(&daemonSets.Items[i]))
+	}
+
+	return daemonSetInfos, nil
+}
+
+func (c *Client) ListNodes(ctx context.Context) ([]NodeInfo, error) {
+	nodes, err := retryOnTransient(ctx, func() (*corev1.NodeList, error) {
+		return c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	var nodeInfos []NodeInfo
+	for i := range nodes.Items {
+		nodeInfos = append(nodeInfos, newNodeInfo(&nodes.Items[i]))
+	}
+
+	return nodeInfos, nil
 }
 
 func (c *Client) ListConfigMaps(ctx context.Context, namespace string) ([]ConfigMapInfo, error) {
-	configmaps, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	configmaps, err := retryOnTransient(ctx, func() (*corev1.ConfigMapList, error) {
+		return c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list configmaps in namespace %s: %w", namespace, err)
 	}
@@ -189,8 +414,26 @@ func (c *Client) ListConfigMaps(ctx context.Context, namespace string) ([]Config
 	return configmapInfos, nil
 }
 
+func (c *Client) ListSecrets(ctx context.Context, namespace string) ([]SecretInfo, error) {
+	secrets, err := retryOnTransient(ctx, func() (*corev1.SecretList, error) {
+		return c.clientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets in namespace %s: %w", namespace, err)
+	}
+
+	var secretInfos []SecretInfo
+	for i := range secrets.Items {
+		secretInfos = append(secretInfos, newSecretInfo(&secrets.Items[i]))
+	}
+
+	return secretInfos, nil
+}
+
 func (c *Client) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
-	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	namespaces, err := retryOnTransient(ctx, func() (*corev1.NamespaceList, error) {
+		return c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list namespaces: %w", err)
 	}
@@ -209,6 +452,44 @@ func (c *Client) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
 	return namespaceInfos, nil
 }
 
+// GetResourceEvents returns the events recorded for an object, oldest first
+func (c *Client) GetResourceEvents(ctx context.Context, namespace, kind, name string) ([]EventInfo, error) {
+	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.Set{
+			"involvedObject.kind": kind,
+			"involvedObject.name": name,
+		}.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events for %s %s/%s: %w", kind, namespace, name, err)
+	}
+
+	eventInfos := make([]EventInfo, 0, len(events.Items))
+	for i := range events.Items {
+		eventInfos = append(eventInfos, newEventInfo(&events.Items[i]))
+	}
+	sort.SliceStable(eventInfos, func(i, j int) bool {
+		return eventInfos[i].LastTimestamp.Before(eventInfos[j].LastTimestamp)
+	})
+
+	return eventInfos, nil
+}
+
+// objectEvents returns the events recorded for an object, oldest first, formatted as
+// "<type> <reason>: <message>"
+func (c *Client) objectEvents(ctx context.Context, namespace, kind, name string) ([]string, error) {
+	events, err := c.GetResourceEvents(ctx, namespace, kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]string, 0, len(events))
+	for _, event := range events {
+		messages = append(messages, event.String())
+	}
+	return messages, nil
+}
+
 func (c *Client) GetResource(ctx context.Context, identifier *types.ResourceIdentifier) (string, error) {
 	switch identifier.Type {
 	case types.ResourceTypePod:
@@ -219,37 +500,56 @@ func (c *Client) GetResource(ctx context.Context, identifier *types.ResourceIden
 		return c.getDeploymentDetails(ctx, identifier.Namespace, identifier.Name)
 	case types.ResourceTypeConfigMap:
 		return c.getConfigMapDetails(ctx, identifier.Namespace, identifier.Name)
+	case types.ResourceTypeSecret:
+		return c.getSecretDetails(ctx, identifier.Namespace, identifier.Name)
 	case types.ResourceTypeNamespace:
 		return c.getNamespaceDetails(ctx, identifier.Name)
+	case types.ResourceTypeStatefulSet:
+		return c.getStatefulSetDetails(ctx, identifier.Namespace, identifier.Name)
+	case types.ResourceTypeDaemonSet:
+		return c.getDaemonSetDetails(ctx, identifier.Namespace, identifier.Name)
+	case types.ResourceTypeNode:
+		return c.getNodeDetails(ctx, identifier.Name)
+	case types.ResourceTypePersistentVolumeClaim:
+		return c.getPVCDetails(ctx, identifier.Namespace, identifier.Name)
+	case types.ResourceTypePersistentVolume:
+		return c.getPVDetails(ctx, identifier.Name)
+	case types.ResourceTypeJob:
+		return c.getJobDetails(ctx, identifier.Namespace, identifier.Name)
+	case types.ResourceTypeCronJob:
+		return c.getCronJobDetails(ctx, identifier.Namespace, identifier.Name)
+	case types.ResourceTypeHPA:
+		return c.getHPADetails(ctx, identifier.Namespace, identifier.Name)
 	default:
 		return "", fmt.Errorf("unsupported resource type: %s", identifier.Type)
 	}
 }
 
 func (c *Client) getPodDetails(ctx context.Context, namespace, name string) (string, error) {
-	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	pod, err := c.getPod(ctx, namespace, name)
 	if err != nil {
 		return "", fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
 	}
 
+	// Events explain most crash loops and scheduling failures, but a missing event
+	// list should not hide the rest of the pod's details
+	events, err := c.GetResourceEvents(ctx, namespace, "Pod", name)
+	if err != nil {
+		c.logger.Warnf("Failed to get events for pod %s/%s: %v", namespace, name, err)
+	}
+	if len(events) > maxPodEvents {
+		events = events[len(events)-maxPodEvents:]
+	}
+
 	// Create detailed pod information
+	podInfo := newPodInfo(pod)
 	podDetail := struct {
 		*PodInfo
-		Containers []ContainerInfo `json:"containers"`
-		Events     []string        `json:"recentEvents"`
-		Conditions []string        `json:"conditions"`
+		Events     []EventInfo `json:"recentEvents"`
+		Conditions []string    `json:"conditions"`
 	}{
-		PodInfo: &PodInfo{
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			Status:    string(pod.Status.Phase),
-			Phase:     string(pod.Status.Phase),
-			Node:      pod.Spec.NodeName,
-			Labels:    pod.Labels,
-			CreatedAt: pod.CreationTimestamp.Time,
-			Restarts:  getTotalRestarts(pod),
-		},
-		Containers: getContainerInfo(pod),
+		PodInfo:    &podInfo,
+		Events:     events,
 		Conditions: getPodConditions(pod),
 	}
 
@@ -262,36 +562,19 @@ func (c *Client) getPodDetails(ctx context.Context, namespace, name string) (str
 }
 
 func (c *Client) getServiceDetails(ctx context.Context, namespace, name string) (string, error) {
-	service, err := c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	service, err := c.getService(ctx, namespace, name)
 	if err != nil {
 		return "", fmt.Errorf("failed to get service %s/%s: %w", namespace, name, err)
 	}
 
-	var ports []ServicePort
-	for _, port := range service.Spec.Ports {
-		ports = append(ports, ServicePort{
-			Name:       port.Name,
-			Port:       port.Port,
-			TargetPort: port.TargetPort.String(),
-			Protocol:   string(port.Protocol),
-		})
-	}
-
+	serviceInfo := newServiceInfo(service)
 	serviceDetail := struct {
 		*ServiceInfo
 		Selector  map[string]string `json:"selector"`
 		Endpoints []string          `json:"endpoints"`
 	}{
-		ServiceInfo: &ServiceInfo{
-			Name:      service.Name,
-			Namespace: service.Namespace,
-			Type:      string(service.Spec.Type),
-			ClusterIP: service.Spec.ClusterIP,
-			Ports:     ports,
-			Labels:    service.Labels,
-			CreatedAt: service.CreationTimestamp.Time,
-		},
-		Selector: service.Spec.Selector,
+		ServiceInfo: &serviceInfo,
+		Selector:    service.Spec.Selector,
 	}
 
 	data, err := json.MarshalIndent(serviceDetail, "", "  ")
@@ -303,33 +586,20 @@ func (c *Client) getServiceDetails(ctx context.Context, namespace, name string)
 }
 
 func (c *Client) getDeploymentDetails(ctx context.Context, namespace, name string) (string, error) {
-	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	deployment, err := c.getDeployment(ctx, namespace, name)
 	if err != nil {
 		return "", fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
 	}
 
-	strategy := "RollingUpdate"
-	if deployment.Spec.Strategy.Type == appsv1.RecreateDeploymentStrategyType {
-		strategy = "Recreate"
-	}
-
+	deploymentInfo := newDeploymentInfo(deployment)
 	deploymentDetail := struct {
 		*DeploymentInfo
 		Selector   map[string]string `json:"selector"`
 		Conditions []string          `json:"conditions"`
 	}{
-		DeploymentInfo: &DeploymentInfo{
-			Name:            deployment.Name,
-			Namespace:       deployment.Namespace,
-			TotalReplicas:   *deployment.Spec.Replicas,
-			ReadyReplicas:   deployment.Status.ReadyReplicas,
-			UpdatedReplicas: deployment.Status.UpdatedReplicas,
-			Labels:          deployment.Labels,
-			CreatedAt:       deployment.CreationTimestamp.Time,
-			Strategy:        strategy,
-		},
-		Selector:   deployment.Spec.Selector.MatchLabels,
-		Conditions: getDeploymentConditions(deployment),
+		DeploymentInfo: &deploymentInfo,
+		Selector:       deployment.Spec.Selector.MatchLabels,
+		Conditions:     getDeploymentConditions(deployment),
 	}
 
 	data, err := json.MarshalIndent(deploymentDetail, "", "  ")
@@ -340,8 +610,139 @@ func (c *Client) getDeploymentDetails(ctx context.Context, namespace, name strin
 	return string(data), nil
 }
 
+func (c *Client) getStatefulSetDetails(ctx context.Context, namespace, name string) (string, error) {
+	statefulSet, err := retryOnTransient(ctx, func() (*appsv1.StatefulSet, error) {
+		return c.clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get statefulset %s/%s: %w", namespace, name, err)
+	}
+
+	var claimTemplates []string
+	for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+		claimTemplates = append(claimTemplates, template.Name)
+	}
+
+	statefulSetInfo := newStatefulSetInfo(statefulSet)
+	statefulSetDetail := struct {
+		*StatefulSetInfo
+		PodManagementPolicy  string            `json:"podManagementPolicy"`
+		UpdateStrategy       string            `json:"updateStrategy"`
+		VolumeClaimTemplates []string          `json:"volumeClaimTemplates"`
+		Selector             map[string]string `json:"selector"`
+	}{
+		StatefulSetInfo:      &statefulSetInfo,
+		PodManagementPolicy:  string(statefulSet.Spec.PodManagementPolicy),
+		UpdateStrategy:       string(statefulSet.Spec.UpdateStrategy.Type),
+		VolumeClaimTemplates: claimTemplates,
+		Selector:             statefulSet.Spec.Selector.MatchLabels,
+	}
+
+	data, err := json.MarshalIndent(statefulSetDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal statefulset details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func (c *Client) getDaemonSetDetails(ctx context.Context, namespace, name string) (string, error) {
+	daemonSet, err := retryOnTransient(ctx, func() (*appsv1.DaemonSet, error) {
+		return c.clientset.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get daemonset %s/%s: %w", namespace, name, err)
+	}
+
+	podSpec := daemonSet.Spec.Template.Spec
+	var tolerations []string
+	for _, toleration := range podSpec.Tolerations {
+		tolerations = append(tolerations, formatToleration(toleration))
+	}
+
+	daemonSetInfo := newDaemonSetInfo(daemonSet)
+	daemonSetDetail := struct {
+		*DaemonSetInfo
+		NumberMisscheduled int32             `json:"numberMisscheduled"`
+		NodeSelector       map[string]string `json:"nodeSelector"`
+		HasNodeAffinity    bool              `json:"hasNodeAffinity"`
+		Tolerations        []string          `json:"tolerations"`
+		Selector           map[string]string `json:"selector"`
+	}{
+		DaemonSetInfo:      &daemonSetInfo,
+		NumberMisscheduled: daemonSet.Status.NumberMisscheduled,
+		NodeSelector:       podSpec.NodeSelector,
+		HasNodeAffinity:    podSpec.Affinity != nil && podSpec.Affinity.NodeAffinity != nil,
+		Tolerations:        tolerations,
+		Selector:           daemonSet.Spec.Selector.MatchLabels,
+	}
+
+	data, err := json.MarshalIndent(daemonSetDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal daemonset details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func (c *Client) getNodeDetails(ctx context.Context, name string) (string, error) {
+	node, err := retryOnTransient(ctx, func() (*corev1.Node, error) {
+		return c.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", name, err)
+	}
+
+	var problems []string
+	for _, condition := range node.Status.Conditions {
+		healthy := condition.Status == corev1.ConditionFalse
+		if condition.Type == corev1.NodeReady {
+			healthy = condition.Status == corev1.ConditionTrue
+		}
+		if !healthy {
+			problems = append(problems, fmt.Sprintf("%s=%s: %s", condition.Type, condition.Status, condition.Message))
+		}
+	}
+
+	nodeInfo := newNodeInfo(node)
+	nodeDetail := struct {
+		*NodeInfo
+		CapacityCPU      string   `json:"capacityCPU"`
+		CapacityMemory   string   `json:"capacityMemory"`
+		AllocatablePods  string   `json:"allocatablePods"`
+		ContainerRuntime string   `json:"containerRuntime"`
+		KernelVersion    string   `json:"kernelVersion"`
+		Architecture     string   `json:"architecture"`
+		InternalIP       string   `json:"internalIP,omitempty"`
+		ConditionIssues  []string `json:"conditionIssues,omitempty"`
+	}{
+		NodeInfo:         &nodeInfo,
+		CapacityCPU:      node.Status.Capacity.Cpu().String(),
+		CapacityMemory:   node.Status.Capacity.Memory().String(),
+		AllocatablePods:  node.Status.Allocatable.Pods().String(),
+		ContainerRuntime: node.Status.NodeInfo.ContainerRuntimeVersion,
+		KernelVersion:    node.Status.NodeInfo.KernelVersion,
+		Architecture:     node.Status.NodeInfo.Architecture,
+		ConditionIssues:  problems,
+	}
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			nodeDetail.InternalIP = address.Address
+		}
+	}
+
+	data, err := json.MarshalIndent(nodeDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal node details: %w", err)
+	}
+
+	return string(data), nil
+}
+
 func (c *Client) getConfigMapDetails(ctx context.Context, namespace, name string) (string, error) {
-	configmap, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	configmap, err := retryOnTransient(ctx, func() (*corev1.ConfigMap, error) {
+		return c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
 	}
@@ -362,8 +763,81 @@ func (c *Client) getConfigMapDetails(ctx context.Context, namespace, name string
 	return string(data), nil
 }
 
+// getSecretDetails describes a secret by its key names and value sizes. Decoded values are
+// never returned. Annotations are left out as well, since kubectl's last-applied-configuration
+// annotation carries the full secret.
+func (c *Client) getSecretDetails(ctx context.Context, namespace, name string) (string, error) {
+	secret, err := retryOnTransient(ctx, func() (*corev1.Secret, error) {
+		return c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	keyLengths := make(map[string]int, len(secret.Data))
+	for key, value := range secret.Data {
+		keyLengths[key] = len(value)
+	}
+
+	secretInfo := newSecretInfo(secret)
+	secretDetail := struct {
+		*SecretInfo
+		KeyLengths map[string]int `json:"keyLengths"`
+		Immutable  bool           `json:"immutable"`
+	}{
+		SecretInfo: &secretInfo,
+		KeyLengths: keyLengths,
+		Immutable:  secret.Immutable != nil && *secret.Immutable,
+	}
+
+	data, err := json.MarshalIndent(secretDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal secret details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// GetConfigMapKey returns the value of a single ConfigMap key. Keys from binaryData are
+// returned as a blob so binary content is not mangled into text. The MIME type is taken
+// from the key's file extension, falling back to content sniffing for binary values.
+func (c *Client) GetConfigMapKey(ctx context.Context, namespace, name, key string) (*types.ResourceContent, error) {
+	configmap, err := retryOnTransient(ctx, func() (*corev1.ConfigMap, error) {
+		return c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, name, err)
+	}
+
+	identifier := types.ResourceIdentifier{Type: types.ResourceTypeConfigMap, Namespace: namespace, Name: name}
+	content := &types.ResourceContent{
+		URI:      identifier.ToURI(),
+		MimeType: mime.TypeByExtension(filepath.Ext(key)),
+	}
+
+	if value, ok := configmap.BinaryData[key]; ok {
+		content.Blob = value
+		if content.MimeType == "" {
+			content.MimeType = http.DetectContentType(value)
+		}
+		return content, nil
+	}
+
+	if value, ok := configmap.Data[key]; ok {
+		content.Text = value
+		if content.MimeType == "" {
+			content.MimeType = "text/plain"
+		}
+		return content, nil
+	}
+
+	return nil, fmt.Errorf("key %q not found in configmap %s/%s", key, namespace, name)
+}
+
 func (c *Client) getNamespaceDetails(ctx context.Context, name string) (string, error) {
-	namespace, err := c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	namespace, err := retryOnTransient(ctx, func() (*corev1.Namespace, error) {
+		return c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get namespace %s: %w", name, err)
 	}
@@ -384,12 +858,215 @@ func (c *Client) getNamespaceDetails(ctx context.Context, name string) (string,
 }
 
 // Helper functions
+func newPodInfo(pod *corev1.Pod) PodInfo {
+	ownerKind, ownerName := podOwner(pod)
+	return PodInfo{
+		Name:         pod.Name,
+		Namespace:    pod.Namespace,
+		Status:       string(pod.Status.Phase),
+		Phase:        string(pod.Status.Phase),
+		Node:         pod.Spec.NodeName,
+		Labels:       pod.Labels,
+		CreatedAt:    pod.CreationTimestamp.Time,
+		Restarts:     getTotalRestarts(pod),
+		CrashLooping: DetectCrashLoop(pod),
+		QOSClass:     string(pod.Status.QOSClass),
+		OwnerKind:    ownerKind,
+		OwnerName:    ownerName,
+		Containers:   getContainerInfo(pod),
+	}
+}
+
+// podOwner returns the kind and name of the controller managing pod. A ReplicaSet created
+// by a Deployment is named after it with the pod template hash appended, so such pods are
+// attributed to the Deployment without looking the ReplicaSet up.
+func podOwner(pod *corev1.Pod) (string, string) {
+	controller := metav1.GetControllerOf(pod)
+	if controller == nil {
+		return "", ""
+	}
+	if controller.Kind == "ReplicaSet" {
+		hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]
+		if deployment, ok := strings.CutSuffix(controller.Name, "-"+hash); ok && hash != "" && deployment != "" {
+			return "Deployment", deployment
+		}
+	}
+	return controller.Kind, controller.Name
+}
+
+func newServiceInfo(svc *corev1.Service) ServiceInfo {
+	var ports []ServicePort
+	for _, port := range svc.Spec.Ports {
+		ports = append(ports, ServicePort{
+			Name:       port.Name,
+			Port:       port.Port,
+			TargetPort: port.TargetPort.String(),
+			Protocol:   string(port.Protocol),
+		})
+	}
+
+	return ServiceInfo{
+		Name:      svc.Name,
+		Namespace: svc.Namespace,
+		Type:      string(svc.Spec.Type),
+		ClusterIP: svc.Spec.ClusterIP,
+		Ports:     ports,
+		Labels:    svc.Labels,
+		CreatedAt: svc.CreationTimestamp.Time,
+	}
+}
+
 type ContainerInfo struct {
 	Name     string `json:"name"`
 	Image    string `json:"image"`
 	Ready    bool   `json:"ready"`
 	Restarts int32  `json:"restarts"`
 	State    string `json:"state"`
+	// Requests and Limits hold the container's CPU and memory settings, e.g. "250m" and
+	// "512Mi"; an unset resource is omitted
+	Requests map[string]string `json:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty"`
+	// ExitCode is the exit code of the container's most recent termination: the current
+	// one if the container is terminated, otherwise the previous instance's
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// LastTerminationReason is why the previous instance terminated, e.g. OOMKilled
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+}
+
+func newStatefulSetInfo(statefulSet *appsv1.StatefulSet) StatefulSetInfo {
+	var replicas int32 = 1
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	return StatefulSetInfo{
+		Name:            statefulSet.Name,
+		Namespace:       statefulSet.Namespace,
+		Replicas:        replicas,
+		ReadyReplicas:   statefulSet.Status.ReadyReplicas,
+		CurrentRevision: statefulSet.Status.CurrentRevision,
+		UpdateRevision:  statefulSet.Status.UpdateRevision,
+		ServiceName:     statefulSet.Spec.ServiceName,
+		Labels:          statefulSet.Labels,
+		CreatedAt:       statefulSet.CreationTimestamp.Time,
+	}
+}
+
+func newDaemonSetInfo(daemonSet *appsv1.DaemonSet) DaemonSetInfo {
+	return DaemonSetInfo{
+		Name:             daemonSet.Name,
+		Namespace:        daemonSet.Namespace,
+		DesiredScheduled: daemonSet.Status.DesiredNumberScheduled,
+		CurrentScheduled: daemonSet.Status.CurrentNumberScheduled,
+		Ready:            daemonSet.Status.NumberReady,
+		Available:        daemonSet.Status.NumberAvailable,
+		UpdateStrategy:   string(daemonSet.Spec.UpdateStrategy.Type),
+		Labels:           daemonSet.Labels,
+		CreatedAt:        daemonSet.CreationTimestamp.Time,
+	}
+}
+
+func newSecretInfo(secret *corev1.Secret) SecretInfo {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return SecretInfo{
+		Name:      secret.Name,
+		Namespace: secret.Namespace,
+		Type:      string(secret.Type),
+		Keys:      keys,
+		Labels:    secret.Labels,
+		CreatedAt: secret.CreationTimestamp.Time,
+	}
+}
+
+// maxPodEvents caps how many of a pod's most recent events its details include
+const maxPodEvents = 20
+
+// newEventInfo converts an event. Events written through the events.k8s.io API leave
+// the legacy timestamps empty and set EventTime instead.
+func newEventInfo(event *corev1.Event) EventInfo {
+	eventInfo := EventInfo{
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Count:          event.Count,
+		FirstTimestamp: event.FirstTimestamp.Time,
+		LastTimestamp:  event.LastTimestamp.Time,
+	}
+	if eventInfo.FirstTimestamp.IsZero() {
+		eventInfo.FirstTimestamp = event.EventTime.Time
+	}
+	if eventInfo.LastTimestamp.IsZero() {
+		eventInfo.LastTimestamp = eventInfo.FirstTimestamp
+		if event.Series != nil {
+			eventInfo.LastTimestamp = event.Series.LastObservedTime.Time
+			eventInfo.Count = event.Series.Count
+		}
+	}
+	return eventInfo
+}
+
+// nodeRolePrefix is the label prefix kubeadm and most distributions use to mark node roles
+const nodeRolePrefix = "node-role.kubernetes.io/"
+
+func newNodeInfo(node *corev1.Node) NodeInfo {
+	var roles []string
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, nodeRolePrefix); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	if role := node.Labels["kubernetes.io/role"]; role != "" && len(roles) == 0 {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	conditions := make(map[string]string, len(node.Status.Conditions))
+	for _, condition := range node.Status.Conditions {
+		conditions[string(condition.Type)] = string(condition.Status)
+	}
+
+	var taints []string
+	for _, taint := range node.Spec.Taints {
+		taintStr := taint.Key
+		if taint.Value != "" {
+			taintStr += "=" + taint.Value
+		}
+		taints = append(taints, taintStr+":"+string(taint.Effect))
+	}
+
+	return NodeInfo{
+		Name:              node.Name,
+		Roles:             roles,
+		KubeletVersion:    node.Status.NodeInfo.KubeletVersion,
+		OSImage:           node.Status.NodeInfo.OSImage,
+		Conditions:        conditions,
+		AllocatableCPU:    node.Status.Allocatable.Cpu().String(),
+		AllocatableMemory: node.Status.Allocatable.Memory().String(),
+		Taints:            taints,
+		Unschedulable:     node.Spec.Unschedulable,
+		Labels:            node.Labels,
+		CreatedAt:         node.CreationTimestamp.Time,
+	}
+}
+
+// formatToleration renders a toleration as key=value:effect, e.g. "node-role.kubernetes.io/control-plane:NoSchedule"
+func formatToleration(toleration corev1.Toleration) string {
+	if toleration.Operator == corev1.TolerationOpExists && toleration.Key == "" {
+		return "*"
+	}
+	result := toleration.Key
+	if toleration.Value != "" {
+		result += "=" + toleration.Value
+	}
+	if toleration.Effect != "" {
+		result += ":" + string(toleration.Effect)
+	}
+	return result
 }
 
 func getContainerInfo(pod *corev1.Pod) []ContainerInfo {
@@ -397,8 +1074,10 @@ func getContainerInfo(pod *corev1.Pod) []ContainerInfo {
 
 	for i, container := range pod.Spec.Containers {
 		info := ContainerInfo{
-			Name:  container.Name,
-			Image: container.Image,
+			Name:     container.Name,
+			Image:    container.Image,
+			Requests: cpuAndMemory(container.Resources.Requests),
+			Limits:   cpuAndMemory(container.Resources.Limits),
 		}
 
 		if i < len(pod.Status.ContainerStatuses) {
@@ -413,6 +1092,14 @@ func getContainerInfo(pod *corev1.Pod) []ContainerInfo {
 			} else if status.State.Terminated != nil {
 				info.State = fmt.Sprintf("Terminated: %s", status.State.Terminated.Reason)
 			}
+
+			if last := status.LastTerminationState.Terminated; last != nil {
+				info.LastTerminationReason = last.Reason
+				info.ExitCode = &last.ExitCode
+			}
+			if current := status.State.Terminated; current != nil {
+				info.ExitCode = &current.ExitCode
+			}
 		}
 
 		containers = append(containers, info)
@@ -421,6 +1108,20 @@ func getContainerInfo(pod *corev1.Pod) []ContainerInfo {
 	return containers
 }
 
+// cpuAndMemory returns the CPU and memory quantities in resources, or nil if neither is set
+func cpuAndMemory(resources corev1.ResourceList) map[string]string {
+	var quantities map[string]string
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if quantity, ok := resources[name]; ok {
+			if quantities == nil {
+				quantities = make(map[string]string, 2)
+			}
+			quantities[string(name)] = quantity.String()
+		}
+	}
+	return quantities
+}
+
 func getTotalRestarts(pod *corev1.Pod) int32 {
 	var total int32
 	for _, status := range pod.Status.ContainerStatuses {
@@ -450,36 +1151,47 @@ func getDeploymentConditions(deployment *appsv1.Deployment) []string {
 }
 
 // Tools
-// ScaleDeployment scales a deployment to the specified number of replicas
-func (c *Client) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32) (*appsv1.Deployment, error) {
+// ScaleDeployment scales a deployment to the specified number of replicas. With dryRun
+// set the API server validates and returns the update without persisting it.
+func (c *Client) ScaleDeployment(ctx context.Context, namespace, name string, replicas int32, dryRun bool) (*appsv1.Deployment, error) {
 	start := time.Now()
 	defer func() {
-		c.logger.LogK8sOperation("scale_deployment", namespace, fmt.Sprintf("%s->%d", name, replicas), time.Since(start), nil)
+		c.logger.LogK8sOperation(ctx, "scale_deployment", namespace, fmt.Sprintf("%s->%d", name, replicas), time.Since(start), nil)
 	}()
 
-	// Get current deployment
-	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
-	}
+	// The update carries the resourceVersion that was read, so if the deployment changed
+	// in between, read it again and reapply the replica count
+	var updatedDeployment *appsv1.Deployment
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Get current deployment
+		deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+		}
 
-	// Update replica count
-	deployment.Spec.Replicas = &replicas
+		// Update replica count
+		deployment.Spec.Replicas = &replicas
 
-	// Apply the update
-	updatedDeployment, err := c.clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+		// Apply the update
+		updatedDeployment, err = c.clientset.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
+		if err != nil {
+			return fmt.Errorf("failed to scale deployment %s/%s to %d replicas: %w", namespace, name, replicas, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to scale deployment %s/%s to %d replicas: %w", namespace, name, replicas, err)
+		return nil, err
 	}
 
 	return updatedDeployment, nil
 }
 
-// RestartDeployment restarts a deployment by updating its restart annotation
-func (c *Client) RestartDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+// RestartDeployment restarts a deployment by updating its restart annotation. With dryRun
+// set the patch is validated but not persisted, so no pods are replaced.
+func (c *Client) RestartDeployment(ctx context.Context, namespace, name string, dryRun bool) (*appsv1.Deployment, error) {
 	start := time.Now()
 	defer func() {
-		c.logger.LogK8sOperation("restart_deployment", namespace, name, time.Since(start), nil)
+		c.logger.LogK8sOperation(ctx, "restart_deployment", namespace, name, time.Since(start), nil)
 	}()
 
 	// Create restart annotation with current timestamp
@@ -498,14 +1210,19 @@ func (c *Client) RestartDeployment(ctx context.Context, namespace, name string)
 		}
 	}`, toJSON(restartAnnotation))
 
-	// Apply strategic merge patch
-	deployment, err := c.clientset.AppsV1().Deployments(namespace).Patch(
-		ctx,
-		name,
-		typesv1.StrategicMergePatchType,
-		[]byte(patchData),
-		metav1.PatchOptions{},
-	)
+	// Apply strategic merge patch, retrying if it races another writer
+	var deployment *appsv1.Deployment
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var err error
+		deployment, err = c.clientset.AppsV1().Deployments(namespace).Patch(
+			ctx,
+			name,
+			typesv1.StrategicMergePatchType,
+			[]byte(patchData),
+			metav1.PatchOptions{DryRun: dryRunOption(dryRun)},
+		)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to restart deployment %s/%s: %w", namespace, name, err)
 	}
@@ -514,14 +1231,17 @@ func (c *Client) RestartDeployment(ctx context.Context, namespace, name string)
 }
 
 // GetPodLogs retrieves logs from a pod with optional filtering
-func (c *Client) GetPodLogs(ctx context.Context, namespace, podName, containerName string, tailLines *int64, sinceSeconds *int64) (string, error) {
+// GetPodLogs returns a snapshot of a container's logs. With previous set it returns the
+// logs of the container's last terminated instance, which is where a crash-looping
+// container's error output ends up.
+func (c *Client) GetPodLogs(ctx context.Context, namespace, podName, containerName string, tailLines *int64, sinceSeconds *int64, previous bool) (string, error) {
 	start := time.Now()
 	defer func() {
-		c.logger.LogK8sOperation("get_pod_logs", namespace, podName, time.Since(start), nil)
+		c.logger.LogK8sOperation(ctx, "get_pod_logs", namespace, podName, time.Since(start), nil)
 	}()
 
 	// Build log options
-	logOptions := &corev1.PodLogOptions{}
+	logOptions := &corev1.PodLogOptions{Previous: previous}
 
 	if containerName != "" {
 		logOptions.Container = containerName
@@ -560,11 +1280,13 @@ func (c *Client) GetPodLogs(ctx context.Context, namespace, podName, containerNa
 	return string(logs), nil
 }
 
-// CreateOrUpdateConfigMap creates a new ConfigMap or updates an existing one
-func (c *Client) CreateOrUpdateConfigMap(ctx context.Context, namespace, name string, data map[string]string, labels map[string]string) (*corev1.ConfigMap, error) {
+// CreateOrUpdateConfigMap creates a new ConfigMap or updates an existing one. Text values
+// go in data and values that are not UTF-8, such as keystores, in binaryData; either may
+// be nil. With dryRun set the create or update is validated but not persisted.
+func (c *Client) CreateOrUpdateConfigMap(ctx context.Context, namespace, name string, data map[string]string, binaryData map[string][]byte, labels map[string]string, dryRun bool) (*corev1.ConfigMap, error) {
 	start := time.Now()
 	defer func() {
-		c.logger.LogK8sOperation("create_update_configmap", namespace, name, time.Since(start), nil)
+		c.logger.LogK8sOperation(ctx, "create_update_configmap", namespace, name, time.Since(start), nil)
 	}()
 
 	configMap := &corev1.ConfigMap{
@@ -573,15 +1295,24 @@ func (c *Client) CreateOrUpdateConfigMap(ctx context.Context, namespace, name st
 			Namespace: namespace,
 			Labels:    labels,
 		},
-		Data: data,
+		Data:       data,
+		BinaryData: binaryData,
+	}
+
+	// A new ConfigMap counts against object-count quotas; check before creating so the
+	// caller gets a clear quota message instead of a raw admission error
+	if _, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		if err := c.CheckObjectCountQuota(ctx, namespace, corev1.SchemeGroupVersion.WithResource("configmaps")); err != nil {
+			return nil, err
+		}
 	}
 
 	// Try to create first
-	createdCM, err := c.clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	createdCM, err := c.clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{DryRun: dryRunOption(dryRun)})
 	if err != nil {
 		// If already exists, update it
 		if strings.Contains(err.Error(), "already exists") {
-			updatedCM, updateErr := c.clientset.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+			updatedCM, updateErr := c.clientset.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
 			if updateErr != nil {
 				return nil, fmt.Errorf("failed to update existing ConfigMap %s/%s: %w", namespace, name, updateErr)
 			}
@@ -593,14 +1324,55 @@ func (c *Client) CreateOrUpdateConfigMap(ctx context.Context, namespace, name st
 	return createdCM, nil
 }
 
-// DeletePod deletes a specific pod
-func (c *Client) DeletePod(ctx context.Context, namespace, name string, force bool) error {
+// CreateOrUpdateSecret creates a secret, or replaces the data of an existing one. Values
+// are raw bytes; client-go base64-encodes them on the wire. An empty
+// secretType creates an Opaque secret. The result describes the secret by its key names
+// only, so values never leave this function.
+func (c *Client) CreateOrUpdateSecret(ctx context.Context, namespace, name string, data map[string][]byte, secretType string, dryRun bool) (*SecretInfo, error) {
 	start := time.Now()
 	defer func() {
-		c.logger.LogK8sOperation("delete_pod", namespace, name, time.Since(start), nil)
+		c.logger.LogK8sOperation(ctx, "create_update_secret", namespace, name, time.Since(start), nil)
 	}()
 
-	deleteOptions := metav1.DeleteOptions{}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretType(secretType),
+		Data: data,
+	}
+
+	if _, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{}); apierrors.IsNotFound(err) {
+		if err := c.CheckObjectCountQuota(ctx, namespace, corev1.SchemeGroupVersion.WithResource("secrets")); err != nil {
+			return nil, err
+		}
+	}
+
+	saved, err := c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{DryRun: dryRunOption(dryRun)})
+	if apierrors.IsAlreadyExists(err) {
+		saved, err = c.clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{DryRun: dryRunOption(dryRun)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update existing Secret %s/%s: %w", namespace, name, err)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Secret %s/%s: %w", namespace, name, err)
+	}
+
+	info := newSecretInfo(saved)
+	return &info, nil
+}
+
+// DeletePod deletes a specific pod. With dryRun set the API server checks the deletion
+// would succeed but leaves the pod running.
+func (c *Client) DeletePod(ctx context.Context, namespace, name string, force, dryRun bool) error {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "delete_pod", namespace, name, time.Since(start), nil)
+	}()
+
+	deleteOptions := metav1.DeleteOptions{DryRun: dryRunOption(dryRun)}
 	if force {
 		gracePeriodSeconds := int64(0)
 		deleteOptions.GracePeriodSeconds = &gracePeriodSeconds
@@ -614,19 +1386,190 @@ func (c *Client) DeletePod(ctx context.Context, namespace, name string, force bo
 	return nil
 }
 
-// GetPodContainers returns the list of container names in a pod
-func (c *Client) GetPodContainers(ctx context.Context, namespace, name string) ([]string, error) {
-	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+// DeleteDeployment deletes a deployment and returns how many pods it owned through its
+// ReplicaSets. With cascade the deletion is foreground: the ReplicaSets and their pods
+// are removed before the deployment is. Without it they are orphaned and keep running.
+func (c *Client) DeleteDeployment(ctx context.Context, namespace, name string, cascade bool) (int, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "delete_deployment", namespace, name, time.Since(start), nil)
+	}()
+
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+	}
+
+	replicaSets, err := c.deploymentReplicaSets(ctx, deployment)
+	if err != nil {
+		return 0, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+		return 0, fmt.Errorf("invalid selector on deployment %s/%s: %w", namespace, name, err)
+	}
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pods of deployment %s/%s: %w", namespace, name, err)
+	}
+	ownedPods := countControlledPods(pods.Items, replicaSets)
+
+	propagation := metav1.DeletePropagationOrphan
+	if cascade {
+		propagation = metav1.DeletePropagationForeground
+	}
+	// The UID precondition keeps a deployment recreated since the count from being deleted
+	err = c.clientset.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+		Preconditions:     metav1.NewUIDPreconditions(string(deployment.UID)),
+	})
+	if err != nil {
+		return ownedPods, fmt.Errorf("failed to delete deployment %s/%s: %w", namespace, name, err)
+	}
+
+	return ownedPods, nil
+}
+
+// countControlledPods counts the pods controlled by one of replicaSets
+func countControlledPods(pods []corev1.Pod, replicaSets []*appsv1.ReplicaSet) int {
+	count := 0
+	for i := range pods {
+		for _, replicaSet := range replicaSets {
+			if metav1.IsControlledBy(&pods[i], replicaSet) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// GetAllPodLogs returns the logs of every container in a pod, each under a
+// "=== container: <name> ===" header, skipping those named in sidecars. tailLines and
+// sinceSeconds apply to each container separately. A container whose logs cannot be
+// read, for example one with no previous instance, gets its error under its header
+// instead of failing the whole call.
+func (c *Client) GetAllPodLogs(ctx context.Context, namespace, podName string, tailLines *int64, sinceSeconds *int64, previous bool, sidecars []string) (string, []string, error) {
+	all, _, err := c.GetPodContainers(ctx, namespace, podName, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	containers := make([]string, 0, len(all))
+	for _, container := range all {
+		if !slices.Contains(sidecars, container) {
+			containers = append(containers, container)
+		}
+	}
+
+	var logs strings.Builder
+	for i, container := range containers {
+		if i > 0 {
+			logs.WriteString("\n")
+		}
+		logs.WriteString(fmt.Sprintf("=== container: %s ===\n", container))
+
+		containerLogs, err := c.GetPodLogs(ctx, namespace, podName, container, tailLines, sinceSeconds, previous)
+		if err != nil {
+			logs.WriteString(fmt.Sprintf("(failed to get logs: %v)\n", err))
+			continue
+		}
+		logs.WriteString(containerLogs)
+		if containerLogs != "" && !strings.HasSuffix(containerLogs, "\n") {
+			logs.WriteString("\n")
+		}
+	}
+
+	return logs.String(), containers, nil
+}
+
+// GetPodContainers returns the names of a pod's containers followed by its init
+// containers, whose logs can be read the same way, along with the pod's main container;
+// see mainContainer
+func (c *Client) GetPodContainers(ctx context.Context, namespace, name string, sidecars []string) ([]string, string, error) {
+	pod, err := c.getPod(ctx, namespace, name)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
 	}
 
 	var containers []string
 	for _, container := range pod.Spec.Containers {
 		containers = append(containers, container.Name)
 	}
+	for _, container := range pod.Spec.InitContainers {
+		containers = append(containers, container.Name)
+	}
+
+	return containers, mainContainer(pod, sidecars), nil
+}
+
+// mainContainer returns the container to read logs from when none is named: the one the
+// kubectl.kubernetes.io/default-container annotation names, otherwise the first container
+// not named in sidecars, such as a service mesh proxy, otherwise the first container
+func mainContainer(pod *corev1.Pod, sidecars []string) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	if annotated := pod.Annotations[defaultContainerAnnotation]; annotated != "" {
+		for _, container := range pod.Spec.Containers {
+			if container.Name == annotated {
+				return annotated
+			}
+		}
+	}
+	for _, container := range pod.Spec.Containers {
+		if !slices.Contains(sidecars, container.Name) {
+			return container.Name
+		}
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+// defaultContainerAnnotation names the container kubectl logs and exec use by default
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
 
-	return containers, nil
+// readBackoff spaces out the retries of a read that failed transiently: four attempts
+// over about a second and a half
+var readBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// retryOnTransient runs a read, retrying with exponential backoff while it fails with an
+// error that is likely to clear up, such as while the API server is rolling out. Only
+// reads may be retried this way; a mutation that timed out may still have been applied.
+func retryOnTransient[T any](ctx context.Context, read func() (T, error)) (T, error) {
+	var result T
+	err := retry.OnError(readBackoff, func(err error) bool {
+		return ctx.Err() == nil && isTransient(err)
+	}, func() error {
+		var err error
+		result, err = read()
+		return err
+	})
+	return result, err
+}
+
+// isTransient reports whether an API error is likely to succeed on retry
+func isTransient(err error) bool {
+	return apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		utilnet.IsConnectionRefused(err) ||
+		utilnet.IsConnectionReset(err) ||
+		// etcd timeouts reach clients as internal errors
+		(apierrors.IsInternalError(err) && strings.Contains(err.Error(), "etcdserver: request timed out"))
+}
+
+// dryRunOption returns the DryRun value for create, update, patch and delete options:
+// server-side dry run of every stage when dryRun is set, none otherwise
+func dryRunOption(dryRun bool) []string {
+	if dryRun {
+		return []string{metav1.DryRunAll}
+	}
+	return nil
 }
 
 // Helper function to convert map to JSON string
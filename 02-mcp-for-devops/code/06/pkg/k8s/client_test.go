@@ -0,0 +1,128 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	typesv1 "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func withFastReadBackoff(t *testing.T) {
+	saved := readBackoff
+	readBackoff = wait.Backoff{Steps: 4, Duration: time.Millisecond, Factor: 2}
+	t.Cleanup(func() { readBackoff = saved })
+}
+
+func TestRetryOnTransientRetriesServerTimeouts(t *testing.T) {
+	withFastReadBackoff(t)
+
+	attempts := 0
+	got, err := retryOnTransient(context.Background(), func() (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", apierrors.NewServerTimeout(schema.GroupResource{Resource: "pods"}, "list", 1)
+		}
+		return "pods", nil
+	})
+	if err != nil || got != "pods" {
+		t.Fatalf("retryOnTransient = %q, %v; want pods, nil", got, err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestRetryOnTransientRetriesEtcdTimeouts(t *testing.T) {
+	withFastReadBackoff(t)
+
+	attempts := 0
+	_, err := retryOnTransient(context.Background(), func() (string, error) {
+		attempts++
+		return "", apierrors.NewInternalError(errors.New("etcdserver: request timed out"))
+	})
+	if err == nil {
+		t.Fatal("expected the last error once retries ran out")
+	}
+	if attempts != readBackoff.Steps {
+		t.Errorf("attempts = %d, want %d", attempts, readBackoff.Steps)
+	}
+}
+
+func TestRetryOnTransientReturnsPermanentErrorsAtOnce(t *testing.T) {
+	withFastReadBackoff(t)
+
+	attempts := 0
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "web")
+	_, err := retryOnTransient(context.Background(), func() (string, error) {
+		attempts++
+		return "", fmt.Errorf("failed to get pod: %w", notFound)
+	})
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("err = %v, want the not-found error", err)
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestCountControlledPods(t *testing.T) {
+	replicaSet := func(name string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name, UID: typesv1.UID(name)}}
+	}
+	pod := func(owner *appsv1.ReplicaSet) corev1.Pod {
+		var pod corev1.Pod
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"))}
+		}
+		return pod
+	}
+	current, previous, other := replicaSet("web-7d9f"), replicaSet("web-5c8b"), replicaSet("api-6f4d")
+
+	pods := []corev1.Pod{pod(current), pod(current), pod(previous), pod(other), pod(nil)}
+	if got := countControlledPods(pods, []*appsv1.ReplicaSet{current, previous}); got != 3 {
+		t.Errorf("countControlledPods = %d, want 3", got)
+	}
+}
+
+func TestHealthCheckHonorsContextDeadline(t *testing.T) {
+	// An API server that never answers, as during a network partition
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewForConfig: %v", err)
+	}
+	c := &Client{clientset: clientset}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = c.HealthCheck(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("HealthCheck error = %v, want a deadline exceeded error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("HealthCheck returned after %s, want it to stop at the 100ms deadline", elapsed)
+	}
+}
@@ -0,0 +1,145 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// metricsAPIGroup is served by metrics-server, which the top tools need
+const metricsAPIGroup = "metrics.k8s.io"
+
+// knownExtensions maps the API groups of commonly installed add-ons to the name reported
+// for them in ClusterInfo.Extensions
+var knownExtensions = map[string]string{
+	"cert-manager.io":           "certManager",
+	"monitoring.coreos.com":     "prometheusOperator",
+	"networking.istio.io":       "istio",
+	"policy.linkerd.io":         "linkerd",
+	"gateway.networking.k8s.io": "gatewayAPI",
+	"argoproj.io":               "argo",
+	"keda.sh":                   "keda",
+	"autoscaling.k8s.io":        "verticalPodAutoscaler",
+	"snapshot.storage.k8s.io":   "volumeSnapshots",
+	"external-secrets.io":       "externalSecrets",
+}
+
+// GetClusterInfo describes the connected cluster: its version, nodes and capacity, the
+// API groups it serves, and whether metrics-server and common add-ons are installed.
+// Nodes and metrics are best effort; what could not be read is listed in Warnings.
+func (c *Client) GetClusterInfo(ctx context.Context) (*ClusterInfo, error) {
+	serverVersion, err := c.serverVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	groups, err := c.serverGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API groups: %w", err)
+	}
+
+	info := &ClusterInfo{
+		ServerVersion: serverVersion.String(),
+		Platform:      serverVersion.Platform,
+		BuildDate:     serverVersion.BuildDate,
+		APIGroups:     groups,
+		Extensions:    detectExtensions(groups),
+	}
+
+	nodes, err := retryOnTransient(ctx, func() (*corev1.NodeList, error) {
+		return c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("failed to list nodes: %v", err))
+	} else {
+		info.NodeCount = len(nodes.Items)
+		info.ReadyNodes, info.Capacity, info.Allocatable = sumNodeResources(nodes.Items)
+	}
+
+	// The group can be registered while metrics-server itself is down, so only a metrics
+	// read shows that the top tools will work
+	if slices.Contains(groups, metricsAPIGroup) {
+		_, err := c.metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{Limit: 1})
+		if err != nil {
+			info.Warnings = append(info.Warnings, metricsError("metrics-server is registered but not answering", err).Error())
+		} else {
+			info.MetricsServer = true
+		}
+	}
+
+	return info, nil
+}
+
+// serverGroups lists the API groups the server serves, with "" for the core group. Unlike
+// Discovery().ServerGroups, the request is cancelled with ctx.
+func (c *Client) serverGroups(ctx context.Context) ([]string, error) {
+	body, err := c.clientset.Discovery().RESTClient().Get().AbsPath("/apis").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	var groupList metav1.APIGroupList
+	if err := json.Unmarshal(body, &groupList); err != nil {
+		return nil, fmt.Errorf("failed to decode API groups: %w", err)
+	}
+
+	groups := []string{""}
+	for _, group := range groupList.Groups {
+		groups = append(groups, group.Name)
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+// detectExtensions reports, for every add-on in knownExtensions, whether its API group is
+// among groups
+func detectExtensions(groups []string) map[string]bool {
+	extensions := make(map[string]bool, len(knownExtensions))
+	for _, name := range knownExtensions {
+		extensions[name] = false
+	}
+	for _, group := range groups {
+		if name, ok := knownExtensions[group]; ok {
+			extensions[name] = true
+		}
+	}
+	return extensions
+}
+
+// sumNodeResources counts the ready nodes and totals their capacity and allocatable
+// resources
+func sumNodeResources(nodes []corev1.Node) (ready int, capacity, allocatable ResourceTotals) {
+	var capacityList, allocatableList []corev1.ResourceList
+	for _, node := range nodes {
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready++
+			}
+		}
+		capacityList = append(capacityList, node.Status.Capacity)
+		allocatableList = append(allocatableList, node.Status.Allocatable)
+	}
+	return ready, sumResources(capacityList), sumResources(allocatableList)
+}
+
+// sumResources totals the CPU, memory and pod counts of lists
+func sumResources(lists []corev1.ResourceList) ResourceTotals {
+	var cpu, memory, pods resource.Quantity
+	for _, list := range lists {
+		cpu.Add(*list.Cpu())
+		memory.Add(*list.Memory())
+		pods.Add(*list.Pods())
+	}
+	totals := ResourceTotals{
+		CPUMillicores: cpu.MilliValue(),
+		MemoryBytes:   memory.Value(),
+		Pods:          pods.Value(),
+	}
+	totals.CPU, totals.Memory = formatCPU(totals.CPUMillicores), formatMemory(totals.MemoryBytes)
+	return totals
+}
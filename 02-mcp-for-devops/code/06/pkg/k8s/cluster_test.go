@@ -0,0 +1,54 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestDetectExtensions(t *testing.T) {
+	extensions := detectExtensions([]string{"", "apps", "cert-manager.io", "gateway.networking.k8s.io"})
+
+	if !extensions["certManager"] || !extensions["gatewayAPI"] {
+		t.Errorf("installed add-ons should be detected, got %v", extensions)
+	}
+	if installed, listed := extensions["istio"]; installed || !listed {
+		t.Errorf("missing add-ons should be reported as false, got istio=%v listed=%v", installed, listed)
+	}
+}
+
+func TestSumNodeResources(t *testing.T) {
+	node := func(ready corev1.ConditionStatus, cpu, memory string) corev1.Node {
+		resources := corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}
+		return corev1.Node{Status: corev1.NodeStatus{
+			Capacity:    resources,
+			Allocatable: resources,
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		}}
+	}
+	nodes := []corev1.Node{
+		node(corev1.ConditionTrue, "4", "8Gi"),
+		node(corev1.ConditionFalse, "2", "4Gi"),
+	}
+	nodes[0].Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("3500m"),
+		corev1.ResourceMemory: resource.MustParse("7Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+
+	ready, capacity, allocatable := sumNodeResources(nodes)
+	if ready != 1 {
+		t.Errorf("ready = %d, want 1", ready)
+	}
+	if capacity.CPUMillicores != 6000 || capacity.Memory != "12288Mi" || capacity.Pods != 220 {
+		t.Errorf("capacity = %+v, want 6000m, 12288Mi and 220 pods", capacity)
+	}
+	if allocatable.CPUMillicores != 5500 || allocatable.Memory != "11264Mi" {
+		t.Errorf("allocatable = %+v, want 5500m and 11264Mi", allocatable)
+	}
+}
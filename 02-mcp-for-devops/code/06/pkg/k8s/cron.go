@@ -0,0 +1,187 @@
+package k8s
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed standard five-field cron expression, the format accepted by
+// CronJob schedules. Each field is a bitset of the values it matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record a "*" or "?" day field. As in cron, when both day
+	// fields are restricted a time matches if either one does.
+	domStar, dowStar bool
+	location         *time.Location
+}
+
+// cronSearchYears bounds how far ahead nextCronTime looks, so schedules that can never
+// fire, such as "0 0 30 2 *", terminate
+const cronSearchYears = 5
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	cronDayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// parseCronSchedule parses a CronJob schedule. A CRON_TZ= or TZ= prefix in the schedule
+// overrides timeZone; with neither, times are computed in UTC.
+func parseCronSchedule(spec, timeZone string) (*cronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		prefix, rest, _ := strings.Cut(spec, " ")
+		_, timeZone, _ = strings.Cut(prefix, "=")
+		spec = strings.TrimSpace(rest)
+	}
+
+	location := time.UTC
+	if timeZone != "" {
+		loc, err := time.LoadLocation(timeZone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q: %w", timeZone, err)
+		}
+		location = loc
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		expanded, ok := cronMacros[spec]
+		if !ok {
+			return nil, fmt.Errorf("unsupported schedule %q", spec)
+		}
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule %q must have 5 fields, got %d", spec, len(fields))
+	}
+
+	schedule := &cronSchedule{location: location}
+	var err error
+	if schedule.minute, _, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hour, _, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.dom, schedule.domStar, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %w", err)
+	}
+	if schedule.month, _, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if schedule.dow, schedule.dowStar, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %w", err)
+	}
+	// 7 is an alias for Sunday
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+
+	return schedule, nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges, and steps, e.g.
+// "*/15", "1-5", or "mon,wed,fri", and reports whether the field starts with a wildcard
+func parseCronField(field string, min, max int, names map[string]int) (bits uint64, star bool, err error) {
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			low, high = min, max
+			star = true
+		default:
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+			if low, err = parseCronValue(lowPart, names); err != nil {
+				return 0, false, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseCronValue(highPart, names); err != nil {
+					return 0, false, err
+				}
+			} else if hasStep {
+				// "5/15" means every 15 starting at 5
+				high = max
+			}
+		}
+
+		step := 1
+		if hasStep {
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, false, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		if low < min || high > max || low > high {
+			return 0, false, fmt.Errorf("%q is outside %d-%d", item, min, max)
+		}
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+	return bits, star, nil
+}
+
+func parseCronValue(value string, names map[string]int) (int, error) {
+	if number, ok := names[strings.ToLower(value)]; ok {
+		return number, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return number, nil
+}
+
+// next returns the first time after t that matches the schedule, or the zero time if
+// there is none within cronSearchYears
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.In(s.location).Truncate(time.Second)
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second)
+	limit := t.Year() + cronSearchYears
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
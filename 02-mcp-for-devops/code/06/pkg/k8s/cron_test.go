@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday
+	from := time.Date(2024, time.January, 10, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		schedule string
+		timeZone string
+		want     time.Time
+	}{
+		{schedule: "*/15 * * * *", want: time.Date(2024, time.January, 10, 10, 15, 0, 0, time.UTC)},
+		{schedule: "0 2 * * *", want: time.Date(2024, time.January, 11, 2, 0, 0, 0, time.UTC)},
+		{schedule: "30 9 * * mon-fri", want: time.Date(2024, time.January, 11, 9, 30, 0, 0, time.UTC)},
+		{schedule: "0 0 * * 7", want: time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 1 */3 *", want: time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 12 15 * fri", want: time.Date(2024, time.January, 12, 12, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 29 2 *", want: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{schedule: "@monthly", want: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{schedule: "5/20 10 * * *", want: time.Date(2024, time.January, 10, 10, 25, 0, 0, time.UTC)},
+		{schedule: "0 9 * * *", timeZone: "America/New_York", want: time.Date(2024, time.January, 10, 14, 0, 0, 0, time.UTC)},
+		{schedule: "CRON_TZ=Asia/Tokyo 0 9 * * *", want: time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC)},
+		{schedule: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		schedule, err := parseCronSchedule(tt.schedule, tt.timeZone)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.schedule, err)
+			continue
+		}
+		if got := schedule.next(from); !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.schedule, got.UTC(), tt.want)
+		}
+	}
+}
+
+func TestParseCronScheduleRejectsInvalid(t *testing.T) {
+	for _, spec := range []string{"* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every 5m", "* * * * foo"} {
+		if _, err := parseCronSchedule(spec, ""); err == nil {
+			t.Errorf("%s: expected an error", spec)
+		}
+	}
+	if _, err := parseCronSchedule("* * * * *", "Not/AZone"); err == nil {
+		t.Error("expected an error for an unknown time zone")
+	}
+}
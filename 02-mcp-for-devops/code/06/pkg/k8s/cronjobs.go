@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxCronJobHistory caps how many of a CronJob's most recent Jobs its details include
+const maxCronJobHistory = 5
+
+func (c *Client) ListCronJobs(ctx context.Context, namespace string) ([]CronJobInfo, error) {
+	cronJobs, err := c.clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs in namespace %s: %w", namespace, err)
+	}
+
+	now := time.Now()
+	var cronJobInfos []CronJobInfo
+	for i := range cronJobs.Items {
+		cronJobInfos = append(cronJobInfos, newCronJobInfo(&cronJobs.Items[i], now))
+	}
+
+	return cronJobInfos, nil
+}
+
+func (c *Client) getCronJobDetails(ctx context.Context, namespace, name string) (string, error) {
+	cronJob, err := c.clientset.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get cronjob %s/%s: %w", namespace, name, err)
+	}
+
+	jobs, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list jobs in namespace %s: %w", namespace, err)
+	}
+	var recentJobs []JobInfo
+	for i := range jobs.Items {
+		if metav1.IsControlledBy(&jobs.Items[i], cronJob) {
+			recentJobs = append(recentJobs, newJobInfo(&jobs.Items[i]))
+		}
+	}
+	sort.Slice(recentJobs, func(i, j int) bool {
+		return recentJobs[i].CreatedAt.After(recentJobs[j].CreatedAt)
+	})
+	if len(recentJobs) > maxCronJobHistory {
+		recentJobs = recentJobs[:maxCronJobHistory]
+	}
+
+	var activeJobs []string
+	for _, ref := range cronJob.Status.Active {
+		activeJobs = append(activeJobs, ref.Name)
+	}
+
+	cronJobInfo := newCronJobInfo(cronJob, time.Now())
+	cronJobDetail := struct {
+		*CronJobInfo
+		ConcurrencyPolicy          string    `json:"concurrencyPolicy"`
+		StartingDeadlineSeconds    *int64    `json:"startingDeadlineSeconds,omitempty"`
+		SuccessfulJobsHistoryLimit *int32    `json:"successfulJobsHistoryLimit,omitempty"`
+		FailedJobsHistoryLimit     *int32    `json:"failedJobsHistoryLimit,omitempty"`
+		ActiveJobNames             []string  `json:"activeJobNames,omitempty"`
+		RecentJobs                 []JobInfo `json:"recentJobs"`
+	}{
+		CronJobInfo:                &cronJobInfo,
+		ConcurrencyPolicy:          string(cronJob.Spec.ConcurrencyPolicy),
+		StartingDeadlineSeconds:    cronJob.Spec.StartingDeadlineSeconds,
+		SuccessfulJobsHistoryLimit: cronJob.Spec.SuccessfulJobsHistoryLimit,
+		FailedJobsHistoryLimit:     cronJob.Spec.FailedJobsHistoryLimit,
+		ActiveJobNames:             activeJobs,
+		RecentJobs:                 recentJobs,
+	}
+
+	data, err := json.MarshalIndent(cronJobDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cronjob details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// newCronJobInfo builds a CronJobInfo, computing the next run after now from the
+// schedule. Suspended CronJobs have no next run.
+func newCronJobInfo(cronJob *batchv1.CronJob, now time.Time) CronJobInfo {
+	info := CronJobInfo{
+		Name:       cronJob.Name,
+		Namespace:  cronJob.Namespace,
+		Schedule:   cronJob.Spec.Schedule,
+		Suspend:    cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		ActiveJobs: len(cronJob.Status.Active),
+		Labels:     cronJob.Labels,
+		CreatedAt:  cronJob.CreationTimestamp.Time,
+	}
+
+	if cronJob.Spec.TimeZone != nil {
+		info.TimeZone = *cronJob.Spec.TimeZone
+	}
+	if cronJob.Status.LastScheduleTime != nil {
+		lastScheduleTime := cronJob.Status.LastScheduleTime.Time
+		info.LastScheduleTime = &lastScheduleTime
+	}
+	if cronJob.Status.LastSuccessfulTime != nil {
+		lastSuccessfulTime := cronJob.Status.LastSuccessfulTime.Time
+		info.LastSuccessfulTime = &lastSuccessfulTime
+	}
+
+	schedule, err := parseCronSchedule(info.Schedule, info.TimeZone)
+	if err != nil {
+		info.ScheduleError = err.Error()
+		return info
+	}
+	if !info.Suspend {
+		if next := schedule.next(now); !next.IsZero() {
+			info.NextScheduleTime = &next
+		}
+	}
+
+	return info
+}
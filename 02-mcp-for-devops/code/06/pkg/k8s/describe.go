@@ -0,0 +1,169 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"kubernetes-mcp-server/pkg/types"
+)
+
+// maxDescribeEvents bounds the events included when describing an unmodelled resource
+const maxDescribeEvents = 20
+
+// lastAppliedAnnotation holds a full copy of the object written by kubectl apply
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// modelledResources maps the API resources GetResource has typed details for
+var modelledResources = map[schema.GroupResource]types.K8sResourceType{
+	{Resource: "pods"}:                                           types.ResourceTypePod,
+	{Resource: "services"}:                                       types.ResourceTypeService,
+	{Resource: "configmaps"}:                                     types.ResourceTypeConfigMap,
+	{Resource: "namespaces"}:                                     types.ResourceTypeNamespace,
+	{Resource: "nodes"}:                                          types.ResourceTypeNode,
+	{Resource: "persistentvolumeclaims"}:                         types.ResourceTypePersistentVolumeClaim,
+	{Resource: "persistentvolumes"}:                              types.ResourceTypePersistentVolume,
+	{Group: "apps", Resource: "deployments"}:                     types.ResourceTypeDeployment,
+	{Group: "apps", Resource: "statefulsets"}:                    types.ResourceTypeStatefulSet,
+	{Group: "apps", Resource: "daemonsets"}:                      types.ResourceTypeDaemonSet,
+	{Group: "batch", Resource: "jobs"}:                           types.ResourceTypeJob,
+	{Group: "batch", Resource: "cronjobs"}:                       types.ResourceTypeCronJob,
+	{Group: "autoscaling", Resource: "horizontalpodautoscalers"}: types.ResourceTypeHPA,
+}
+
+// DescribeResource returns a detailed view of any resource in the cluster. kind accepts
+// the forms kubectl does: a kind ("Deployment"), a resource name ("deployments"), a
+// short name ("deploy"), or a group-qualified name ("certificates.cert-manager.io").
+// Types GetResource models are routed through it; anything else, including custom
+// resources, is fetched with the dynamic client and returned as JSON with its events.
+// namespace must be empty for cluster-scoped kinds, which are authorized cluster-wide.
+func (c *Client) DescribeResource(ctx context.Context, kind, namespace, name string) (string, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "describe_resource", namespace, name, time.Since(start), nil)
+	}()
+
+	mapping, err := c.resolveKind(kind)
+	if err != nil {
+		return "", err
+	}
+
+	groupResource := mapping.Resource.GroupResource()
+	if groupResource == (schema.GroupResource{Resource: "secrets"}) {
+		// Secret values must stay behind the secret tools' permission
+		return "", fmt.Errorf("secrets cannot be described; use k8s_get_secret instead")
+	}
+
+	namespaced := mapping.Scope.Name() == meta.RESTScopeNameNamespace
+	switch {
+	case !namespaced && namespace != "":
+		return "", fmt.Errorf("%s is cluster-scoped; omit the namespace", mapping.Resource.Resource)
+	case namespaced && namespace == "":
+		return "", fmt.Errorf("%s is namespaced; a namespace is required", mapping.Resource.Resource)
+	}
+
+	if resourceType, ok := modelledResources[groupResource]; ok {
+		return c.GetResource(ctx, &types.ResourceIdentifier{
+			Type:      resourceType,
+			Namespace: namespace,
+			Name:      name,
+		})
+	}
+
+	resource := c.dynamicClient.Resource(mapping.Resource)
+	var obj *unstructured.Unstructured
+	if namespaced {
+		obj, err = resource.Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		obj, err = resource.Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s %s: %w", groupResource.String(), qualifiedName(namespace, name), err)
+	}
+
+	// Managed fields and the last-applied copy roughly triple the size of the object
+	// without telling the reader anything new
+	obj.SetManagedFields(nil)
+	if annotations := obj.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		delete(annotations, lastAppliedAnnotation)
+		obj.SetAnnotations(annotations)
+	}
+
+	events, err := c.GetResourceEvents(ctx, namespace, mapping.GroupVersionKind.Kind, name)
+	if err != nil {
+		c.logger.Warnf("Failed to get events for %s %s: %v", groupResource.String(), qualifiedName(namespace, name), err)
+	}
+	if len(events) > maxDescribeEvents {
+		events = events[len(events)-maxDescribeEvents:]
+	}
+
+	detail := struct {
+		Object map[string]interface{} `json:"object"`
+		Events []EventInfo            `json:"recentEvents,omitempty"`
+	}{
+		Object: obj.Object,
+		Events: events,
+	}
+
+	data, err := json.MarshalIndent(detail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s: %w", groupResource.String(), err)
+	}
+
+	return string(data), nil
+}
+
+// resolveKind maps a kubectl-style type name to its REST mapping, refreshing discovery
+// once if the name is unknown so recently installed CRDs are found
+func (c *Client) resolveKind(kind string) (*meta.RESTMapping, error) {
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(kind))
+
+	var gvr schema.GroupVersionResource
+	var err error
+	if fullySpecified != nil {
+		gvr, err = c.shortcutMapper.ResourceFor(*fullySpecified)
+	}
+	if fullySpecified == nil || err != nil {
+		gvr, err = c.shortcutMapper.ResourceFor(groupResource.WithVersion(""))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource kind %q: %w", kind, err)
+	}
+
+	gvk, err := c.shortcutMapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource kind %q: %w", kind, err)
+	}
+
+	mapping, err := c.shortcutMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource kind %q: %w", kind, err)
+	}
+
+	return mapping, nil
+}
+
+// IsClusterScoped reports whether kind, in any form DescribeResource accepts, names a
+// cluster-scoped resource such as a node or ClusterRoleBinding
+func (c *Client) IsClusterScoped(kind string) (bool, error) {
+	mapping, err := c.resolveKind(kind)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope.Name() != meta.RESTScopeNameNamespace, nil
+}
+
+// qualifiedName formats namespace/name, or just name for cluster-scoped objects
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
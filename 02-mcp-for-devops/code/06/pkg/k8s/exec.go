@@ -0,0 +1,107 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// maxExecOutputBytes caps how much combined output ExecInPod keeps
+const maxExecOutputBytes = 1 << 20
+
+// ExecInPod runs command in a container through the pods/exec subresource and returns its
+// combined stdout and stderr. The command is run directly, not through a shell. A command
+// that exits non-zero returns its output together with an error; use ExitCode to read
+// the exit status.
+func (c *Client) ExecInPod(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+	start := time.Now()
+	var execErr error
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "exec_pod", namespace, podName, time.Since(start), execErr)
+	}()
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.restConfig, "POST", req.URL())
+	if err != nil {
+		execErr = err
+		return "", fmt.Errorf("failed to create executor for pod %s/%s: %w", namespace, podName, err)
+	}
+
+	output := &boundedBuffer{limit: maxExecOutputBytes}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: output,
+		Stderr: output,
+	})
+	if err != nil {
+		execErr = err
+		if _, ok := ExitCode(err); ok {
+			return output.String(), fmt.Errorf("command exited non-zero in pod %s/%s: %w", namespace, podName, err)
+		}
+		return output.String(), fmt.Errorf("failed to exec in pod %s/%s: %w", namespace, podName, err)
+	}
+
+	return output.String(), nil
+}
+
+// ExitCode extracts the exit status from an ExecInPod error
+func ExitCode(err error) (int, bool) {
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
+// boundedBuffer collects stdout and stderr, which are written concurrently, and drops
+// output past limit
+type boundedBuffer struct {
+	mu        sync.Mutex
+	data      []byte
+	limit     int
+	truncated bool
+}
+
+func (b *boundedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	room := b.limit - len(b.data)
+	if room < len(p) {
+		b.truncated = true
+		if room < 0 {
+			room = 0
+		}
+		b.data = append(b.data, p[:room]...)
+		return len(p), nil
+	}
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
+
+func (b *boundedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.truncated {
+		return string(b.data) + "\n... (output truncated)"
+	}
+	return string(b.data)
+}
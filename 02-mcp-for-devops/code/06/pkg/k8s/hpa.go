@@ -0,0 +1,151 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (c *Client) ListHPAs(ctx context.Context, namespace string) ([]HPAInfo, error) {
+	hpas, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list horizontalpodautoscalers in namespace %s: %w", namespace, err)
+	}
+
+	var hpaInfos []HPAInfo
+	for i := range hpas.Items {
+		hpaInfos = append(hpaInfos, newHPAInfo(&hpas.Items[i]))
+	}
+
+	return hpaInfos, nil
+}
+
+// getHPADetails adds the autoscaler's conditions, other metrics, and events. The
+// SuccessfulRescale events record each scaling decision and the metric that caused it.
+func (c *Client) getHPADetails(ctx context.Context, namespace, name string) (string, error) {
+	hpa, err := c.clientset.AutoscalingV2().HorizontalPodAutoscalers(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get horizontalpodautoscaler %s/%s: %w", namespace, name, err)
+	}
+
+	var conditions []string
+	for _, condition := range hpa.Status.Conditions {
+		conditions = append(conditions, fmt.Sprintf("%s=%s: %s - %s", condition.Type, condition.Status, condition.Reason, condition.Message))
+	}
+
+	var metrics []string
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil &&
+			(metric.Resource.Name == corev1.ResourceCPU || metric.Resource.Name == corev1.ResourceMemory) &&
+			metric.Resource.Target.AverageUtilization != nil {
+			continue // reported as utilization in HPAInfo
+		}
+		metrics = append(metrics, formatMetricSpec(metric))
+	}
+
+	events, err := c.GetResourceEvents(ctx, namespace, "HorizontalPodAutoscaler", name)
+	if err != nil {
+		c.logger.Warnf("Failed to get events for horizontalpodautoscaler %s/%s: %v", namespace, name, err)
+	}
+
+	hpaInfo := newHPAInfo(hpa)
+	hpaDetail := struct {
+		*HPAInfo
+		Conditions   []string    `json:"conditions"`
+		OtherMetrics []string    `json:"otherMetrics,omitempty"`
+		Events       []EventInfo `json:"events,omitempty"`
+	}{
+		HPAInfo:      &hpaInfo,
+		Conditions:   conditions,
+		OtherMetrics: metrics,
+		Events:       events,
+	}
+
+	data, err := json.MarshalIndent(hpaDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal horizontalpodautoscaler details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func newHPAInfo(hpa *autoscalingv2.HorizontalPodAutoscaler) HPAInfo {
+	info := HPAInfo{
+		Name:            hpa.Name,
+		Namespace:       hpa.Namespace,
+		TargetRef:       hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+		MinReplicas:     1,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+		Labels:          hpa.Labels,
+		CreatedAt:       hpa.CreationTimestamp.Time,
+	}
+	if hpa.Spec.MinReplicas != nil {
+		info.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	if hpa.Status.LastScaleTime != nil {
+		lastScaleTime := hpa.Status.LastScaleTime.Time
+		info.LastScaleTime = &lastScaleTime
+	}
+
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			continue
+		}
+		switch metric.Resource.Name {
+		case corev1.ResourceCPU:
+			info.TargetCPUUtilization = metric.Resource.Target.AverageUtilization
+		case corev1.ResourceMemory:
+			info.TargetMemoryUtilization = metric.Resource.Target.AverageUtilization
+		}
+	}
+	for _, metric := range hpa.Status.CurrentMetrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			continue
+		}
+		switch metric.Resource.Name {
+		case corev1.ResourceCPU:
+			info.CurrentCPUUtilization = metric.Resource.Current.AverageUtilization
+		case corev1.ResourceMemory:
+			info.CurrentMemoryUtilization = metric.Resource.Current.AverageUtilization
+		}
+	}
+
+	return info
+}
+
+// formatMetricSpec describes a metric target, e.g. "Pods packets-per-second: AverageValue 1k"
+func formatMetricSpec(metric autoscalingv2.MetricSpec) string {
+	var name string
+	var target autoscalingv2.MetricTarget
+	switch {
+	case metric.Resource != nil:
+		name, target = string(metric.Resource.Name), metric.Resource.Target
+	case metric.ContainerResource != nil:
+		name, target = metric.ContainerResource.Container+"/"+string(metric.ContainerResource.Name), metric.ContainerResource.Target
+	case metric.Pods != nil:
+		name, target = metric.Pods.Metric.Name, metric.Pods.Target
+	case metric.Object != nil:
+		name, target = metric.Object.DescribedObject.Kind+"/"+metric.Object.DescribedObject.Name+" "+metric.Object.Metric.Name, metric.Object.Target
+	case metric.External != nil:
+		name, target = metric.External.Metric.Name, metric.External.Target
+	default:
+		return string(metric.Type)
+	}
+
+	value := ""
+	switch {
+	case target.AverageUtilization != nil:
+		value = fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		value = target.AverageValue.String()
+	case target.Value != nil:
+		value = target.Value.String()
+	}
+	return fmt.Sprintf("%s %s: %s %s", metric.Type, name, target.Type, value)
+}
@@ -0,0 +1,321 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+const (
+	JobStatusComplete = "Complete"
+	JobStatusFailed   = "Failed"
+	JobStatusRunning  = "Running"
+)
+
+// jobNameLabel is set by the Job controller on every pod it creates
+const jobNameLabel = "job-name"
+
+// maxDiagnosedJobPods caps how many pods a Job diagnosis collects logs and events for
+const maxDiagnosedJobPods = 5
+
+// ErrWaitTimeout is returned when a wait operation reaches its deadline
+var ErrWaitTimeout = errors.New("timed out waiting for condition")
+
+func (c *Client) ListJobs(ctx context.Context, namespace string) ([]JobInfo, error) {
+	jobs, err := c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs in namespace %s: %w", namespace, err)
+	}
+
+	var jobInfos []JobInfo
+	for i := range jobs.Items {
+		jobInfos = append(jobInfos, newJobInfo(&jobs.Items[i]))
+	}
+
+	return jobInfos, nil
+}
+
+func (c *Client) getJobDetails(ctx context.Context, namespace, name string) (string, error) {
+	job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get job %s/%s: %w", namespace, name, err)
+	}
+
+	events, err := c.objectEvents(ctx, namespace, "Job", name)
+	if err != nil {
+		c.logger.Warnf("Failed to get events for job %s/%s: %v", namespace, name, err)
+	}
+
+	var cronJob string
+	for _, owner := range job.OwnerReferences {
+		if owner.Kind == "CronJob" {
+			cronJob = owner.Name
+		}
+	}
+
+	jobInfo := newJobInfo(job)
+	jobDetail := struct {
+		*JobInfo
+		Parallelism  *int32   `json:"parallelism,omitempty"`
+		BackoffLimit *int32   `json:"backoffLimit,omitempty"`
+		CronJob      string   `json:"cronJob,omitempty"`
+		Events       []string `json:"events,omitempty"`
+	}{
+		JobInfo:      &jobInfo,
+		Parallelism:  job.Spec.Parallelism,
+		BackoffLimit: job.Spec.BackoffLimit,
+		CronJob:      cronJob,
+		Events:       events,
+	}
+
+	data, err := json.MarshalIndent(jobDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal job details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// WaitForJob watches a Job until it completes or fails, or until timeout elapses.
+// On timeout the latest observed status is returned together with ErrWaitTimeout.
+func (c *Client) WaitForJob(ctx context.Context, namespace, name string, timeout time.Duration) (*JobInfo, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "wait_for_job", namespace, name, time.Since(start), nil)
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	jobs := c.clientset.BatchV1().Jobs(namespace)
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return jobs.List(waitCtx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return jobs.Watch(waitCtx, options)
+		},
+	}
+
+	var last *batchv1.Job
+	_, err := watchtools.UntilWithSync(waitCtx, lw, &batchv1.Job{}, nil, func(event watch.Event) (bool, error) {
+		if event.Type == watch.Deleted {
+			return false, fmt.Errorf("job %s/%s was deleted while waiting", namespace, name)
+		}
+		job, ok := event.Object.(*batchv1.Job)
+		if !ok {
+			return false, nil
+		}
+		last = job
+		return jobStatus(job) != JobStatusRunning, nil
+	})
+
+	if err != nil {
+		if waitCtx.Err() != nil && ctx.Err() == nil {
+			if last == nil {
+				return nil, fmt.Errorf("job %s/%s not found: %w", namespace, name, ErrWaitTimeout)
+			}
+			info := newJobInfo(last)
+			return &info, ErrWaitTimeout
+		}
+		return nil, fmt.Errorf("failed waiting for job %s/%s: %w", namespace, name, err)
+	}
+
+	info := newJobInfo(last)
+	return &info, nil
+}
+
+// GetJobFailedPodLogs returns the name and last log lines of the most recently
+// failed pod belonging to a Job. An empty pod name means no failed pod was found.
+func (c *Client) GetJobFailedPodLogs(ctx context.Context, namespace, jobName string, tailLines int64) (string, string, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", jobNameLabel, jobName),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list pods for job %s/%s: %w", namespace, jobName, err)
+	}
+
+	var failed []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodFailed {
+			failed = append(failed, pod)
+		}
+	}
+	if len(failed) == 0 {
+		return "", "", nil
+	}
+
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].CreationTimestamp.After(failed[j].CreationTimestamp.Time)
+	})
+	pod := failed[0]
+	if len(pod.Spec.Containers) == 0 {
+		return pod.Name, "", nil
+	}
+
+	logs, err := c.GetPodLogs(ctx, namespace, pod.Name, failedContainerName(&pod), &tailLines, nil, false)
+	if err != nil {
+		return pod.Name, "", err
+	}
+	return pod.Name, logs, nil
+}
+
+// DiagnoseJob collects a Job's status and events together with the statuses, last
+// container terminations, events, and final log lines of its most recent pods. Logs are
+// only fetched for pods that did not succeed.
+func (c *Client) DiagnoseJob(ctx context.Context, namespace, name string, tailLines int64) (*JobDiagnosis, error) {
+	job, err := c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s/%s: %w", namespace, name, err)
+	}
+
+	diagnosis := &JobDiagnosis{
+		Job:          newJobInfo(job),
+		BackoffLimit: job.Spec.BackoffLimit,
+	}
+	if diagnosis.Events, err = c.objectEvents(ctx, namespace, "Job", name); err != nil {
+		c.logger.Warnf("Failed to get events for job %s/%s: %v", namespace, name, err)
+	}
+
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", jobNameLabel, name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods for job %s/%s: %w", namespace, name, err)
+	}
+
+	items := pods.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.After(items[j].CreationTimestamp.Time)
+	})
+	if len(items) > maxDiagnosedJobPods {
+		items = items[:maxDiagnosedJobPods]
+	}
+
+	for i := range items {
+		pod := &items[i]
+		podDiagnosis := JobPodDiagnosis{
+			Name:         pod.Name,
+			Phase:        string(pod.Status.Phase),
+			Reason:       pod.Status.Reason,
+			Message:      pod.Status.Message,
+			Terminations: lastTerminations(pod),
+			CreatedAt:    pod.CreationTimestamp.Time,
+		}
+
+		if podDiagnosis.Events, err = c.objectEvents(ctx, namespace, "Pod", pod.Name); err != nil {
+			c.logger.Warnf("Failed to get events for pod %s/%s: %v", namespace, pod.Name, err)
+		}
+
+		if pod.Status.Phase != corev1.PodSucceeded && len(pod.Spec.Containers) > 0 {
+			container := failedContainerName(pod)
+			logs, err := c.GetPodLogs(ctx, namespace, pod.Name, container, &tailLines, nil, false)
+			if err != nil {
+				c.logger.Warnf("Failed to get logs for pod %s/%s: %v", namespace, pod.Name, err)
+			}
+			podDiagnosis.Logs = logs
+		}
+
+		diagnosis.Pods = append(diagnosis.Pods, podDiagnosis)
+	}
+
+	return diagnosis, nil
+}
+
+// lastTerminations returns the most recent termination of each init and app container
+func lastTerminations(pod *corev1.Pod) []ContainerTermination {
+	var terminations []ContainerTermination
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		terminated := status.State.Terminated
+		if terminated == nil {
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil {
+			continue
+		}
+		terminations = append(terminations, ContainerTermination{
+			Container: status.Name,
+			Reason:    terminated.Reason,
+			ExitCode:  terminated.ExitCode,
+			Message:   terminated.Message,
+		})
+	}
+	return terminations
+}
+
+// failedContainerName returns the first container that exited non-zero, falling back
+// to the pod's first container
+func failedContainerName(pod *corev1.Pod) string {
+	for _, termination := range lastTerminations(pod) {
+		if termination.ExitCode != 0 {
+			return termination.Container
+		}
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+func newJobInfo(job *batchv1.Job) JobInfo {
+	info := JobInfo{
+		Name:      job.Name,
+		Namespace: job.Namespace,
+		Status:    jobStatus(job),
+		Succeeded: job.Status.Succeeded,
+		Failed:    job.Status.Failed,
+		Active:    job.Status.Active,
+		Labels:    job.Labels,
+		CreatedAt: job.CreationTimestamp.Time,
+	}
+
+	if job.Spec.Completions != nil {
+		info.Completions = *job.Spec.Completions
+	}
+	if job.Status.StartTime != nil {
+		startTime := job.Status.StartTime.Time
+		info.StartTime = &startTime
+	}
+	if job.Status.CompletionTime != nil {
+		completionTime := job.Status.CompletionTime.Time
+		info.CompletionTime = &completionTime
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			info.FailureReason = condition.Reason
+			info.FailureMessage = condition.Message
+		}
+	}
+
+	return info
+}
+
+// jobStatus derives the terminal state of a Job from its conditions
+func jobStatus(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return JobStatusComplete
+		case batchv1.JobFailed:
+			return JobStatusFailed
+		}
+	}
+	return JobStatusRunning
+}
@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// DefaultLogWaitMaxBytes caps how much log output a single wait may consume
+	DefaultLogWaitMaxBytes = 10 << 20
+	// maxLogLineBytes is the longest single log line the scanner accepts
+	maxLogLineBytes = 1 << 20
+	// logAfterContextWindow bounds how long to keep following for lines after a match
+	logAfterContextWindow = 2 * time.Second
+)
+
+// ErrLogByteLimit is returned when a log follow reads maxBytes without a match
+var ErrLogByteLimit = errors.New("log byte limit reached before pattern matched")
+
+// LogWaitOptions controls how WaitForLog follows a container's logs
+type LogWaitOptions struct {
+	Container    string
+	Pattern      *regexp.Regexp
+	Timeout      time.Duration
+	ContextLines int
+	MaxBytes     int64
+}
+
+// LogMatch is the result of waiting for a log line
+type LogMatch struct {
+	Matched    bool     `json:"matched"`
+	Line       string   `json:"line,omitempty"`
+	LineNumber int      `json:"lineNumber,omitempty"`
+	Before     []string `json:"before,omitempty"`
+	After      []string `json:"after,omitempty"`
+	BytesRead  int64    `json:"bytesRead"`
+}
+
+// countingReader tracks how many bytes have been read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// FollowPodLogs opens a follow-mode log stream for a container, starting with the last
+// tailLines lines when tailLines is set. The stream ends when the container exits, when
+// the caller closes it, or when ctx is cancelled; cancellation closes the stream so a
+// blocked Read returns promptly.
+func (c *Client) FollowPodLogs(ctx context.Context, namespace, podName, container string, tailLines *int64) (io.ReadCloser, error) {
+	req := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+		TailLines: tailLines,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow logs for pod %s/%s: %w", namespace, podName, err)
+	}
+
+	followed := &followedStream{ReadCloser: stream}
+	followed.stop = context.AfterFunc(ctx, func() { followed.closeStream() })
+	return followed, nil
+}
+
+// followedStream closes its log stream exactly once, whether the caller closes it or
+// its context is cancelled first
+type followedStream struct {
+	io.ReadCloser
+	stop     func() bool
+	once     sync.Once
+	closeErr error
+}
+
+func (s *followedStream) Close() error {
+	s.stop()
+	return s.closeStream()
+}
+
+func (s *followedStream) closeStream() error {
+	s.once.Do(func() {
+		s.closeErr = s.ReadCloser.Close()
+	})
+	return s.closeErr
+}
+
+// WaitForLog follows a pod's logs from the beginning until a line matches opts.Pattern,
+// the timeout elapses, or opts.MaxBytes have been read. On a match it keeps following
+// briefly to collect up to opts.ContextLines lines after the matching line.
+func (c *Client) WaitForLog(ctx context.Context, namespace, podName string, opts LogWaitOptions) (*LogMatch, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "wait_for_log", namespace, podName, time.Since(start), nil)
+	}()
+
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultLogWaitMaxBytes
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container: opts.Container,
+		Follow:    true,
+	})
+	stream, err := req.Stream(waitCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow logs for pod %s/%s: %w", namespace, podName, err)
+	}
+	defer stream.Close()
+
+	counter := &countingReader{r: io.LimitReader(stream, opts.MaxBytes)}
+	scanner := bufio.NewScanner(counter)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineBytes)
+
+	match := &LogMatch{}
+	var before []string
+	lineNumber := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNumber++
+
+		if match.Matched {
+			match.After = append(match.After, line)
+			if len(match.After) >= opts.ContextLines {
+				break
+			}
+			continue
+		}
+
+		if opts.Pattern.MatchString(line) {
+			match.Matched = true
+			match.Line = line
+			match.LineNumber = lineNumber
+			match.Before = before
+			if opts.ContextLines == 0 {
+				break
+			}
+			// The stream has no natural end while following, so stop collecting
+			// trailing context after a short window
+			time.AfterFunc(logAfterContextWindow, cancel)
+			continue
+		}
+
+		if opts.ContextLines > 0 {
+			before = append(before, line)
+			if len(before) > opts.ContextLines {
+				before = before[1:]
+			}
+		}
+	}
+	match.BytesRead = counter.n
+
+	if match.Matched {
+		return match, nil
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+		return match, fmt.Errorf("failed to read logs for pod %s/%s: %w", namespace, podName, err)
+	}
+	if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+		return match, ErrWaitTimeout
+	}
+	if ctx.Err() != nil {
+		return match, ctx.Err()
+	}
+	if counter.n >= opts.MaxBytes {
+		return match, ErrLogByteLimit
+	}
+	return match, fmt.Errorf("log stream for pod %s/%s ended before pattern matched", namespace, podName)
+}
@@ -0,0 +1,37 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestFollowedStreamClosesOnCancel(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	followed := &followedStream{ReadCloser: reader}
+	followed.stop = context.AfterFunc(ctx, func() { followed.closeStream() })
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := followed.Read(make([]byte, 16))
+		done <- err
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected Read to fail after cancellation")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read still blocked after cancellation")
+	}
+
+	if err := followed.Close(); err != nil {
+		t.Errorf("Close after cancellation returned %v", err)
+	}
+}
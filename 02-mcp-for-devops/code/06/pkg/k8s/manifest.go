@@ -0,0 +1,154 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	typesv1 "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+// fieldManager identifies this server in managedFields for apply operations
+const fieldManager = "k8s-mcp-server"
+
+// ManifestValidation is the validation outcome for a single object in a manifest
+type ManifestValidation struct {
+	Index     int      `json:"index"`
+	Kind      string   `json:"kind"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Valid     bool     `json:"valid"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// ValidateManifest checks every object in a YAML or JSON manifest with a server-side
+// apply dry-run using strict field validation. Unknown or misspelled fields and schema
+// violations are reported per object; nothing is persisted. Objects without a namespace
+// are validated in defaultNamespace when they are namespaced.
+func (c *Client) ValidateManifest(ctx context.Context, manifest, defaultNamespace string) ([]ManifestValidation, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "validate_manifest", defaultNamespace, "manifest", time.Since(start), nil)
+	}()
+
+	objects, err := decodeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 {
+		return nil, fmt.Errorf("manifest contains no objects")
+	}
+
+	results := make([]ManifestValidation, 0, len(objects))
+	for i, obj := range objects {
+		result := ManifestValidation{
+			Index:     i,
+			Kind:      obj.GetKind(),
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		}
+
+		if err := c.dryRunApply(ctx, obj, defaultNamespace, &result); err != nil {
+			result.Errors = validationMessages(err)
+		} else {
+			result.Valid = true
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// dryRunApply server-side applies obj with DryRun=All and strict field validation
+func (c *Client) dryRunApply(ctx context.Context, obj *unstructured.Unstructured, defaultNamespace string, result *ManifestValidation) error {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return fmt.Errorf("object is missing apiVersion or kind")
+	}
+	if obj.GetName() == "" {
+		return fmt.Errorf("object is missing metadata.name")
+	}
+
+	mapping, err := c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("unknown resource type %s: %w", gvk.String(), err)
+	}
+
+	resource := c.dynamicClient.Resource(mapping.Resource)
+	var target dynamic.ResourceInterface = resource
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(defaultNamespace)
+			result.Namespace = defaultNamespace
+		}
+		target = resource.Namespace(obj.GetNamespace())
+
+		if _, err := target.Get(ctx, obj.GetName(), metav1.GetOptions{}); apierrors.IsNotFound(err) {
+			if err := c.CheckObjectCountQuota(ctx, obj.GetNamespace(), mapping.Resource); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("failed to encode object: %w", err)
+	}
+
+	force := true
+	_, err = target.Patch(ctx, obj.GetName(), typesv1.ApplyPatchType, data, metav1.PatchOptions{
+		DryRun:          []string{metav1.DryRunAll},
+		FieldManager:    fieldManager,
+		FieldValidation: metav1.FieldValidationStrict,
+		Force:           &force,
+	})
+	return err
+}
+
+// decodeManifest splits a multi-document YAML or JSON manifest into objects
+func decodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader([]byte(manifest)), 4096)
+
+	var objects []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse manifest document %d: %w", len(objects), err)
+		}
+		if len(obj.Object) == 0 {
+			continue // empty document, e.g. a trailing "---"
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, nil
+}
+
+// validationMessages flattens an API error into its individual field causes
+func validationMessages(err error) []string {
+	var statusErr *apierrors.StatusError
+	if errors.As(err, &statusErr) && statusErr.ErrStatus.Details != nil && len(statusErr.ErrStatus.Details.Causes) > 0 {
+		var messages []string
+		for _, cause := range statusErr.ErrStatus.Details.Causes {
+			if cause.Field != "" {
+				messages = append(messages, fmt.Sprintf("%s: %s", cause.Field, cause.Message))
+			} else {
+				messages = append(messages, cause.Message)
+			}
+		}
+		return messages
+	}
+	return []string{err.Error()}
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrMetricsUnavailable is returned when the metrics.k8s.io API cannot be reached,
+// usually because metrics-server is not installed
+var ErrMetricsUnavailable = errors.New("the metrics.k8s.io API is not available; install metrics-server to get pod and node usage")
+
+// TopPods returns the current CPU and memory usage of the pods in a namespace, or in
+// every namespace when namespace is empty
+func (c *Client) TopPods(ctx context.Context, namespace string) ([]PodUsage, error) {
+	podMetrics, err := c.metricsClient.MetricsV1beta1().PodMetricses(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, metricsError(fmt.Sprintf("failed to get pod metrics in namespace %s", namespace), err)
+	}
+
+	usages := make([]PodUsage, 0, len(podMetrics.Items))
+	for _, metrics := range podMetrics.Items {
+		usage := PodUsage{
+			Name:      metrics.Name,
+			Namespace: metrics.Namespace,
+			Timestamp: metrics.Timestamp.Time,
+		}
+		var cpu, memory resource.Quantity
+		for _, container := range metrics.Containers {
+			containerCPU := container.Usage[corev1.ResourceCPU]
+			containerMemory := container.Usage[corev1.ResourceMemory]
+			cpu.Add(containerCPU)
+			memory.Add(containerMemory)
+			usage.Containers = append(usage.Containers, ContainerUsage{
+				Name:          container.Name,
+				CPUMillicores: containerCPU.MilliValue(),
+				MemoryBytes:   containerMemory.Value(),
+			})
+		}
+		usage.CPUMillicores, usage.MemoryBytes = cpu.MilliValue(), memory.Value()
+		usage.CPU, usage.Memory = formatCPU(usage.CPUMillicores), formatMemory(usage.MemoryBytes)
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
+
+// TopNodes returns the current CPU and memory usage of every node, with percentages of
+// each node's allocatable resources like kubectl top nodes
+func (c *Client) TopNodes(ctx context.Context) ([]NodeUsage, error) {
+	nodeMetrics, err := c.metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, metricsError("failed to get node metrics", err)
+	}
+
+	// Percentages are a bonus; usage alone still answers the question
+	allocatable := make(map[string]corev1.ResourceList)
+	nodes, err := retryOnTransient(ctx, func() (*corev1.NodeList, error) {
+		return c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
+	if err != nil {
+		c.logger.Warnf("Failed to list nodes for usage percentages: %v", err)
+	} else {
+		for _, node := range nodes.Items {
+			allocatable[node.Name] = node.Status.Allocatable
+		}
+	}
+
+	usages := make([]NodeUsage, 0, len(nodeMetrics.Items))
+	for _, metrics := range nodeMetrics.Items {
+		cpu := metrics.Usage[corev1.ResourceCPU]
+		memory := metrics.Usage[corev1.ResourceMemory]
+		usage := NodeUsage{
+			Name:          metrics.Name,
+			CPUMillicores: cpu.MilliValue(),
+			MemoryBytes:   memory.Value(),
+			Timestamp:     metrics.Timestamp.Time,
+		}
+		usage.CPU, usage.Memory = formatCPU(usage.CPUMillicores), formatMemory(usage.MemoryBytes)
+		if resources, ok := allocatable[metrics.Name]; ok {
+			usage.CPUPercent = percentOf(usage.CPUMillicores, resources.Cpu().MilliValue())
+			usage.MemoryPercent = percentOf(usage.MemoryBytes, resources.Memory().Value())
+		}
+		usages = append(usages, usage)
+	}
+
+	return usages, nil
+}
+
+// metricsError reports a missing or unreachable metrics API as ErrMetricsUnavailable,
+// rather than the API server's bare 404 or 503
+func metricsError(message string, err error) error {
+	if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+		return fmt.Errorf("%s: %w", message, ErrMetricsUnavailable)
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// percentOf returns used as a whole percentage of total, or nil when total is unknown
+func percentOf(used, total int64) *int64 {
+	if total <= 0 {
+		return nil
+	}
+	percent := used * 100 / total
+	return &percent
+}
+
+// formatCPU renders millicores the way kubectl top does, e.g. 250m
+func formatCPU(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+// formatMemory renders bytes in mebibytes the way kubectl top does, e.g. 128Mi
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
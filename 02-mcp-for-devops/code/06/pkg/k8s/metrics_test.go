@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMetricsErrorExplainsMissingMetricsServer(t *testing.T) {
+	notFound := apierrors.NewNotFound(schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}, "")
+	if err := metricsError("failed to get pod metrics", notFound); !errors.Is(err, ErrMetricsUnavailable) {
+		t.Errorf("a 404 from the metrics API should be ErrMetricsUnavailable, got %v", err)
+	}
+
+	unavailable := apierrors.NewServiceUnavailable("metrics-server is starting")
+	if err := metricsError("failed to get pod metrics", unavailable); !errors.Is(err, ErrMetricsUnavailable) {
+		t.Errorf("a 503 from the metrics API should be ErrMetricsUnavailable, got %v", err)
+	}
+
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}, "", errors.New("no access"))
+	err := metricsError("failed to get pod metrics", forbidden)
+	if errors.Is(err, ErrMetricsUnavailable) || !apierrors.IsForbidden(err) {
+		t.Errorf("other errors should pass through, got %v", err)
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	if got := percentOf(500, 2000); got == nil || *got != 25 {
+		t.Errorf("percentOf(500, 2000) = %v, want 25", got)
+	}
+	if got := percentOf(500, 0); got != nil {
+		t.Errorf("percentOf(500, 0) = %v, want nil", *got)
+	}
+}
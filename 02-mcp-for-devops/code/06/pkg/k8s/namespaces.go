@@ -0,0 +1,52 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// namespacedResources maps the resource names used by tool permissions to their API resources
+var namespacedResources = map[string]schema.GroupVersionResource{
+	"pods":        {Version: "v1", Resource: "pods"},
+	"services":    {Version: "v1", Resource: "services"},
+	"configmaps":  {Version: "v1", Resource: "configmaps"},
+	"secrets":     {Version: "v1", Resource: "secrets"},
+	"deployments": {Group: "apps", Version: "v1", Resource: "deployments"},
+	"jobs":        {Group: "batch", Version: "v1", Resource: "jobs"},
+}
+
+// FindResourceNamespaces returns the namespaces, out of candidates, that contain an object
+// of the given resource type with the given name. An empty candidates list means every
+// namespace in the cluster.
+func (c *Client) FindResourceNamespaces(ctx context.Context, resource, name string, candidates []string) ([]string, error) {
+	gvr, ok := namespacedResources[resource]
+	if !ok {
+		return nil, fmt.Errorf("namespace lookup is not supported for resource type %q", resource)
+	}
+
+	if len(candidates) == 0 {
+		all, err := c.namespaceNames(ctx)
+		if err != nil {
+			return nil, err
+		}
+		candidates = all
+	}
+
+	var found []string
+	for _, namespace := range candidates {
+		_, err := c.dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || apierrors.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up %s %s in namespace %s: %w", resource, name, namespace, err)
+		}
+		found = append(found, namespace)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,133 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	typesv1 "k8s.io/apimachinery/pkg/types"
+)
+
+// DrainOptions controls which pods DrainNode evicts
+type DrainOptions struct {
+	// DeleteEmptyDirData evicts pods with emptyDir volumes, whose data is lost
+	DeleteEmptyDirData bool
+	// GracePeriodSeconds overrides each pod's termination grace period when set
+	GracePeriodSeconds *int64
+	// DryRun reports what would be evicted without cordoning or evicting anything
+	DryRun bool
+}
+
+// CordonNode marks a node unschedulable so no new pods are placed on it
+func (c *Client) CordonNode(ctx context.Context, name string, dryRun bool) error {
+	return c.setUnschedulable(ctx, name, true, dryRun)
+}
+
+// UncordonNode makes a cordoned node schedulable again
+func (c *Client) UncordonNode(ctx context.Context, name string, dryRun bool) error {
+	return c.setUnschedulable(ctx, name, false, dryRun)
+}
+
+func (c *Client) setUnschedulable(ctx context.Context, name string, unschedulable, dryRun bool) error {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "cordon_node", "", fmt.Sprintf("%s->unschedulable=%t", name, unschedulable), time.Since(start), nil)
+	}()
+
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	_, err := c.clientset.CoreV1().Nodes().Patch(ctx, name, typesv1.StrategicMergePatchType, []byte(patch),
+		metav1.PatchOptions{DryRun: dryRunOption(dryRun)})
+	if err != nil {
+		return fmt.Errorf("failed to set node %s unschedulable=%t: %w", name, unschedulable, err)
+	}
+	return nil
+}
+
+// DrainNode cordons a node and evicts its pods through the eviction API, so
+// PodDisruptionBudgets are respected. Like kubectl drain it leaves DaemonSet pods and
+// static pods in place, and it also skips pods no controller would recreate and, unless
+// opts allow it, pods with emptyDir data. A pod whose eviction a PodDisruptionBudget
+// blocks is skipped rather than failing the drain. DrainNode does not wait for evicted
+// pods to terminate.
+func (c *Client) DrainNode(ctx context.Context, name string, opts DrainOptions) (*DrainResult, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "drain_node", "", name, time.Since(start), nil)
+	}()
+
+	if err := c.CordonNode(ctx, name, opts.DryRun); err != nil {
+		return nil, err
+	}
+
+	pods, err := retryOnTransient(ctx, func() (*corev1.PodList, error) {
+		return c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String(),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %w", name, err)
+	}
+
+	result := &DrainResult{Node: name, Evicted: []DrainedPod{}, Skipped: []DrainedPod{}}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		drained := DrainedPod{Namespace: pod.Namespace, Name: pod.Name}
+
+		if reason := drainSkipReason(pod, opts); reason != "" {
+			drained.Reason = reason
+			result.Skipped = append(result.Skipped, drained)
+			continue
+		}
+
+		err := c.clientset.PolicyV1().Evictions(pod.Namespace).Evict(ctx, &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+			DeleteOptions: &metav1.DeleteOptions{
+				GracePeriodSeconds: opts.GracePeriodSeconds,
+				DryRun:             dryRunOption(opts.DryRun),
+			},
+		})
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+			result.Evicted = append(result.Evicted, drained)
+		case apierrors.IsTooManyRequests(err):
+			drained.Reason = "eviction blocked by a PodDisruptionBudget"
+			result.Skipped = append(result.Skipped, drained)
+		default:
+			drained.Reason = fmt.Sprintf("eviction failed: %v", err)
+			result.Skipped = append(result.Skipped, drained)
+		}
+	}
+
+	return result, nil
+}
+
+// drainSkipReason returns why a drain should leave a pod in place, or "" to evict it
+func drainSkipReason(pod *corev1.Pod, opts DrainOptions) string {
+	if _, mirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; mirror {
+		return "static pod managed by the kubelet"
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return "" // finished pods can always go
+	}
+
+	controller := metav1.GetControllerOf(pod)
+	if controller == nil {
+		return "not managed by a controller, so it would not be recreated elsewhere"
+	}
+	if controller.Kind == "DaemonSet" {
+		return "managed by DaemonSet " + controller.Name
+	}
+	if !opts.DeleteEmptyDirData {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.EmptyDir != nil {
+				return fmt.Sprintf("emptyDir volume %s would lose its data; set deleteEmptyDirData to evict", volume.Name)
+			}
+		}
+	}
+	return ""
+}
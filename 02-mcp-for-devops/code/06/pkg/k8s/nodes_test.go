@@ -0,0 +1,41 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDrainSkipReason(t *testing.T) {
+	isController := true
+	ownedBy := func(kind string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: "owner", Controller: &isController}}
+	}
+	emptyDir := []corev1.Volume{{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		opts DrainOptions
+		skip string // substring of the reason, "" to evict
+	}{
+		{"replicaset pod", corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: ownedBy("ReplicaSet")}}, DrainOptions{}, ""},
+		{"daemonset pod", corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: ownedBy("DaemonSet")}}, DrainOptions{}, "DaemonSet"},
+		{"static pod", corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "x"}}}, DrainOptions{}, "static"},
+		{"bare pod", corev1.Pod{}, DrainOptions{}, "not managed"},
+		{"finished bare pod", corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}}, DrainOptions{}, ""},
+		{"emptyDir pod", corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: ownedBy("ReplicaSet")}, Spec: corev1.PodSpec{Volumes: emptyDir}}, DrainOptions{}, "emptyDir"},
+		{"emptyDir pod allowed", corev1.Pod{ObjectMeta: metav1.ObjectMeta{OwnerReferences: ownedBy("ReplicaSet")}, Spec: corev1.PodSpec{Volumes: emptyDir}}, DrainOptions{DeleteEmptyDirData: true}, ""},
+	}
+	for _, tt := range tests {
+		reason := drainSkipReason(&tt.pod, tt.opts)
+		if tt.skip == "" && reason != "" {
+			t.Errorf("%s: expected eviction, got skip %q", tt.name, reason)
+		}
+		if tt.skip != "" && !strings.Contains(reason, tt.skip) {
+			t.Errorf("%s: reason %q does not mention %q", tt.name, reason, tt.skip)
+		}
+	}
+}
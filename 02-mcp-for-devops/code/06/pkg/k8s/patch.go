@@ -0,0 +1,124 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	typesv1 "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// patchTypes maps the patch type names the patch tool accepts to their content types
+var patchTypes = map[string]typesv1.PatchType{
+	"json":      typesv1.JSONPatchType,
+	"merge":     typesv1.MergePatchType,
+	"strategic": typesv1.StrategicMergePatchType,
+}
+
+// PatchResource applies a JSON patch, JSON merge patch or strategic merge patch to any
+// resource, resolving kind the way DescribeResource does. Custom resources accept only
+// json and merge patches. Secrets are refused, since the patched object would return
+// their values; use CreateOrUpdateSecret instead. namespace must be empty for
+// cluster-scoped kinds.
+func (c *Client) PatchResource(ctx context.Context, kind, namespace, name, patchType string, patch []byte, dryRun bool) (*PatchResult, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "patch_resource", namespace, name, time.Since(start), nil)
+	}()
+
+	pt, ok := patchTypes[patchType]
+	if !ok {
+		return nil, fmt.Errorf("unknown patch type %q; use json, merge or strategic", patchType)
+	}
+
+	mapping, namespace, err := c.patchTarget(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return c.applyPatch(ctx, mapping, namespace, name, pt, patch, dryRun)
+}
+
+// EditMetadata sets or removes labels or annotations, as field says, on any resource
+// except secrets, resolving kind like PatchResource, so namespace must be empty for
+// cluster-scoped kinds. A nil value removes its key. Built-in
+// kinds get a strategic merge patch and custom resources, which don't support one, the
+// equivalent JSON merge patch.
+func (c *Client) EditMetadata(ctx context.Context, kind, namespace, name, field string, changes map[string]*string, dryRun bool) (*PatchResult, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "edit_"+field, namespace, name, time.Since(start), nil)
+	}()
+
+	mapping, namespace, err := c.patchTarget(kind, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{field: changes},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s patch: %w", field, err)
+	}
+
+	pt := typesv1.MergePatchType
+	if scheme.Scheme.Recognizes(mapping.GroupVersionKind) {
+		pt = typesv1.StrategicMergePatchType
+	}
+	return c.applyPatch(ctx, mapping, namespace, name, pt, patch, dryRun)
+}
+
+// patchTarget resolves kind for a patch, refusing secrets, and returns the namespace to
+// patch in. A namespace given for a cluster-scoped kind is refused rather than dropped,
+// since the caller was authorized in that namespace only.
+func (c *Client) patchTarget(kind, namespace string) (*meta.RESTMapping, string, error) {
+	mapping, err := c.resolveKind(kind)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if mapping.Resource.GroupResource() == (schema.GroupResource{Resource: "secrets"}) {
+		return nil, "", fmt.Errorf("secrets cannot be patched; use k8s_create_secret instead")
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		if namespace != "" {
+			return nil, "", fmt.Errorf("%s is cluster-scoped; omit the namespace", mapping.Resource.Resource)
+		}
+		return mapping, "", nil
+	}
+	if namespace == "" {
+		return nil, "", fmt.Errorf("%s is namespaced; a namespace is required", mapping.Resource.Resource)
+	}
+	return mapping, namespace, nil
+}
+
+// applyPatch patches the named object of mapping's resource, in namespace unless it is ""
+func (c *Client) applyPatch(ctx context.Context, mapping *meta.RESTMapping, namespace, name string, pt typesv1.PatchType, patch []byte, dryRun bool) (*PatchResult, error) {
+	resource := c.dynamicClient.Resource(mapping.Resource)
+	options := metav1.PatchOptions{DryRun: dryRunOption(dryRun)}
+	var obj *unstructured.Unstructured
+	var err error
+	if namespace != "" {
+		obj, err = resource.Namespace(namespace).Patch(ctx, name, pt, patch, options)
+	} else {
+		obj, err = resource.Patch(ctx, name, pt, patch, options)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch %s %s: %w", mapping.Resource.GroupResource().String(), qualifiedName(namespace, name), err)
+	}
+
+	return &PatchResult{
+		Kind:            obj.GetKind(),
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		ResourceVersion: obj.GetResourceVersion(),
+		Generation:      obj.GetGeneration(),
+	}, nil
+}
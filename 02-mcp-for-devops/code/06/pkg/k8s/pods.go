@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// crashLoopMinRestarts is how many restarts a container needs before recent restarts
+	// count as crash-looping rather than a one-off failure
+	crashLoopMinRestarts = 3
+	// crashLoopRecentWindow is how recently a restarting container must have last
+	// terminated to still be crash-looping. The kubelet's back-off tops out at five
+	// minutes, so a looping container terminates at least this often.
+	crashLoopRecentWindow = 10 * time.Minute
+)
+
+// DetectCrashLoop reports whether any of pod's containers is crash-looping: waiting in
+// CrashLoopBackOff, or restarted at least crashLoopMinRestarts times with the last
+// termination within crashLoopRecentWindow, which catches a container between back-offs.
+// Containers that restarted often in the past but have since stayed up are not flagged.
+func DetectCrashLoop(pod *corev1.Pod) bool {
+	return detectCrashLoop(pod, time.Now())
+}
+
+func detectCrashLoop(pod *corev1.Pod, now time.Time) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason == "CrashLoopBackOff" {
+			return true
+		}
+		last := status.LastTerminationState.Terminated
+		if status.RestartCount >= crashLoopMinRestarts && last != nil && now.Sub(last.FinishedAt.Time) <= crashLoopRecentWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// DiagnosePod collects what explains a failing pod in one call: its status, unmet
+// conditions, last container terminations, recent events, and the final log lines of the
+// container most likely at fault. Logs come from the container's previous instance when
+// it has restarted, since the current one has usually not failed yet. Missing events or
+// logs are logged and left out rather than failing the diagnosis.
+func (c *Client) DiagnosePod(ctx context.Context, namespace, name string, tailLines int64) (*PodDiagnosis, error) {
+	pod, err := c.getPod(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %w", namespace, name, err)
+	}
+
+	diagnosis := &PodDiagnosis{
+		Pod:          newPodInfo(pod),
+		Reason:       pod.Status.Reason,
+		Message:      pod.Status.Message,
+		Conditions:   unmetPodConditions(pod),
+		Terminations: lastTerminations(pod),
+	}
+	if diagnosis.Events, err = c.objectEvents(ctx, namespace, "Pod", name); err != nil {
+		c.logger.Warnf("Failed to get events for pod %s/%s: %v", namespace, name, err)
+	}
+	if len(diagnosis.Events) > maxPodEvents {
+		diagnosis.Events = diagnosis.Events[len(diagnosis.Events)-maxPodEvents:]
+	}
+
+	if len(pod.Spec.Containers) == 0 {
+		return diagnosis, nil
+	}
+	diagnosis.LogContainer = failedContainerName(pod)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == diagnosis.LogContainer && status.RestartCount > 0 {
+			diagnosis.PreviousLogs = true
+		}
+	}
+	logs, err := c.GetPodLogs(ctx, namespace, name, diagnosis.LogContainer, &tailLines, nil, diagnosis.PreviousLogs)
+	if err != nil {
+		c.logger.Warnf("Failed to get logs for pod %s/%s: %v", namespace, name, err)
+	}
+	diagnosis.Logs = logs
+
+	return diagnosis, nil
+}
+
+// unmetPodConditions returns the pod's conditions that are not true, formatted as
+// "<type>: <reason>: <message>", e.g. why a pod is unschedulable or not ready
+func unmetPodConditions(pod *corev1.Pod) []string {
+	var conditions []string
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		description := string(condition.Type)
+		if condition.Reason != "" {
+			description += ": " + condition.Reason
+		}
+		if condition.Message != "" {
+			description += ": " + condition.Message
+		}
+		conditions = append(conditions, description)
+	}
+	return conditions
+}
@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodOwner(t *testing.T) {
+	controlledBy := func(kind, name string) []metav1.OwnerReference {
+		isController := true
+		return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+	}
+	tests := []struct {
+		name      string
+		owners    []metav1.OwnerReference
+		labels    map[string]string
+		wantKind  string
+		wantOwner string
+	}{
+		{"bare pod", nil, nil, "", ""},
+		{"deployment", controlledBy("ReplicaSet", "web-7d4b9c8f6"), map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "7d4b9c8f6"}, "Deployment", "web"},
+		{"standalone replicaset", controlledBy("ReplicaSet", "web"), nil, "ReplicaSet", "web"},
+		{"hash from another template", controlledBy("ReplicaSet", "web-7d4b9c8f6"), map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "5f6d7c"}, "ReplicaSet", "web-7d4b9c8f6"},
+		{"job", controlledBy("Job", "backup-28923410"), nil, "Job", "backup-28923410"},
+		{"non-controller owner", []metav1.OwnerReference{{Kind: "ConfigMap", Name: "owner"}}, nil, "", ""},
+	}
+	for _, tt := range tests {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", OwnerReferences: tt.owners, Labels: tt.labels}}
+		kind, name := podOwner(pod)
+		if kind != tt.wantKind || name != tt.wantOwner {
+			t.Errorf("%s: owner = %s %q, want %s %q", tt.name, kind, name, tt.wantKind, tt.wantOwner)
+		}
+	}
+}
+
+func TestDetectCrashLoop(t *testing.T) {
+	now := time.Now()
+	terminatedAt := func(ago time.Duration) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(now.Add(-ago))}}
+	}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	tests := []struct {
+		name   string
+		status corev1.ContainerStatus
+		want   bool
+	}{
+		{"healthy", corev1.ContainerStatus{State: running}, false},
+		{"back-off", corev1.ContainerStatus{RestartCount: 1, State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}, true},
+		{"restarting between back-offs", corev1.ContainerStatus{RestartCount: 5, State: running, LastTerminationState: terminatedAt(2 * time.Minute)}, true},
+		{"one recent restart", corev1.ContainerStatus{RestartCount: 1, State: running, LastTerminationState: terminatedAt(2 * time.Minute)}, false},
+		{"recovered", corev1.ContainerStatus{RestartCount: 40, State: running, LastTerminationState: terminatedAt(3 * time.Hour)}, false},
+		{"image pull failure", corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}}, false},
+	}
+	for _, tt := range tests {
+		pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{tt.status}}}
+		if got := detectCrashLoop(pod, now); got != tt.want {
+			t.Errorf("%s: crash-looping = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnmetPodConditions(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+		{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "ContainersNotReady", Message: "containers with unready status: [app]"},
+		{Type: corev1.ContainersReady, Status: corev1.ConditionFalse},
+	}}}
+
+	got := unmetPodConditions(pod)
+	want := []string{"Ready: ContainersNotReady: containers with unready status: [app]", "ContainersReady"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("unmet conditions = %q, want %q", got, want)
+	}
+}
+
+func TestMainContainerSkipsSidecars(t *testing.T) {
+	sidecars := []string{"istio-proxy", "linkerd-proxy"}
+	podWith := func(annotations map[string]string, names ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+		for _, name := range names {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: name})
+		}
+		return pod
+	}
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want string
+	}{
+		{"single container", podWith(nil, "app"), "app"},
+		{"injected proxy first", podWith(nil, "istio-proxy", "app"), "app"},
+		{"only sidecars", podWith(nil, "linkerd-proxy"), "linkerd-proxy"},
+		{"annotated", podWith(map[string]string{defaultContainerAnnotation: "worker"}, "istio-proxy", "app", "worker"), "worker"},
+		{"annotation naming a missing container", podWith(map[string]string{defaultContainerAnnotation: "gone"}, "istio-proxy", "app"), "app"},
+		{"no containers", podWith(nil), ""},
+	}
+	for _, tt := range tests {
+		if got := mainContainer(tt.pod, sidecars); got != tt.want {
+			t.Errorf("%s: main container = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
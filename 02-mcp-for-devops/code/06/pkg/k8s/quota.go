@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// legacyCountResources are core resources whose object count may also be limited
+// by the pre-"count/" quota names, e.g. "configmaps" instead of "count/configmaps"
+var legacyCountResources = map[string]bool{
+	"configmaps":             true,
+	"persistentvolumeclaims": true,
+	"pods":                   true,
+	"replicationcontrollers": true,
+	"resourcequotas":         true,
+	"secrets":                true,
+	"services":               true,
+}
+
+// QuotaExceededError reports that creating an object would exceed an object-count quota
+type QuotaExceededError struct {
+	Namespace string
+	Quota     string
+	Resource  string
+	Hard      string
+	Used      string
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("this would exceed the object-count quota: %s in namespace %s allows %s %s and %s are already in use",
+		e.Quota, e.Namespace, e.Hard, e.Resource, e.Used)
+}
+
+// CheckObjectCountQuota predicts whether creating one more object of the given resource
+// in namespace would be rejected by an object-count ResourceQuota. It returns a
+// *QuotaExceededError when the create would fail and nil when there is room.
+//
+// The check is advisory: when the quotas cannot be listed, for example because the
+// server's service account may not list them, it is skipped and the API server's quota
+// admission still rejects creates over the limit.
+func (c *Client) CheckObjectCountQuota(ctx context.Context, namespace string, gvr schema.GroupVersionResource) error {
+	quotas, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		c.logger.Warnf("Skipping the object-count quota check in namespace %s: %v", namespace, err)
+		return nil
+	}
+
+	names := objectCountResourceNames(gvr)
+	for _, quota := range quotas.Items {
+		for _, name := range names {
+			hard, ok := quota.Status.Hard[name]
+			if !ok {
+				hard, ok = quota.Spec.Hard[name]
+			}
+			if !ok {
+				continue
+			}
+
+			used := quota.Status.Used[name]
+			if used.Value()+1 > hard.Value() {
+				return &QuotaExceededError{
+					Namespace: namespace,
+					Quota:     quota.Name,
+					Resource:  string(name),
+					Hard:      hard.String(),
+					Used:      used.String(),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// objectCountResourceNames returns the quota resource names that limit the object count of gvr
+func objectCountResourceNames(gvr schema.GroupVersionResource) []corev1.ResourceName {
+	if gvr.Group == "" {
+		names := []corev1.ResourceName{corev1.ResourceName("count/" + gvr.Resource)}
+		if legacyCountResources[gvr.Resource] {
+			names = append(names, corev1.ResourceName(gvr.Resource))
+		}
+		return names
+	}
+	return []corev1.ResourceName{corev1.ResourceName("count/" + gvr.Resource + "." + gvr.Group)}
+}
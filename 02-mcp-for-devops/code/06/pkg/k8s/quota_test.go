@@ -0,0 +1,34 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"kubernetes-mcp-server/internal/logging"
+)
+
+func TestCheckObjectCountQuotaSkipsUnlistableQuotas(t *testing.T) {
+	// A service account without list on resourcequotas
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Forbidden","code":403}`))
+	}))
+	defer server.Close()
+
+	clientset, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatalf("NewForConfig: %v", err)
+	}
+	c := &Client{clientset: clientset, logger: logging.NewLogger("error", "text")}
+
+	if err := c.CheckObjectCountQuota(context.Background(), "dev", corev1.SchemeGroupVersion.WithResource("configmaps")); err != nil {
+		t.Errorf("CheckObjectCountQuota = %v, want nil when quotas cannot be listed", err)
+	}
+}
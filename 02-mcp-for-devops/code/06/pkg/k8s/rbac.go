@@ -0,0 +1,205 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListRoles lists Roles in a namespace, optionally including cluster-wide ClusterRoles
+func (c *Client) ListRoles(ctx context.Context, namespace string, includeClusterRoles bool) ([]RoleInfo, error) {
+	roles, err := c.clientset.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles in namespace %s: %w", namespace, err)
+	}
+
+	var roleInfos []RoleInfo
+	for _, role := range roles.Items {
+		roleInfos = append(roleInfos, RoleInfo{
+			Name:      role.Name,
+			Namespace: role.Namespace,
+			Kind:      "Role",
+			Rules:     getPolicyRules(role.Rules),
+			Labels:    role.Labels,
+			CreatedAt: role.CreationTimestamp.Time,
+		})
+	}
+
+	if includeClusterRoles {
+		clusterRoles, err := c.clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cluster roles: %w", err)
+		}
+
+		for _, role := range clusterRoles.Items {
+			roleInfos = append(roleInfos, RoleInfo{
+				Name:      role.Name,
+				Kind:      "ClusterRole",
+				Rules:     getPolicyRules(role.Rules),
+				Labels:    role.Labels,
+				CreatedAt: role.CreationTimestamp.Time,
+			})
+		}
+	}
+
+	return roleInfos, nil
+}
+
+// ListRoleBindings lists RoleBindings in a namespace, optionally including ClusterRoleBindings
+func (c *Client) ListRoleBindings(ctx context.Context, namespace string, includeClusterBindings bool) ([]RoleBindingInfo, error) {
+	bindings, err := c.clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings in namespace %s: %w", namespace, err)
+	}
+
+	var bindingInfos []RoleBindingInfo
+	for _, binding := range bindings.Items {
+		bindingInfos = append(bindingInfos, RoleBindingInfo{
+			Name:      binding.Name,
+			Namespace: binding.Namespace,
+			Kind:      "RoleBinding",
+			RoleKind:  binding.RoleRef.Kind,
+			RoleName:  binding.RoleRef.Name,
+			Subjects:  getSubjects(binding.Subjects),
+			CreatedAt: binding.CreationTimestamp.Time,
+		})
+	}
+
+	if includeClusterBindings {
+		clusterBindings, err := c.clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cluster role bindings: %w", err)
+		}
+
+		for _, binding := range clusterBindings.Items {
+			bindingInfos = append(bindingInfos, RoleBindingInfo{
+				Name:      binding.Name,
+				Kind:      "ClusterRoleBinding",
+				RoleKind:  binding.RoleRef.Kind,
+				RoleName:  binding.RoleRef.Name,
+				Subjects:  getSubjects(binding.Subjects),
+				CreatedAt: binding.CreationTimestamp.Time,
+			})
+		}
+	}
+
+	return bindingInfos, nil
+}
+
+// WhoCan lists the subjects allowed to perform verb on resource in the given namespace.
+// Both RoleBindings in the namespace and cluster-wide ClusterRoleBindings are considered.
+// An empty apiGroup matches rules for any API group.
+func (c *Client) WhoCan(ctx context.Context, namespace, verb, resource, apiGroup string) ([]AccessGrant, error) {
+	roles, err := c.clientset.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles in namespace %s: %w", namespace, err)
+	}
+	clusterRoles, err := c.clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster roles: %w", err)
+	}
+	bindings, err := c.clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list role bindings in namespace %s: %w", namespace, err)
+	}
+	clusterBindings, err := c.clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cluster role bindings: %w", err)
+	}
+
+	roleRules := make(map[string][]rbacv1.PolicyRule)
+	for _, role := range roles.Items {
+		roleRules["Role/"+role.Name] = role.Rules
+	}
+	for _, role := range clusterRoles.Items {
+		roleRules["ClusterRole/"+role.Name] = role.Rules
+	}
+
+	var grants []AccessGrant
+	addGrants := func(bindingKind, bindingName string, roleRef rbacv1.RoleRef, subjects []rbacv1.Subject) {
+		rules, exists := roleRules[roleRef.Kind+"/"+roleRef.Name]
+		if !exists {
+			return
+		}
+		allowed, resourceNames := rulesAllow(rules, verb, resource, apiGroup)
+		if !allowed {
+			return
+		}
+		for _, subject := range getSubjects(subjects) {
+			grants = append(grants, AccessGrant{
+				Subject:       subject,
+				BindingKind:   bindingKind,
+				BindingName:   bindingName,
+				RoleKind:      roleRef.Kind,
+				RoleName:      roleRef.Name,
+				ResourceNames: resourceNames,
+			})
+		}
+	}
+
+	for _, binding := range bindings.Items {
+		addGrants("RoleBinding", binding.Name, binding.RoleRef, binding.Subjects)
+	}
+	for _, binding := range clusterBindings.Items {
+		addGrants("ClusterRoleBinding", binding.Name, binding.RoleRef, binding.Subjects)
+	}
+
+	return grants, nil
+}
+
+// rulesAllow reports whether any rule grants verb on resource. When the only matching
+// rules are restricted to specific resource names, those names are returned.
+func rulesAllow(rules []rbacv1.PolicyRule, verb, resource, apiGroup string) (bool, []string) {
+	allowed := false
+	var resourceNames []string
+	for _, rule := range rules {
+		if !matchesRuleValue(rule.Verbs, verb) || !matchesRuleValue(rule.Resources, resource) {
+			continue
+		}
+		if apiGroup != "" && !matchesRuleValue(rule.APIGroups, apiGroup) {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 {
+			return true, nil
+		}
+		allowed = true
+		resourceNames = append(resourceNames, rule.ResourceNames...)
+	}
+	return allowed, resourceNames
+}
+
+func matchesRuleValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func getPolicyRules(rules []rbacv1.PolicyRule) []PolicyRuleInfo {
+	var ruleInfos []PolicyRuleInfo
+	for _, rule := range rules {
+		ruleInfos = append(ruleInfos, PolicyRuleInfo{
+			Verbs:         rule.Verbs,
+			APIGroups:     rule.APIGroups,
+			Resources:     rule.Resources,
+			ResourceNames: rule.ResourceNames,
+		})
+	}
+	return ruleInfos
+}
+
+func getSubjects(subjects []rbacv1.Subject) []SubjectInfo {
+	var subjectInfos []SubjectInfo
+	for _, subject := range subjects {
+		subjectInfos = append(subjectInfos, SubjectInfo{
+			Kind:      subject.Kind,
+			Name:      subject.Name,
+			Namespace: subject.Namespace,
+		})
+	}
+	return subjectInfos
+}
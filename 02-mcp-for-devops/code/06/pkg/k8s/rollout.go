@@ -0,0 +1,229 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typesv1 "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	RolloutStateComplete    = "Complete"
+	RolloutStateProgressing = "Progressing"
+	RolloutStateStalled     = "Stalled"
+)
+
+const (
+	// revisionAnnotation is set by the deployment controller on deployments and their ReplicaSets
+	revisionAnnotation = "deployment.kubernetes.io/revision"
+	// changeCauseAnnotation records why a revision was created, e.g. by kubectl annotate
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+	// progressDeadlineExceeded is the Progressing condition reason for a stalled rollout
+	progressDeadlineExceeded = "ProgressDeadlineExceeded"
+)
+
+// GetRolloutStatus reports whether a deployment's latest rollout is complete, still
+// progressing, or stalled past its progress deadline, using the same rules as
+// kubectl rollout status
+func (c *Client) GetRolloutStatus(ctx context.Context, namespace, name string) (*RolloutStatus, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+	}
+
+	status := &RolloutStatus{
+		Name:              deployment.Name,
+		Namespace:         deployment.Namespace,
+		Revision:          revisionOf(deployment.ObjectMeta),
+		DesiredReplicas:   1,
+		UpdatedReplicas:   deployment.Status.UpdatedReplicas,
+		ReadyReplicas:     deployment.Status.ReadyReplicas,
+		AvailableReplicas: deployment.Status.AvailableReplicas,
+		Conditions:        getDeploymentConditions(deployment),
+	}
+	if deployment.Spec.Replicas != nil {
+		status.DesiredReplicas = *deployment.Spec.Replicas
+	}
+
+	status.State, status.Message = rolloutState(deployment, status.DesiredReplicas)
+	return status, nil
+}
+
+// rolloutState classifies a deployment's rollout and explains what it is waiting for
+func rolloutState(deployment *appsv1.Deployment, desired int32) (string, string) {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return RolloutStateProgressing, "Waiting for the deployment spec update to be observed"
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == progressDeadlineExceeded {
+			return RolloutStateStalled, fmt.Sprintf("Rollout exceeded its progress deadline: %s", condition.Message)
+		}
+	}
+
+	updated := deployment.Status.UpdatedReplicas
+	switch {
+	case updated < desired:
+		return RolloutStateProgressing, fmt.Sprintf("%d of %d new replicas have been updated", updated, desired)
+	case deployment.Status.Replicas > updated:
+		return RolloutStateProgressing, fmt.Sprintf("%d old replicas are pending termination", deployment.Status.Replicas-updated)
+	case deployment.Status.AvailableReplicas < updated:
+		return RolloutStateProgressing, fmt.Sprintf("%d of %d updated replicas are available", deployment.Status.AvailableReplicas, updated)
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable && condition.Status != corev1.ConditionTrue {
+			return RolloutStateProgressing, fmt.Sprintf("Deployment is not yet available: %s", condition.Message)
+		}
+	}
+
+	return RolloutStateComplete, "Rollout completed successfully"
+}
+
+// GetRolloutHistory lists a deployment's revisions, oldest first, from the ReplicaSets it owns
+func (c *Client) GetRolloutHistory(ctx context.Context, namespace, name string) ([]RolloutRevision, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+	}
+
+	replicaSets, err := c.deploymentReplicaSets(ctx, deployment)
+	if err != nil {
+		return nil, err
+	}
+
+	currentRevision := revisionOf(deployment.ObjectMeta)
+	revisions := make([]RolloutRevision, 0, len(replicaSets))
+	for _, replicaSet := range replicaSets {
+		var images []string
+		for _, container := range replicaSet.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+
+		revision := revisionOf(replicaSet.ObjectMeta)
+		var replicas int32
+		if replicaSet.Spec.Replicas != nil {
+			replicas = *replicaSet.Spec.Replicas
+		}
+		revisions = append(revisions, RolloutRevision{
+			Revision:    revision,
+			ReplicaSet:  replicaSet.Name,
+			Images:      images,
+			ChangeCause: replicaSet.Annotations[changeCauseAnnotation],
+			Replicas:    replicas,
+			Current:     revision == currentRevision,
+			CreatedAt:   replicaSet.CreationTimestamp.Time,
+		})
+	}
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+
+	return revisions, nil
+}
+
+// RollbackDeployment restores a deployment's pod template from the ReplicaSet of an
+// earlier revision, like kubectl rollout undo. A toRevision of 0 means the revision
+// before the current one. It returns the revisions rolled back from and to.
+func (c *Client) RollbackDeployment(ctx context.Context, namespace, name string, toRevision int64) (int64, int64, error) {
+	start := time.Now()
+	defer func() {
+		c.logger.LogK8sOperation(ctx, "rollback_deployment", namespace, name, time.Since(start), nil)
+	}()
+
+	deployment, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get deployment %s/%s: %w", namespace, name, err)
+	}
+	if deployment.Spec.Paused {
+		return 0, 0, fmt.Errorf("deployment %s/%s is paused; resume it before rolling back", namespace, name)
+	}
+
+	replicaSets, err := c.deploymentReplicaSets(ctx, deployment)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	currentRevision := revisionOf(deployment.ObjectMeta)
+	var target *appsv1.ReplicaSet
+	var targetRevision int64
+	for _, replicaSet := range replicaSets {
+		revision := revisionOf(replicaSet.ObjectMeta)
+		if toRevision == 0 {
+			// The previous revision is the newest one older than the current
+			if revision < currentRevision && revision > targetRevision {
+				target, targetRevision = replicaSet, revision
+			}
+		} else if revision == toRevision {
+			target, targetRevision = replicaSet, revision
+		}
+	}
+
+	switch {
+	case target == nil && toRevision == 0:
+		return currentRevision, 0, fmt.Errorf("deployment %s/%s has no previous revision to roll back to", namespace, name)
+	case target == nil:
+		return currentRevision, 0, fmt.Errorf("revision %d of deployment %s/%s not found", toRevision, namespace, name)
+	case targetRevision == currentRevision:
+		return currentRevision, targetRevision, fmt.Errorf("deployment %s/%s is already at revision %d", namespace, name, targetRevision)
+	}
+
+	// The controller adds pod-template-hash to each ReplicaSet's template; it must not
+	// be copied back into the deployment
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "replace", "path": "/spec/template", "value": template},
+	})
+	if err != nil {
+		return currentRevision, targetRevision, fmt.Errorf("failed to build rollback patch: %w", err)
+	}
+
+	_, err = c.clientset.AppsV1().Deployments(namespace).Patch(ctx, name, typesv1.JSONPatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return currentRevision, targetRevision, fmt.Errorf("failed to roll back deployment %s/%s: %w", namespace, name, err)
+	}
+
+	return currentRevision, targetRevision, nil
+}
+
+// deploymentReplicaSets returns the ReplicaSets controlled by a deployment
+func (c *Client) deploymentReplicaSets(ctx context.Context, deployment *appsv1.Deployment) ([]*appsv1.ReplicaSet, error) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+	}
+
+	list, err := c.clientset.AppsV1().ReplicaSets(deployment.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets for deployment %s/%s: %w", deployment.Namespace, deployment.Name, err)
+	}
+
+	var owned []*appsv1.ReplicaSet
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], deployment) {
+			owned = append(owned, &list.Items[i])
+		}
+	}
+	return owned, nil
+}
+
+// revisionOf reads the deployment controller's revision annotation, 0 if unset
+func revisionOf(meta metav1.ObjectMeta) int64 {
+	revision, err := strconv.ParseInt(meta.Annotations[revisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
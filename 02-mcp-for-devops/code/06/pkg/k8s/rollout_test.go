@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRolloutState(t *testing.T) {
+	deployment := func(generation, observed int64, replicas, updated, available int32, conditions ...appsv1.DeploymentCondition) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: observed,
+				Replicas:           replicas,
+				UpdatedReplicas:    updated,
+				AvailableReplicas:  available,
+				Conditions:         conditions,
+			},
+		}
+	}
+	stalled := appsv1.DeploymentCondition{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: progressDeadlineExceeded}
+	unavailable := appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse}
+
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		want       string
+	}{
+		{name: "spec not observed", deployment: deployment(2, 1, 3, 3, 3), want: RolloutStateProgressing},
+		{name: "deadline exceeded", deployment: deployment(2, 2, 3, 1, 2, stalled), want: RolloutStateStalled},
+		{name: "updating", deployment: deployment(2, 2, 3, 1, 3), want: RolloutStateProgressing},
+		{name: "old replicas terminating", deployment: deployment(2, 2, 4, 3, 3), want: RolloutStateProgressing},
+		{name: "updated not available", deployment: deployment(2, 2, 3, 3, 2), want: RolloutStateProgressing},
+		{name: "not available", deployment: deployment(2, 2, 3, 3, 3, unavailable), want: RolloutStateProgressing},
+		{name: "complete", deployment: deployment(2, 2, 3, 3, 3), want: RolloutStateComplete},
+	}
+
+	for _, tt := range tests {
+		if got, message := rolloutState(tt.deployment, 3); got != tt.want {
+			t.Errorf("%s: got %s (%s), want %s", tt.name, got, message, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ListPVCs lists persistent volume claims in a namespace, or in all namespaces when namespace is empty
+func (c *Client) ListPVCs(ctx context.Context, namespace string) ([]PVCInfo, error) {
+	claims, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumeclaims in namespace %s: %w", namespace, err)
+	}
+
+	var pvcInfos []PVCInfo
+	for i := range claims.Items {
+		pvcInfos = append(pvcInfos, newPVCInfo(&claims.Items[i]))
+	}
+
+	return pvcInfos, nil
+}
+
+// ListPVs lists the cluster's persistent volumes
+func (c *Client) ListPVs(ctx context.Context) ([]PVInfo, error) {
+	volumes, err := c.clientset.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistentvolumes: %w", err)
+	}
+
+	var pvInfos []PVInfo
+	for i := range volumes.Items {
+		pvInfos = append(pvInfos, newPVInfo(&volumes.Items[i]))
+	}
+
+	return pvInfos, nil
+}
+
+func (c *Client) getPVCDetails(ctx context.Context, namespace, name string) (string, error) {
+	claim, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get persistentvolumeclaim %s/%s: %w", namespace, name, err)
+	}
+
+	// Pods referencing the claim; a Pending pod here usually means the claim is unbound
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+	var usedBy []string
+	for _, pod := range pods.Items {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == name {
+				usedBy = append(usedBy, fmt.Sprintf("%s (%s)", pod.Name, pod.Status.Phase))
+				break
+			}
+		}
+	}
+
+	// Provisioning failures such as "no persistent volumes available" only show up as events
+	events, err := c.objectEvents(ctx, namespace, "PersistentVolumeClaim", name)
+	if err != nil {
+		c.logger.Warnf("Failed to list events for persistentvolumeclaim %s/%s: %v", namespace, name, err)
+	}
+
+	volumeMode := ""
+	if claim.Spec.VolumeMode != nil {
+		volumeMode = string(*claim.Spec.VolumeMode)
+	}
+
+	pvcInfo := newPVCInfo(claim)
+	pvcDetail := struct {
+		*PVCInfo
+		RequestedStorage string   `json:"requestedStorage"`
+		VolumeMode       string   `json:"volumeMode,omitempty"`
+		UsedBy           []string `json:"usedBy"`
+		Events           []string `json:"events,omitempty"`
+	}{
+		PVCInfo:          &pvcInfo,
+		RequestedStorage: claim.Spec.Resources.Requests.Storage().String(),
+		VolumeMode:       volumeMode,
+		UsedBy:           usedBy,
+		Events:           events,
+	}
+
+	data, err := json.MarshalIndent(pvcDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal persistentvolumeclaim details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func (c *Client) getPVDetails(ctx context.Context, name string) (string, error) {
+	volume, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get persistentvolume %s: %w", name, err)
+	}
+
+	pvInfo := newPVInfo(volume)
+	pvDetail := struct {
+		*PVInfo
+		Reason     string            `json:"reason,omitempty"`
+		Message    string            `json:"message,omitempty"`
+		Source     string            `json:"source"`
+		MountFlags []string          `json:"mountOptions,omitempty"`
+		Labels     map[string]string `json:"labels"`
+	}{
+		PVInfo:     &pvInfo,
+		Reason:     volume.Status.Reason,
+		Message:    volume.Status.Message,
+		Source:     volumeSource(volume),
+		MountFlags: volume.Spec.MountOptions,
+		Labels:     volume.Labels,
+	}
+
+	data, err := json.MarshalIndent(pvDetail, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal persistentvolume details: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func newPVCInfo(claim *corev1.PersistentVolumeClaim) PVCInfo {
+	pvcInfo := PVCInfo{
+		Name:        claim.Name,
+		Namespace:   claim.Namespace,
+		Status:      string(claim.Status.Phase),
+		VolumeName:  claim.Spec.VolumeName,
+		AccessModes: accessModes(claim.Spec.AccessModes),
+		Labels:      claim.Labels,
+		CreatedAt:   claim.CreationTimestamp.Time,
+	}
+	if storage, ok := claim.Status.Capacity[corev1.ResourceStorage]; ok {
+		pvcInfo.Capacity = storage.String()
+	}
+	if claim.Spec.StorageClassName != nil {
+		pvcInfo.StorageClass = *claim.Spec.StorageClassName
+	}
+	return pvcInfo
+}
+
+func newPVInfo(volume *corev1.PersistentVolume) PVInfo {
+	pvInfo := PVInfo{
+		Name:          volume.Name,
+		Capacity:      volume.Spec.Capacity.Storage().String(),
+		AccessModes:   accessModes(volume.Spec.AccessModes),
+		ReclaimPolicy: string(volume.Spec.PersistentVolumeReclaimPolicy),
+		Status:        string(volume.Status.Phase),
+		StorageClass:  volume.Spec.StorageClassName,
+		CreatedAt:     volume.CreationTimestamp.Time,
+	}
+	if ref := volume.Spec.ClaimRef; ref != nil {
+		pvInfo.ClaimRef = ref.Namespace + "/" + ref.Name
+	}
+	return pvInfo
+}
+
+func accessModes(modes []corev1.PersistentVolumeAccessMode) []string {
+	result := make([]string, 0, len(modes))
+	for _, mode := range modes {
+		result = append(result, string(mode))
+	}
+	return result
+}
+
+// volumeSource names the backing storage of a persistent volume
+func volumeSource(volume *corev1.PersistentVolume) string {
+	source := volume.Spec.PersistentVolumeSource
+	switch {
+	case source.CSI != nil:
+		return "csi:" + source.CSI.Driver
+	case source.HostPath != nil:
+		return "hostPath:" + source.HostPath.Path
+	case source.Local != nil:
+		return "local:" + source.Local.Path
+	case source.NFS != nil:
+		return "nfs:" + source.NFS.Server + ":" + source.NFS.Path
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultSweepLimit is the page size used by cluster-wide sweeps when none is given
+	DefaultSweepLimit = 100
+	// MaxSweepLimit caps the page size of cluster-wide sweeps
+	MaxSweepLimit = 500
+)
+
+// SweepCursor records where a cluster-wide sweep stopped: the namespace being
+// scanned and the API server continue token within that namespace.
+type SweepCursor struct {
+	Namespace string `json:"ns"`
+	Continue  string `json:"continue,omitempty"`
+}
+
+// SweepPage is one page of results from a cluster-wide sweep
+type SweepPage[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// NamespacePageFunc lists one page of items in a single namespace. It may filter
+// items client-side, so it can return fewer items than opts.Limit.
+type NamespacePageFunc[T any] func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]T, string, error)
+
+// EncodeSweepCursor turns a cursor into an opaque string safe to hand to clients
+func EncodeSweepCursor(cursor SweepCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSweepCursor is the inverse of EncodeSweepCursor. An empty string decodes
+// to the zero cursor, which starts a sweep from the first namespace.
+func DecodeSweepCursor(encoded string) (SweepCursor, error) {
+	var cursor SweepCursor
+	if encoded == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("invalid cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// SweepNamespaces walks namespaces in name order, collecting up to limit items and
+// returning a cursor that resumes exactly where this page stopped. Namespaces are
+// visited in sorted order, so a cursor stays valid even if its namespace is deleted
+// between calls: the sweep resumes at the next namespace by name.
+func SweepNamespaces[T any](ctx context.Context, namespaces []string, limit int, encodedCursor string, list NamespacePageFunc[T]) (*SweepPage[T], error) {
+	if limit <= 0 {
+		limit = DefaultSweepLimit
+	}
+	if limit > MaxSweepLimit {
+		limit = MaxSweepLimit
+	}
+
+	cursor, err := DecodeSweepCursor(encodedCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]string(nil), namespaces...)
+	sort.Strings(sorted)
+
+	start := sort.SearchStrings(sorted, cursor.Namespace)
+	continueToken := ""
+	if start < len(sorted) && sorted[start] == cursor.Namespace {
+		continueToken = cursor.Continue
+	}
+
+	page := &SweepPage[T]{}
+	for i := start; i < len(sorted); i++ {
+		namespace := sorted[i]
+		for {
+			items, next, err := list(ctx, namespace, metav1.ListOptions{
+				Limit:    int64(limit - len(page.Items)),
+				Continue: continueToken,
+			})
+			if err != nil {
+				return nil, err
+			}
+			page.Items = append(page.Items, items...)
+			continueToken = next
+
+			if len(page.Items) >= limit {
+				switch {
+				case continueToken != "":
+					page.NextCursor = EncodeSweepCursor(SweepCursor{Namespace: namespace, Continue: continueToken})
+				case i+1 < len(sorted):
+					page.NextCursor = EncodeSweepCursor(SweepCursor{Namespace: sorted[i+1]})
+				}
+				return page, nil
+			}
+			if continueToken == "" {
+				break
+			}
+		}
+	}
+
+	return page, nil
+}
+
+// namespaceNames returns the names of all namespaces in the cluster, or of those in the
+// allow-list when one is set
+func (c *Client) namespaceNames(ctx context.Context) ([]string, error) {
+	if len(c.allowedNamespaces) > 0 {
+		return append([]string(nil), c.allowedNamespaces...), nil
+	}
+
+	namespaces, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	names := make([]string, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeNamespacePages serves items per namespace in pages of at most opts.Limit,
+// using the item offset as the continue token.
+func fakeNamespacePages(items map[string][]string) NamespacePageFunc[string] {
+	return func(_ context.Context, namespace string, opts metav1.ListOptions) ([]string, string, error) {
+		all := items[namespace]
+		start := 0
+		if opts.Continue != "" {
+			start, _ = strconv.Atoi(opts.Continue)
+		}
+		end := start + int(opts.Limit)
+		if end >= len(all) {
+			return all[start:], "", nil
+		}
+		return all[start:end], strconv.Itoa(end), nil
+	}
+}
+
+func TestSweepNamespacesResumesAcrossNamespaces(t *testing.T) {
+	items := map[string][]string{
+		"a": {"a1", "a2", "a3"},
+		"b": {},
+		"c": {"c1", "c2"},
+	}
+	namespaces := []string{"c", "a", "b"}
+	list := fakeNamespacePages(items)
+
+	var got []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("sweep did not terminate")
+		}
+		page, err := SweepNamespaces(context.Background(), namespaces, 2, cursor, list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, page.Items...)
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	want := []string{"a1", "a2", "a3", "c1", "c2"}
+	if len(got) != len(want) {
+		t.Fatalf("want %v got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("want %v got %v", want, got)
+		}
+	}
+}
+
+func TestSweepNamespacesSurvivesDeletedNamespace(t *testing.T) {
+	cursor := EncodeSweepCursor(SweepCursor{Namespace: "b", Continue: "5"})
+	items := map[string][]string{"a": {"a1"}, "c": {"c1"}}
+
+	page, err := SweepNamespaces(context.Background(), []string{"a", "c"}, 10, cursor, fakeNamespacePages(items))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0] != "c1" {
+		t.Fatalf("want [c1] got %v", page.Items)
+	}
+}
+
+func TestDecodeSweepCursorRejectsGarbage(t *testing.T) {
+	if _, err := DecodeSweepCursor("not a cursor!"); err == nil {
+		t.Fatal("expected an error for a malformed cursor")
+	}
+}
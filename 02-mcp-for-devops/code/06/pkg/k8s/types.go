@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -14,6 +15,14 @@ type PodInfo struct {
 	Labels    map[string]string `json:"labels"`
 	CreatedAt time.Time         `json:"createdAt"`
 	Restarts  int32             `json:"restarts"`
+	// CrashLooping is set when a container keeps crashing and restarting; see DetectCrashLoop
+	CrashLooping bool   `json:"crashLooping"`
+	QOSClass     string `json:"qosClass,omitempty"` // Guaranteed, Burstable, or BestEffort
+	// OwnerKind and OwnerName name the workload managing the pod, e.g. Deployment web.
+	// Both are empty for a bare pod.
+	OwnerKind  string          `json:"ownerKind,omitempty"`
+	OwnerName  string          `json:"ownerName,omitempty"`
+	Containers []ContainerInfo `json:"containers,omitempty"`
 }
 
 // ServiceInfo represents essential service information
@@ -46,6 +55,136 @@ type DeploymentInfo struct {
 	Strategy        string            `json:"strategy"`
 }
 
+// RolloutStatus describes how far a deployment's latest rollout has progressed
+type RolloutStatus struct {
+	Name              string   `json:"name"`
+	Namespace         string   `json:"namespace"`
+	State             string   `json:"state"` // "Complete", "Progressing", or "Stalled"
+	Message           string   `json:"message"`
+	Revision          int64    `json:"revision"`
+	DesiredReplicas   int32    `json:"desiredReplicas"`
+	UpdatedReplicas   int32    `json:"updatedReplicas"`
+	ReadyReplicas     int32    `json:"readyReplicas"`
+	AvailableReplicas int32    `json:"availableReplicas"`
+	Conditions        []string `json:"conditions"`
+}
+
+// RolloutRevision is one revision of a deployment, backed by a ReplicaSet
+type RolloutRevision struct {
+	Revision    int64     `json:"revision"`
+	ReplicaSet  string    `json:"replicaSet"`
+	Images      []string  `json:"images"`
+	ChangeCause string    `json:"changeCause,omitempty"`
+	Replicas    int32     `json:"replicas"`
+	Current     bool      `json:"current"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// StatefulSetInfo represents essential statefulset information
+type StatefulSetInfo struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	Replicas        int32             `json:"replicas"`
+	ReadyReplicas   int32             `json:"readyReplicas"`
+	CurrentRevision string            `json:"currentRevision"`
+	UpdateRevision  string            `json:"updateRevision"`
+	ServiceName     string            `json:"serviceName"`
+	Labels          map[string]string `json:"labels"`
+	CreatedAt       time.Time         `json:"createdAt"`
+}
+
+// DaemonSetInfo represents essential daemonset information
+type DaemonSetInfo struct {
+	Name             string            `json:"name"`
+	Namespace        string            `json:"namespace"`
+	DesiredScheduled int32             `json:"desiredScheduled"`
+	CurrentScheduled int32             `json:"currentScheduled"`
+	Ready            int32             `json:"ready"`
+	Available        int32             `json:"available"`
+	UpdateStrategy   string            `json:"updateStrategy"`
+	Labels           map[string]string `json:"labels"`
+	CreatedAt        time.Time         `json:"createdAt"`
+}
+
+// EventInfo represents a Kubernetes event recorded for an object
+type EventInfo struct {
+	Type           string    `json:"type"`
+	Reason         string    `json:"reason"`
+	Message        string    `json:"message"`
+	Count          int32     `json:"count"`
+	FirstTimestamp time.Time `json:"firstTimestamp"`
+	LastTimestamp  time.Time `json:"lastTimestamp"`
+}
+
+// String formats the event as "<type> <reason>: <message>", noting repeats
+func (e EventInfo) String() string {
+	message := fmt.Sprintf("%s %s: %s", e.Type, e.Reason, e.Message)
+	if e.Count > 1 {
+		message = fmt.Sprintf("%s (x%d)", message, e.Count)
+	}
+	return message
+}
+
+// HPAInfo represents essential HorizontalPodAutoscaler information. Utilization values
+// are percentages of the pods' resource requests and are nil when not configured or
+// not yet reported.
+type HPAInfo struct {
+	Name                     string            `json:"name"`
+	Namespace                string            `json:"namespace"`
+	TargetRef                string            `json:"targetRef"`
+	MinReplicas              int32             `json:"minReplicas"`
+	MaxReplicas              int32             `json:"maxReplicas"`
+	CurrentReplicas          int32             `json:"currentReplicas"`
+	DesiredReplicas          int32             `json:"desiredReplicas"`
+	CurrentCPUUtilization    *int32            `json:"currentCPUUtilization,omitempty"`
+	TargetCPUUtilization     *int32            `json:"targetCPUUtilization,omitempty"`
+	CurrentMemoryUtilization *int32            `json:"currentMemoryUtilization,omitempty"`
+	TargetMemoryUtilization  *int32            `json:"targetMemoryUtilization,omitempty"`
+	LastScaleTime            *time.Time        `json:"lastScaleTime,omitempty"`
+	Labels                   map[string]string `json:"labels"`
+	CreatedAt                time.Time         `json:"createdAt"`
+}
+
+// NodeInfo represents essential node information
+type NodeInfo struct {
+	Name              string            `json:"name"`
+	Roles             []string          `json:"roles"`
+	KubeletVersion    string            `json:"kubeletVersion"`
+	OSImage           string            `json:"osImage"`
+	Conditions        map[string]string `json:"conditions"`
+	AllocatableCPU    string            `json:"allocatableCPU"`
+	AllocatableMemory string            `json:"allocatableMemory"`
+	Taints            []string          `json:"taints,omitempty"`
+	Unschedulable     bool              `json:"unschedulable"`
+	Labels            map[string]string `json:"labels"`
+	CreatedAt         time.Time         `json:"createdAt"`
+}
+
+// PVCInfo represents essential persistent volume claim information
+type PVCInfo struct {
+	Name         string            `json:"name"`
+	Namespace    string            `json:"namespace"`
+	Status       string            `json:"status"`
+	VolumeName   string            `json:"volumeName,omitempty"`
+	Capacity     string            `json:"capacity,omitempty"`
+	AccessModes  []string          `json:"accessModes"`
+	StorageClass string            `json:"storageClass,omitempty"`
+	Labels       map[string]string `json:"labels"`
+	CreatedAt    time.Time         `json:"createdAt"`
+}
+
+// PVInfo represents essential persistent volume information
+type PVInfo struct {
+	Name          string    `json:"name"`
+	Capacity      string    `json:"capacity"`
+	AccessModes   []string  `json:"accessModes"`
+	ReclaimPolicy string    `json:"reclaimPolicy"`
+	Status        string    `json:"status"`
+	ClaimRef      string    `json:"claimRef,omitempty"`
+	StorageClass  string    `json:"storageClass,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
 // NamespaceInfo represents essential namespace information
 type NamespaceInfo struct {
 	Name      string            `json:"name"`
@@ -62,3 +201,244 @@ type ConfigMapInfo struct {
 	Labels    map[string]string `json:"labels"`
 	CreatedAt time.Time         `json:"createdAt"`
 }
+
+// SecretInfo represents essential secret information. Values are never included,
+// only the names of the keys the secret holds.
+type SecretInfo struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Type      string            `json:"type"`
+	Keys      []string          `json:"keys"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// PolicyRuleInfo represents a single rule of a Role or ClusterRole
+type PolicyRuleInfo struct {
+	Verbs         []string `json:"verbs"`
+	APIGroups     []string `json:"apiGroups,omitempty"`
+	Resources     []string `json:"resources,omitempty"`
+	ResourceNames []string `json:"resourceNames,omitempty"`
+}
+
+// RoleInfo represents essential Role or ClusterRole information
+type RoleInfo struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace,omitempty"`
+	Kind      string            `json:"kind"`
+	Rules     []PolicyRuleInfo  `json:"rules"`
+	Labels    map[string]string `json:"labels"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// SubjectInfo identifies a user, group, or service account in a binding
+type SubjectInfo struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RoleBindingInfo represents essential RoleBinding or ClusterRoleBinding information
+type RoleBindingInfo struct {
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace,omitempty"`
+	Kind      string        `json:"kind"`
+	RoleKind  string        `json:"roleKind"`
+	RoleName  string        `json:"roleName"`
+	Subjects  []SubjectInfo `json:"subjects"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// AccessGrant describes a subject permitted to perform an action and the binding granting it
+type AccessGrant struct {
+	Subject       SubjectInfo `json:"subject"`
+	BindingKind   string      `json:"bindingKind"`
+	BindingName   string      `json:"bindingName"`
+	RoleKind      string      `json:"roleKind"`
+	RoleName      string      `json:"roleName"`
+	ResourceNames []string    `json:"resourceNames,omitempty"`
+}
+
+// JobInfo represents essential Job information
+type JobInfo struct {
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	Status         string            `json:"status"` // "Complete", "Failed", or "Running"
+	Completions    int32             `json:"completions"`
+	Succeeded      int32             `json:"succeeded"`
+	Failed         int32             `json:"failed"`
+	Active         int32             `json:"active"`
+	FailureReason  string            `json:"failureReason,omitempty"`
+	FailureMessage string            `json:"failureMessage,omitempty"`
+	StartTime      *time.Time        `json:"startTime,omitempty"`
+	CompletionTime *time.Time        `json:"completionTime,omitempty"`
+	Labels         map[string]string `json:"labels"`
+	CreatedAt      time.Time         `json:"createdAt"`
+}
+
+// CronJobInfo represents essential CronJob information
+type CronJobInfo struct {
+	Name               string            `json:"name"`
+	Namespace          string            `json:"namespace"`
+	Schedule           string            `json:"schedule"`
+	TimeZone           string            `json:"timeZone,omitempty"`
+	Suspend            bool              `json:"suspend"`
+	LastScheduleTime   *time.Time        `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *time.Time        `json:"lastSuccessfulTime,omitempty"`
+	ActiveJobs         int               `json:"activeJobs"`
+	NextScheduleTime   *time.Time        `json:"nextScheduleTime,omitempty"`
+	ScheduleError      string            `json:"scheduleError,omitempty"`
+	Labels             map[string]string `json:"labels"`
+	CreatedAt          time.Time         `json:"createdAt"`
+}
+
+// PodDiagnosis bundles a pod's status with the signals that explain why it is failing
+type PodDiagnosis struct {
+	Pod          PodInfo                `json:"pod"`
+	Reason       string                 `json:"reason,omitempty"`
+	Message      string                 `json:"message,omitempty"`
+	Conditions   []string               `json:"conditions,omitempty"` // Conditions that are not true
+	Terminations []ContainerTermination `json:"terminations,omitempty"`
+	Events       []string               `json:"events,omitempty"`
+	// LogContainer is the container Logs were read from, and PreviousLogs reports whether
+	// they came from its previous instance
+	LogContainer string `json:"logContainer,omitempty"`
+	PreviousLogs bool   `json:"previousLogs"`
+	Logs         string `json:"logs,omitempty"`
+}
+
+// JobDiagnosis bundles a Job's status with what its pods report about why it failed
+type JobDiagnosis struct {
+	Job          JobInfo           `json:"job"`
+	BackoffLimit *int32            `json:"backoffLimit,omitempty"`
+	Events       []string          `json:"events,omitempty"`
+	Pods         []JobPodDiagnosis `json:"pods"`
+}
+
+// JobPodDiagnosis describes one pod created by a Job
+type JobPodDiagnosis struct {
+	Name         string                 `json:"name"`
+	Phase        string                 `json:"phase"`
+	Reason       string                 `json:"reason,omitempty"`
+	Message      string                 `json:"message,omitempty"`
+	Terminations []ContainerTermination `json:"terminations,omitempty"`
+	Events       []string               `json:"events,omitempty"`
+	Logs         string                 `json:"logs,omitempty"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+// ContainerTermination is the most recent termination of a container
+type ContainerTermination struct {
+	Container string `json:"container"`
+	Reason    string `json:"reason"`
+	ExitCode  int32  `json:"exitCode"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ProbeGap reports a deployment container that lacks a readiness and/or liveness probe
+type ProbeGap struct {
+	Namespace        string `json:"namespace"`
+	Deployment       string `json:"deployment"`
+	Container        string `json:"container"`
+	MissingReadiness bool   `json:"missingReadiness"`
+	MissingLiveness  bool   `json:"missingLiveness"`
+}
+
+// ImageUsage describes one container image and every workload container that runs it
+type ImageUsage struct {
+	Image      string          `json:"image"`
+	Repository string          `json:"repository"`
+	Tag        string          `json:"tag,omitempty"`
+	Digest     string          `json:"digest,omitempty"`
+	Latest     bool            `json:"latest"`
+	Untagged   bool            `json:"untagged"`
+	Workloads  []ImageWorkload `json:"workloads"`
+}
+
+// ImageWorkload identifies a container in a workload that uses an image
+type ImageWorkload struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+}
+
+// PodUsage is a pod's current CPU and memory usage as reported by metrics-server, summed
+// over its containers
+type PodUsage struct {
+	Name          string           `json:"name"`
+	Namespace     string           `json:"namespace"`
+	CPUMillicores int64            `json:"cpuMillicores"`
+	MemoryBytes   int64            `json:"memoryBytes"`
+	CPU           string           `json:"cpu"`
+	Memory        string           `json:"memory"`
+	Containers    []ContainerUsage `json:"containers"`
+	Timestamp     time.Time        `json:"timestamp"`
+}
+
+// ContainerUsage is one container's share of a pod's usage
+type ContainerUsage struct {
+	Name          string `json:"name"`
+	CPUMillicores int64  `json:"cpuMillicores"`
+	MemoryBytes   int64  `json:"memoryBytes"`
+}
+
+// NodeUsage is a node's current CPU and memory usage. The percentages are of the node's
+// allocatable resources and are omitted when the node could not be read.
+type NodeUsage struct {
+	Name          string    `json:"name"`
+	CPUMillicores int64     `json:"cpuMillicores"`
+	MemoryBytes   int64     `json:"memoryBytes"`
+	CPU           string    `json:"cpu"`
+	Memory        string    `json:"memory"`
+	CPUPercent    *int64    `json:"cpuPercent,omitempty"`
+	MemoryPercent *int64    `json:"memoryPercent,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// ClusterInfo describes the connected cluster and the capabilities it offers. Extensions
+// maps each commonly installed add-on to whether its API group is served.
+type ClusterInfo struct {
+	ServerVersion string          `json:"serverVersion"`
+	Platform      string          `json:"platform"`
+	BuildDate     string          `json:"buildDate"`
+	NodeCount     int             `json:"nodeCount"`
+	ReadyNodes    int             `json:"readyNodes"`
+	Capacity      ResourceTotals  `json:"capacity"`
+	Allocatable   ResourceTotals  `json:"allocatable"`
+	APIGroups     []string        `json:"apiGroups"`
+	MetricsServer bool            `json:"metricsServer"`
+	Extensions    map[string]bool `json:"extensions"`
+	Warnings      []string        `json:"warnings,omitempty"`
+}
+
+// ResourceTotals sums CPU, memory and pod slots over the cluster's nodes
+type ResourceTotals struct {
+	CPUMillicores int64  `json:"cpuMillicores"`
+	MemoryBytes   int64  `json:"memoryBytes"`
+	Pods          int64  `json:"pods"`
+	CPU           string `json:"cpu"`
+	Memory        string `json:"memory"`
+}
+
+// DrainResult lists the pods a node drain evicted and the pods it left in place
+type DrainResult struct {
+	Node    string       `json:"node"`
+	Evicted []DrainedPod `json:"evicted"`
+	Skipped []DrainedPod `json:"skipped"`
+}
+
+// DrainedPod identifies a pod considered by a drain. Reason explains why a pod was skipped.
+type DrainedPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// PatchResult identifies a patched object and the version the patch produced
+type PatchResult struct {
+	Kind            string `json:"kind"`
+	Namespace       string `json:"namespace,omitempty"`
+	Name            string `json:"name"`
+	ResourceVersion string `json:"resourceVersion"`
+	Generation      int64  `json:"generation,omitempty"`
+}
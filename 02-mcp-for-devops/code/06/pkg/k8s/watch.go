@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ResourceChange identifies a watched object that was added, updated or deleted
+type ResourceChange struct {
+	Kind      string // "pod" or "deployment"
+	Namespace string
+	Name      string
+}
+
+// WatchChanges calls onChange whenever a pod or deployment in any namespace changes,
+// until ctx is done. It shares the informer cache's watches when the cache is enabled and
+// starts informers of its own otherwise. Objects that exist when the watch starts and
+// periodic resyncs, which change nothing, are not reported.
+func (c *Client) WatchChanges(ctx context.Context, onChange func(ResourceChange)) error {
+	factory := informers.NewSharedInformerFactory(c.clientset, 0)
+	if c.cache != nil {
+		factory = c.cache.factory
+	}
+
+	watched := map[string]cache.SharedIndexInformer{
+		"pod":        factory.Core().V1().Pods().Informer(),
+		"deployment": factory.Apps().V1().Deployments().Informer(),
+	}
+	for kind, informer := range watched {
+		if _, err := informer.AddEventHandler(changeHandler(kind, onChange)); err != nil {
+			return fmt.Errorf("failed to watch %ss: %w", kind, err)
+		}
+	}
+
+	// Informers the cache already runs keep running; only new ones are started
+	factory.Start(ctx.Done())
+	return nil
+}
+
+// changeHandler reports the key of each changed object to onChange
+func changeHandler(kind string, onChange func(ResourceChange)) cache.ResourceEventHandler {
+	report := func(obj interface{}) {
+		key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return
+		}
+		namespace, name, err := cache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			return
+		}
+		onChange(ResourceChange{Kind: kind, Namespace: namespace, Name: name})
+	}
+
+	return cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc: func(obj interface{}, isInInitialList bool) {
+			if !isInInitialList {
+				report(obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldMeta, oldOK := oldObj.(metav1.Object)
+			newMeta, newOK := newObj.(metav1.Object)
+			if oldOK && newOK && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+				return // a resync
+			}
+			report(newObj)
+		},
+		DeleteFunc: report,
+	}
+}
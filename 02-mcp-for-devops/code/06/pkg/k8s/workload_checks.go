@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FindMissingProbes reports, per container, deployments whose containers lack a readiness
+// or liveness probe. An empty namespace sweeps every namespace one page at a time; the
+// returned cursor resumes the sweep where this page stopped.
+func (c *Client) FindMissingProbes(ctx context.Context, namespace string, limit int, cursor string) (*SweepPage[ProbeGap], error) {
+	namespaces := []string{namespace}
+	if namespace == "" {
+		var err error
+		if namespaces, err = c.namespaceNames(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return SweepNamespaces(ctx, namespaces, limit, cursor, func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]ProbeGap, string, error) {
+		deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+		}
+
+		var gaps []ProbeGap
+		for _, deploy := range deployments.Items {
+			for _, container := range deploy.Spec.Template.Spec.Containers {
+				gap := ProbeGap{
+					Namespace:        deploy.Namespace,
+					Deployment:       deploy.Name,
+					Container:        container.Name,
+					MissingReadiness: container.ReadinessProbe == nil,
+					MissingLiveness:  container.LivenessProbe == nil,
+				}
+				if gap.MissingReadiness || gap.MissingLiveness {
+					gaps = append(gaps, gap)
+				}
+			}
+		}
+		return gaps, deployments.Continue, nil
+	})
+}
+
+// AuditImages lists every container image used by the deployments, statefulsets, and
+// daemonsets in a namespace, grouped by image and flagging ":latest" and untagged
+// references. Init containers are included. Images are sorted by usage, most used first.
+func (c *Client) AuditImages(ctx context.Context, namespace string) ([]ImageUsage, error) {
+	usages := make(map[string]*ImageUsage)
+	record := func(kind, name string, spec corev1.PodSpec) {
+		containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+		for _, container := range containers {
+			usage, ok := usages[container.Image]
+			if !ok {
+				usage = newImageUsage(container.Image)
+				usages[container.Image] = usage
+			}
+			usage.Workloads = append(usage.Workloads, ImageWorkload{
+				Kind:      kind,
+				Name:      name,
+				Container: container.Name,
+			})
+		}
+	}
+
+	deployments, err := c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments in namespace %s: %w", namespace, err)
+	}
+	for _, deploy := range deployments.Items {
+		record("Deployment", deploy.Name, deploy.Spec.Template.Spec)
+	}
+
+	statefulSets, err := c.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets in namespace %s: %w", namespace, err)
+	}
+	for _, sts := range statefulSets.Items {
+		record("StatefulSet", sts.Name, sts.Spec.Template.Spec)
+	}
+
+	daemonSets, err := c.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets in namespace %s: %w", namespace, err)
+	}
+	for _, ds := range daemonSets.Items {
+		record("DaemonSet", ds.Name, ds.Spec.Template.Spec)
+	}
+
+	result := make([]ImageUsage, 0, len(usages))
+	for _, usage := range usages {
+		result = append(result, *usage)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Workloads) != len(result[j].Workloads) {
+			return len(result[i].Workloads) > len(result[j].Workloads)
+		}
+		return result[i].Image < result[j].Image
+	})
+
+	return result, nil
+}
+
+// newImageUsage splits an image reference into repository, tag, and digest. A reference
+// with neither tag nor digest is untagged, which the runtime resolves to ":latest".
+func newImageUsage(image string) *ImageUsage {
+	usage := &ImageUsage{Image: image}
+
+	ref := image
+	if at := strings.Index(ref, "@"); at >= 0 {
+		usage.Digest = ref[at+1:]
+		ref = ref[:at]
+	}
+	// A colon after the last slash separates the tag; earlier colons belong to a registry port
+	if colon := strings.LastIndex(ref, ":"); colon > strings.LastIndex(ref, "/") {
+		usage.Tag = ref[colon+1:]
+		ref = ref[:colon]
+	}
+	usage.Repository = ref
+
+	usage.Latest = usage.Tag == "latest"
+	usage.Untagged = usage.Tag == "" && usage.Digest == ""
+	return usage
+}
@@ -0,0 +1,30 @@
+package k8s
+
+import "testing"
+
+func TestNewImageUsageParsesReferences(t *testing.T) {
+	tests := []struct {
+		image      string
+		repository string
+		tag        string
+		digest     string
+		latest     bool
+		untagged   bool
+	}{
+		{image: "nginx", repository: "nginx", untagged: true},
+		{image: "nginx:latest", repository: "nginx", tag: "latest", latest: true},
+		{image: "registry.local:5000/team/api:1.4.2", repository: "registry.local:5000/team/api", tag: "1.4.2"},
+		{image: "registry.local:5000/team/api", repository: "registry.local:5000/team/api", untagged: true},
+		{image: "ghcr.io/org/app@sha256:abc", repository: "ghcr.io/org/app", digest: "sha256:abc"},
+		{image: "ghcr.io/org/app:v2@sha256:abc", repository: "ghcr.io/org/app", tag: "v2", digest: "sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		got := newImageUsage(tt.image)
+		if got.Repository != tt.repository || got.Tag != tt.tag || got.Digest != tt.digest ||
+			got.Latest != tt.latest || got.Untagged != tt.untagged {
+			t.Errorf("%s: got repository=%q tag=%q digest=%q latest=%v untagged=%v", tt.image,
+				got.Repository, got.Tag, got.Digest, got.Latest, got.Untagged)
+		}
+	}
+}
@@ -3,8 +3,15 @@ package mcp
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"kubernetes-mcp-server/pkg/types"
 )
 
 // ResourceFormatter provides AI-friendly formatting for Kubernetes resources
@@ -14,8 +21,50 @@ func NewResourceFormatter() *ResourceFormatter {
 	return &ResourceFormatter{}
 }
 
+// Format renders a resource's JSON details in the requested format and returns the
+// content with its MIME type. Kinds without a markdown formatter are returned as JSON
+// when markdown is requested.
+func (f *ResourceFormatter) Format(kind, data string, format types.OutputFormat, verbosity types.Verbosity) (string, string, error) {
+	switch format {
+	case types.OutputFormatJSON:
+		return data, "application/json", nil
+	case types.OutputFormatYAML:
+		var object interface{}
+		if err := json.Unmarshal([]byte(data), &object); err != nil {
+			return "", "", err
+		}
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return "", "", err
+		}
+		return string(out), "application/yaml", nil
+	}
+
+	var formatMarkdown func(string, types.Verbosity) (string, error)
+	switch kind {
+	case "pod":
+		formatMarkdown = f.FormatPodForAI
+	case "service":
+		formatMarkdown = f.FormatServiceForAI
+	case "deployment":
+		formatMarkdown = f.FormatDeploymentForAI
+	case "configmap":
+		formatMarkdown = f.FormatConfigMapForAI
+	case "node":
+		formatMarkdown = f.FormatNodeForAI
+	default:
+		return data, "application/json", nil
+	}
+
+	content, err := formatMarkdown(data, verbosity)
+	if err != nil {
+		return "", "", err
+	}
+	return content, "text/markdown", nil
+}
+
 // FormatPodForAI creates an AI-optimized view of pod information
-func (f *ResourceFormatter) FormatPodForAI(podData string) (string, error) {
+func (f *ResourceFormatter) FormatPodForAI(podData string, verbosity types.Verbosity) (string, error) {
 	var pod map[string]interface{}
 	if err := json.Unmarshal([]byte(podData), &pod); err != nil {
 		return "", err
@@ -29,11 +78,50 @@ func (f *ResourceFormatter) FormatPodForAI(podData string) (string, error) {
 	summary.WriteString(fmt.Sprintf("**Namespace**: %s\n", pod["namespace"]))
 	summary.WriteString(fmt.Sprintf("**Status**: %s\n", pod["status"]))
 	summary.WriteString(fmt.Sprintf("**Node**: %s\n", pod["node"]))
+	if ownerKind := stringField(pod, "ownerKind", ""); ownerKind != "" {
+		summary.WriteString(fmt.Sprintf("**Owner**: %s %s\n", ownerKind, stringField(pod, "ownerName", "unknown")))
+	}
+	if qosClass := stringField(pod, "qosClass", ""); qosClass != "" {
+		summary.WriteString(fmt.Sprintf("**QoS Class**: %s\n", qosClass))
+	}
 
+	if crashLooping, _ := pod["crashLooping"].(bool); crashLooping {
+		summary.WriteString("**🔴 CrashLooping**: a container keeps crashing and restarting; check its previous logs\n")
+	}
 	if restarts, ok := pod["restarts"].(float64); ok && restarts > 0 {
 		summary.WriteString(fmt.Sprintf("**⚠️ Restarts**: %.0f\n", restarts))
 	}
 
+	// OOMKills are the first thing to look for in a crashing pod, so list them at every verbosity
+	if containers, ok := pod["containers"].([]interface{}); ok {
+		var oomKilled []string
+		for _, container := range containers {
+			if c, ok := container.(map[string]interface{}); ok {
+				if termination, oom := containerTermination(c); oom {
+					// "app (exit 137)"
+					detail := strings.TrimPrefix(termination, "OOMKilled")
+					oomKilled = append(oomKilled, stringField(c, "name", "unknown")+detail)
+				}
+			}
+		}
+		if len(oomKilled) > 0 {
+			summary.WriteString(fmt.Sprintf("**🔴 OOMKilled**: %s\n", strings.Join(oomKilled, ", ")))
+		}
+	}
+
+	if verbosity == types.VerbositySummary {
+		if containers, ok := pod["containers"].([]interface{}); ok {
+			ready := 0
+			for _, container := range containers {
+				if c, ok := container.(map[string]interface{}); ok && c["ready"] == true {
+					ready++
+				}
+			}
+			summary.WriteString(fmt.Sprintf("**Containers Ready**: %d/%d\n", ready, len(containers)))
+		}
+		return summary.String(), nil
+	}
+
 	// Creation time
 	if createdAt, ok := pod["createdAt"].(string); ok {
 		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
@@ -48,10 +136,11 @@ func (f *ResourceFormatter) FormatPodForAI(podData string) (string, error) {
 	if containers, ok := pod["containers"].([]interface{}); ok {
 		for _, container := range containers {
 			if c, ok := container.(map[string]interface{}); ok {
-				name := c["name"].(string)
-				image := c["image"].(string)
-				ready := c["ready"].(bool)
-				state := c["state"].(string)
+				// A pending pod may not report container statuses yet
+				name := stringField(c, "name", "unknown")
+				image := stringField(c, "image", "unknown")
+				ready, _ := c["ready"].(bool)
+				state := stringField(c, "state", "unknown")
 
 				status := "🟢 Ready"
 				if !ready {
@@ -61,6 +150,14 @@ func (f *ResourceFormatter) FormatPodForAI(podData string) (string, error) {
 				summary.WriteString(fmt.Sprintf("- **%s**: %s\n", name, status))
 				summary.WriteString(fmt.Sprintf("  - Image: `%s`\n", image))
 				summary.WriteString(fmt.Sprintf("  - State: %s\n", state))
+				if termination, oom := containerTermination(c); oom {
+					summary.WriteString(fmt.Sprintf("  - 🔴 %s\n", termination))
+				} else if termination != "" {
+					summary.WriteString(fmt.Sprintf("  - Last Termination: %s\n", termination))
+				}
+				// Missing limits matter as much as set ones when diagnosing OOMKills and throttling
+				summary.WriteString(fmt.Sprintf("  - Requests: %s\n", formatQuantities(c["requests"])))
+				summary.WriteString(fmt.Sprintf("  - Limits: %s\n", formatQuantities(c["limits"])))
 
 				if restarts, ok := c["restarts"].(float64); ok && restarts > 0 {
 					summary.WriteString(fmt.Sprintf("  - Restarts: %.0f\n", restarts))
@@ -80,8 +177,35 @@ func (f *ResourceFormatter) FormatPodForAI(podData string) (string, error) {
 	// Labels
 	if labels, ok := pod["labels"].(map[string]interface{}); ok && len(labels) > 0 {
 		summary.WriteString("\n## Labels\n\n")
-		for key, value := range labels {
-			summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, value))
+		for _, key := range sortedKeys(labels) {
+			summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, labels[key]))
+		}
+	}
+
+	// Events are only included at detailed verbosity
+	if verbosity == types.VerbosityDetailed {
+		if events, ok := pod["recentEvents"].([]interface{}); ok && len(events) > 0 {
+			summary.WriteString("\n## Recent Events\n\n")
+			for _, event := range events {
+				e, ok := event.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				icon := "ℹ️"
+				if e["type"] == "Warning" {
+					icon = "⚠️"
+				}
+				summary.WriteString(fmt.Sprintf("- %s **%s**: %s", icon, e["reason"], e["message"]))
+				if count, ok := e["count"].(float64); ok && count > 1 {
+					summary.WriteString(fmt.Sprintf(" (x%.0f)", count))
+				}
+				if lastSeen, ok := e["lastTimestamp"].(string); ok {
+					if t, err := time.Parse(time.RFC3339, lastSeen); err == nil && !t.IsZero() {
+						summary.WriteString(fmt.Sprintf(", %s ago", formatDuration(time.Since(t))))
+					}
+				}
+				summary.WriteString("\n")
+			}
 		}
 	}
 
@@ -92,7 +216,7 @@ func (f *ResourceFormatter) FormatPodForAI(podData string) (string, error) {
 }
 
 // FormatDeploymentForAI creates an AI-optimized view of deployment information
-func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string) (string, error) {
+func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string, verbosity types.Verbosity) (string, error) {
 	var deployment map[string]interface{}
 	if err := json.Unmarshal([]byte(deploymentData), &deployment); err != nil {
 		return "", err
@@ -106,10 +230,10 @@ func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string) (string
 	summary.WriteString(fmt.Sprintf("**Namespace**: %s\n", deployment["namespace"]))
 	summary.WriteString(fmt.Sprintf("**Strategy**: %s\n", deployment["strategy"]))
 
-	// Replica status
-	total := deployment["totalReplicas"].(float64)
-	ready := deployment["readyReplicas"].(float64)
-	updated := deployment["updatedReplicas"].(float64)
+	// Replica status; a missing count is treated as zero
+	total, _ := deployment["totalReplicas"].(float64)
+	ready, _ := deployment["readyReplicas"].(float64)
+	updated, _ := deployment["updatedReplicas"].(float64)
 
 	healthStatus := "🟢 Healthy"
 	if ready < total {
@@ -122,6 +246,10 @@ func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string) (string
 	summary.WriteString(fmt.Sprintf("**Status**: %s\n", healthStatus))
 	summary.WriteString(fmt.Sprintf("**Replicas**: %.0f desired, %.0f ready, %.0f updated\n", total, ready, updated))
 
+	if verbosity == types.VerbositySummary {
+		return summary.String(), nil
+	}
+
 	// Progress indicator
 	if total > 0 {
 		percentage := (ready / total) * 100
@@ -139,8 +267,8 @@ func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string) (string
 	// Selector
 	if selector, ok := deployment["selector"].(map[string]interface{}); ok && len(selector) > 0 {
 		summary.WriteString("\n## Selector\n\n")
-		for key, value := range selector {
-			summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, value))
+		for _, key := range sortedKeys(selector) {
+			summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, selector[key]))
 		}
 	}
 
@@ -152,6 +280,16 @@ func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string) (string
 		}
 	}
 
+	// Labels are only included at detailed verbosity
+	if verbosity == types.VerbosityDetailed {
+		if labels, ok := deployment["labels"].(map[string]interface{}); ok && len(labels) > 0 {
+			summary.WriteString("\n## Labels\n\n")
+			for _, key := range sortedKeys(labels) {
+				summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, labels[key]))
+			}
+		}
+	}
+
 	// Recommendations
 	summary.WriteString("\n## AI Assistant Notes\n\n")
 	if ready < total {
@@ -168,7 +306,7 @@ func (f *ResourceFormatter) FormatDeploymentForAI(deploymentData string) (string
 }
 
 // FormatServiceForAI creates an AI-optimized view of service information
-func (f *ResourceFormatter) FormatServiceForAI(serviceData string) (string, error) {
+func (f *ResourceFormatter) FormatServiceForAI(serviceData string, verbosity types.Verbosity) (string, error) {
 	var service map[string]interface{}
 	if err := json.Unmarshal([]byte(serviceData), &service); err != nil {
 		return "", err
@@ -183,6 +321,13 @@ func (f *ResourceFormatter) FormatServiceForAI(serviceData string) (string, erro
 	summary.WriteString(fmt.Sprintf("**Type**: %s\n", service["type"]))
 	summary.WriteString(fmt.Sprintf("**Cluster IP**: %s\n", service["clusterIP"]))
 
+	if verbosity == types.VerbositySummary {
+		if ports, ok := service["ports"].([]interface{}); ok {
+			summary.WriteString(fmt.Sprintf("**Ports**: %d\n", len(ports)))
+		}
+		return summary.String(), nil
+	}
+
 	// Port information
 	if ports, ok := service["ports"].([]interface{}); ok && len(ports) > 0 {
 		summary.WriteString("\n## Ports\n\n")
@@ -192,8 +337,9 @@ func (f *ResourceFormatter) FormatServiceForAI(serviceData string) (string, erro
 				if n, exists := p["name"].(string); exists && n != "" {
 					name = fmt.Sprintf(" (%s)", n)
 				}
-				summary.WriteString(fmt.Sprintf("- **Port %s%s**: %.0f → %s (%s)\n",
-					p["port"], name, p["port"], p["targetPort"], p["protocol"]))
+				port, _ := p["port"].(float64)
+				summary.WriteString(fmt.Sprintf("- **Port %.0f%s**: %.0f → %v (%s)\n",
+					port, name, port, p["targetPort"], stringField(p, "protocol", "TCP")))
 			}
 		}
 	}
@@ -202,13 +348,23 @@ func (f *ResourceFormatter) FormatServiceForAI(serviceData string) (string, erro
 	if selector, ok := service["selector"].(map[string]interface{}); ok && len(selector) > 0 {
 		summary.WriteString("\n## Selector\n\n")
 		summary.WriteString("This service routes traffic to pods with these labels:\n")
-		for key, value := range selector {
-			summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, value))
+		for _, key := range sortedKeys(selector) {
+			summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, selector[key]))
+		}
+	}
+
+	// Labels are only included at detailed verbosity
+	if verbosity == types.VerbosityDetailed {
+		if labels, ok := service["labels"].(map[string]interface{}); ok && len(labels) > 0 {
+			summary.WriteString("\n## Labels\n\n")
+			for _, key := range sortedKeys(labels) {
+				summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, labels[key]))
+			}
 		}
 	}
 
 	// Service type specific information
-	serviceType := service["type"].(string)
+	serviceType := stringField(service, "type", "unknown")
 	summary.WriteString("\n## Access Information\n\n")
 
 	switch serviceType {
@@ -225,6 +381,251 @@ func (f *ResourceFormatter) FormatServiceForAI(serviceData string) (string, erro
 	return summary.String(), nil
 }
 
+// maxConfigMapValueBytes is how much of each ConfigMap value is shown inline
+const maxConfigMapValueBytes = 2048
+
+// FormatConfigMapForAI creates an AI-optimized view of configmap information
+func (f *ResourceFormatter) FormatConfigMapForAI(configMapData string, verbosity types.Verbosity) (string, error) {
+	var configMap map[string]interface{}
+	if err := json.Unmarshal([]byte(configMapData), &configMap); err != nil {
+		return "", err
+	}
+
+	data, _ := configMap["data"].(map[string]interface{})
+	namespace := stringField(configMap, "namespace", "unknown")
+	name := stringField(configMap, "name", "unknown")
+
+	summary := &strings.Builder{}
+	summary.WriteString("# ConfigMap Summary\n\n")
+
+	// Basic information
+	summary.WriteString(fmt.Sprintf("**Name**: %s\n", name))
+	summary.WriteString(fmt.Sprintf("**Namespace**: %s\n", namespace))
+	if createdAt, ok := configMap["createdAt"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			summary.WriteString(fmt.Sprintf("**Age**: %s\n", formatDuration(time.Since(t))))
+		}
+	}
+	summary.WriteString(fmt.Sprintf("**Keys**: %d\n", len(data)))
+
+	if verbosity == types.VerbositySummary {
+		for _, key := range sortedKeys(data) {
+			value, _ := data[key].(string)
+			summary.WriteString(fmt.Sprintf("- `%s` (%d bytes)\n", key, len(value)))
+		}
+		return summary.String(), nil
+	}
+
+	// Data
+	if len(data) > 0 {
+		summary.WriteString("\n## Data\n\n")
+		for _, key := range sortedKeys(data) {
+			value, _ := data[key].(string)
+			fullValueURI := fmt.Sprintf("k8s://configmap/%s/%s?key=%s", namespace, name, url.QueryEscape(key))
+
+			if looksBinary(value) {
+				summary.WriteString(fmt.Sprintf("### `%s`\n\n⚠️ Binary-looking value (%d bytes), not shown. Read `%s` for the raw value.\n\n", key, len(value), fullValueURI))
+				continue
+			}
+
+			shown := value
+			truncated := len(value) > maxConfigMapValueBytes
+			if truncated {
+				// Cut on a rune boundary so the shown prefix stays valid UTF-8
+				cut := maxConfigMapValueBytes
+				for cut > 0 && !utf8.RuneStart(value[cut]) {
+					cut--
+				}
+				shown = value[:cut]
+			}
+
+			summary.WriteString(fmt.Sprintf("### `%s`\n\n```\n%s\n```\n", key, shown))
+			if truncated {
+				summary.WriteString(fmt.Sprintf("*Truncated to %d of %d bytes. Read `%s` for the full value.*\n", len(shown), len(value), fullValueURI))
+			}
+			summary.WriteString("\n")
+		}
+	}
+
+	// Labels are only included at detailed verbosity
+	if verbosity == types.VerbosityDetailed {
+		if labels, ok := configMap["labels"].(map[string]interface{}); ok && len(labels) > 0 {
+			summary.WriteString("\n## Labels\n\n")
+			for _, key := range sortedKeys(labels) {
+				summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, labels[key]))
+			}
+		}
+	}
+
+	return summary.String(), nil
+}
+
+// looksBinary reports whether a value is unlikely to be readable text: it is not valid
+// UTF-8 or it contains control characters other than common whitespace
+func looksBinary(value string) bool {
+	if !utf8.ValidString(value) {
+		return true
+	}
+	for _, r := range value {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' && r != '\f' {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatNodeForAI creates an AI-optimized view of node information
+func (f *ResourceFormatter) FormatNodeForAI(nodeData string, verbosity types.Verbosity) (string, error) {
+	var node map[string]interface{}
+	if err := json.Unmarshal([]byte(nodeData), &node); err != nil {
+		return "", err
+	}
+
+	conditions, _ := node["conditions"].(map[string]interface{})
+
+	summary := &strings.Builder{}
+	summary.WriteString("# Node Summary\n\n")
+
+	// Basic information
+	summary.WriteString(fmt.Sprintf("**Name**: %s\n", node["name"]))
+	if roles, ok := node["roles"].([]interface{}); ok && len(roles) > 0 {
+		names := make([]string, 0, len(roles))
+		for _, role := range roles {
+			names = append(names, fmt.Sprint(role))
+		}
+		summary.WriteString(fmt.Sprintf("**Roles**: %s\n", strings.Join(names, ", ")))
+	}
+
+	status := "🟢 Ready"
+	if conditions["Ready"] != "True" {
+		status = "🔴 Not Ready"
+	}
+	summary.WriteString(fmt.Sprintf("**Status**: %s\n", status))
+	if unschedulable, ok := node["unschedulable"].(bool); ok && unschedulable {
+		summary.WriteString("**⚠️ Scheduling**: Disabled (cordoned)\n")
+	}
+	summary.WriteString(fmt.Sprintf("**Kubelet**: %s\n", node["kubeletVersion"]))
+	summary.WriteString(fmt.Sprintf("**Allocatable**: %s CPU, %s memory\n", node["allocatableCPU"], node["allocatableMemory"]))
+
+	if verbosity == types.VerbositySummary {
+		return summary.String(), nil
+	}
+
+	summary.WriteString(fmt.Sprintf("**OS Image**: %s\n", node["osImage"]))
+	if ip, ok := node["internalIP"].(string); ok && ip != "" {
+		summary.WriteString(fmt.Sprintf("**Internal IP**: %s\n", ip))
+	}
+
+	// Creation time
+	if createdAt, ok := node["createdAt"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, createdAt); err == nil {
+			age := time.Since(t)
+			summary.WriteString(fmt.Sprintf("**Age**: %s\n", formatDuration(age)))
+		}
+	}
+
+	// Conditions; Ready should be True and the pressure conditions False
+	if len(conditions) > 0 {
+		summary.WriteString("\n## Conditions\n\n")
+		for _, conditionType := range []string{"Ready", "MemoryPressure", "DiskPressure", "PIDPressure", "NetworkUnavailable"} {
+			value, ok := conditions[conditionType]
+			if !ok {
+				continue
+			}
+			healthy := value == "False"
+			if conditionType == "Ready" {
+				healthy = value == "True"
+			}
+			icon := "🟢"
+			if !healthy {
+				icon = "🔴"
+			}
+			summary.WriteString(fmt.Sprintf("- %s **%s**: %s\n", icon, conditionType, value))
+		}
+	}
+
+	// Taints
+	if taints, ok := node["taints"].([]interface{}); ok && len(taints) > 0 {
+		summary.WriteString("\n## Taints\n\n")
+		for _, taint := range taints {
+			summary.WriteString(fmt.Sprintf("- `%s`\n", taint))
+		}
+	}
+
+	// Labels are only included at detailed verbosity
+	if verbosity == types.VerbosityDetailed {
+		if labels, ok := node["labels"].(map[string]interface{}); ok && len(labels) > 0 {
+			summary.WriteString("\n## Labels\n\n")
+			for _, key := range sortedKeys(labels) {
+				summary.WriteString(fmt.Sprintf("- `%s`: `%s`\n", key, labels[key]))
+			}
+		}
+	}
+
+	// Recommendations
+	if issues, ok := node["conditionIssues"].([]interface{}); ok && len(issues) > 0 {
+		summary.WriteString("\n## AI Assistant Notes\n\n")
+		for _, issue := range issues {
+			summary.WriteString(fmt.Sprintf("⚠️ **Unhealthy Condition**: %s\n", issue))
+		}
+	}
+
+	return summary.String(), nil
+}
+
+// sortedKeys returns the keys of m in ascending order, so rendered output is stable
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containerTermination describes a container's most recent termination, such as
+// "OOMKilled (exit 137)", and reports whether it was killed for exceeding its memory
+// limit. It returns "" for a container that has never terminated.
+func containerTermination(c map[string]interface{}) (string, bool) {
+	reason := stringField(c, "lastTerminationReason", "")
+	if state := stringField(c, "state", ""); strings.HasPrefix(state, "Terminated: ") {
+		reason = strings.TrimPrefix(state, "Terminated: ")
+	}
+	exitCode, hasExitCode := c["exitCode"].(float64)
+
+	switch {
+	case reason != "" && hasExitCode:
+		return fmt.Sprintf("%s (exit %.0f)", reason, exitCode), reason == "OOMKilled"
+	case reason != "":
+		return reason, reason == "OOMKilled"
+	case hasExitCode:
+		return fmt.Sprintf("exit %.0f", exitCode), false
+	}
+	return "", false
+}
+
+// formatQuantities renders a container's requests or limits as "cpu 250m, memory 512Mi",
+// or "none" when unset
+func formatQuantities(value interface{}) string {
+	quantities, ok := value.(map[string]interface{})
+	if !ok || len(quantities) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(quantities))
+	for _, name := range sortedKeys(quantities) {
+		parts = append(parts, fmt.Sprintf("%s %v", name, quantities[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// stringField returns m[key] as a string, or fallback when it is missing, null, or empty
+func stringField(m map[string]interface{}, key, fallback string) string {
+	if value, ok := m[key].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
 // Helper function to format duration in a human-readable way
 func formatDuration(d time.Duration) string {
 	if d < time.Minute {
@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"kubernetes-mcp-server/pkg/types"
+)
+
+func TestFormattersToleratePartialData(t *testing.T) {
+	formatter := NewResourceFormatter()
+
+	tests := []struct {
+		name   string
+		format func(string, types.Verbosity) (string, error)
+		data   string
+		want   string
+	}{
+		{
+			name:   "pending pod without container status",
+			format: formatter.FormatPodForAI,
+			data:   `{"name":"web","namespace":"default","status":"Pending","containers":[{"name":"app","image":"nginx"},{"state":null}]}`,
+			want:   "- **unknown**: 🔴 Not Ready",
+		},
+		{
+			name:   "deployment without replica counts",
+			format: formatter.FormatDeploymentForAI,
+			data:   `{"name":"web","namespace":"default","totalReplicas":null}`,
+			want:   "**Replicas**: 0 desired, 0 ready, 0 updated",
+		},
+		{
+			name:   "service without type",
+			format: formatter.FormatServiceForAI,
+			data:   `{"name":"web","namespace":"default","ports":[{"port":80,"targetPort":"8080"}]}`,
+			want:   "- **Port 80**: 80 → 8080 (TCP)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, verbosity := range []types.Verbosity{types.VerbositySummary, types.VerbosityNormal, types.VerbosityDetailed} {
+				got, err := tt.format(tt.data, verbosity)
+				if err != nil {
+					t.Fatalf("verbosity %s: unexpected error: %v", verbosity, err)
+				}
+				if verbosity != types.VerbositySummary && !strings.Contains(got, tt.want) {
+					t.Errorf("verbosity %s: output does not contain %q:\n%s", verbosity, tt.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatPodForAIShowsResources(t *testing.T) {
+	data := `{"name":"web","namespace":"default","status":"Running","qosClass":"Burstable","containers":[` +
+		`{"name":"app","image":"nginx","ready":true,"requests":{"memory":"128Mi","cpu":"100m"},"limits":{"memory":"256Mi"}},` +
+		`{"name":"sidecar","image":"envoy","ready":true}]}`
+
+	got, err := NewResourceFormatter().FormatPodForAI(data, types.VerbosityNormal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"**QoS Class**: Burstable",
+		"  - Requests: cpu 100m, memory 128Mi\n  - Limits: memory 256Mi",
+		"- **sidecar**: 🟢 Ready\n  - Image: `envoy`\n  - State: unknown\n  - Requests: none\n  - Limits: none",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatPodForAICallsOutOOMKills(t *testing.T) {
+	data := `{"name":"web","namespace":"default","status":"Running","restarts":3,"containers":[` +
+		`{"name":"app","image":"java","ready":false,"restarts":3,"state":"Waiting: CrashLoopBackOff","lastTerminationReason":"OOMKilled","exitCode":137},` +
+		`{"name":"migrate","image":"flyway","ready":false,"state":"Terminated: Error","exitCode":1},` +
+		`{"name":"proxy","image":"envoy","ready":true,"state":"Running"}]}`
+
+	for _, verbosity := range []types.Verbosity{types.VerbositySummary, types.VerbosityNormal} {
+		got, err := NewResourceFormatter().FormatPodForAI(data, verbosity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(got, "**🔴 OOMKilled**: app (exit 137)\n") {
+			t.Errorf("verbosity %s: OOMKill is not called out:\n%s", verbosity, got)
+		}
+	}
+
+	got, _ := NewResourceFormatter().FormatPodForAI(data, types.VerbosityNormal)
+	for _, want := range []string{
+		"  - State: Waiting: CrashLoopBackOff\n  - 🔴 OOMKilled (exit 137)\n",
+		"  - State: Terminated: Error\n  - Last Termination: Error (exit 1)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+	if !strings.Contains(got, "`envoy`\n  - State: Running\n  - Requests") {
+		t.Errorf("running container reported a termination:\n%s", got)
+	}
+}
+
+func TestFormatPodForAIWarnsOfCrashLoops(t *testing.T) {
+	looping, err := NewResourceFormatter().FormatPodForAI(`{"name":"web","status":"Running","restarts":12,"crashLooping":true}`, types.VerbositySummary)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(looping, "**🔴 CrashLooping**") {
+		t.Errorf("crash-looping pod has no warning:\n%s", looping)
+	}
+
+	stable, _ := NewResourceFormatter().FormatPodForAI(`{"name":"web","status":"Running","restarts":12,"crashLooping":false}`, types.VerbositySummary)
+	if strings.Contains(stable, "CrashLooping") {
+		t.Errorf("stable pod has a crash-loop warning:\n%s", stable)
+	}
+}
+
+func TestFormatConfigMapForAI(t *testing.T) {
+	long := strings.Repeat("é", maxConfigMapValueBytes) // two bytes per rune
+	data := `{"name":"app","namespace":"default","data":{"config.yaml":"port: 8080\n","blob":"\u0000\u0001PK","long":"` + long + `"}}`
+
+	got, err := NewResourceFormatter().FormatConfigMapForAI(data, types.VerbosityNormal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"**Keys**: 3",
+		"```\nport: 8080\n\n```",
+		"Binary-looking value (4 bytes), not shown. Read `k8s://configmap/default/app?key=blob`",
+		"*Truncated to 2048 of 4096 bytes.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "`blob`") > strings.Index(got, "`config.yaml`") {
+		t.Errorf("keys are not rendered in sorted order:\n%s", got)
+	}
+}
+
+func TestFormatSelectsOutputFormat(t *testing.T) {
+	formatter := NewResourceFormatter()
+	data := `{"name":"web","namespace":"default","type":"ClusterIP"}`
+
+	tests := []struct {
+		kind     string
+		format   types.OutputFormat
+		mimeType string
+		want     string
+	}{
+		{kind: "service", format: types.OutputFormatMarkdown, mimeType: "text/markdown", want: "# Service Summary"},
+		{kind: "service", format: types.OutputFormatJSON, mimeType: "application/json", want: data},
+		{kind: "service", format: types.OutputFormatYAML, mimeType: "application/yaml", want: "name: web\nnamespace: default\ntype: ClusterIP\n"},
+		{kind: "job", format: types.OutputFormatMarkdown, mimeType: "application/json", want: data},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind+"/"+string(tt.format), func(t *testing.T) {
+			got, mimeType, err := formatter.Format(tt.kind, data, tt.format, types.VerbosityNormal)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mimeType != tt.mimeType {
+				t.Errorf("mime type = %q, want %q", mimeType, tt.mimeType)
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("output does not contain %q:\n%s", tt.want, got)
+			}
+		})
+	}
+}
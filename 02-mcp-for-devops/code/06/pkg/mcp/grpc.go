@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"kubernetes-mcp-server/internal/tracing"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+// The gRPC transport exposes a single unary RPC, mcp.v1.ToolService/CallTool. Requests and
+// responses are google.protobuf.Struct messages, so clients need no generated stubs:
+//
+//	request:  {"name": "k8s_list_pods", "arguments": {"namespace": "default"}}
+//	response: the tool result data
+//
+// api/mcp/v1/tool_service.proto describes the service for clients. Credentials are read
+// from the "authorization" metadata key, in the same "Bearer <jwt>" or "ApiKey <key>"
+// form as the HTTP Authorization header.
+const grpcServiceName = "mcp.v1.ToolService"
+
+// toolServiceServer is the handler type registered for the gRPC tool service
+type toolServiceServer interface {
+	CallTool(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error)
+}
+
+var toolServiceDesc = grpc.ServiceDesc{
+	ServiceName: grpcServiceName,
+	HandlerType: (*toolServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CallTool",
+			Handler:    callToolHandler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "mcp/v1/tool_service.proto",
+}
+
+// GRPCToolService routes gRPC tool calls through SecureMCPServer.HandleToolCall so they get
+// the same authentication, authorization, and audit logging as every other transport
+type GRPCToolService struct {
+	server *SecureMCPServer
+	logger *logrus.Logger
+}
+
+// NewGRPCServer creates a gRPC server with the tool service registered. Transport security
+// such as mTLS is supplied by the caller through opts.
+func NewGRPCServer(secureServer *SecureMCPServer, logger *logrus.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.UnaryInterceptor(authMetadataInterceptor))
+	grpcServer := grpc.NewServer(opts...)
+	grpcServer.RegisterService(&toolServiceDesc, &GRPCToolService{
+		server: secureServer,
+		logger: logger,
+	})
+	return grpcServer
+}
+
+// CallTool executes the named tool with the given arguments
+func (g *GRPCToolService) CallTool(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	fields := request.GetFields()
+	toolName := fields["name"].GetStringValue()
+	if toolName == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing tool name")
+	}
+
+	arguments := map[string]interface{}{}
+	if args := fields["arguments"].GetStructValue(); args != nil {
+		arguments = args.AsMap()
+	}
+
+	result, err := g.server.HandleToolCall(ctx, toolName, arguments)
+	if err != nil {
+		return nil, status.Error(grpcCode(err), err.Error())
+	}
+
+	response, err := toStruct(result)
+	if err != nil {
+		g.logger.WithError(err).WithField("tool", toolName).Error("Failed to encode gRPC tool result")
+		return nil, status.Error(codes.Internal, "failed to encode tool result")
+	}
+	return response, nil
+}
+
+func callToolHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	request := new(structpb.Struct)
+	if err := dec(request); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(toolServiceServer).CallTool(ctx, request)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/" + grpcServiceName + "/CallTool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(toolServiceServer).CallTool(ctx, req.(*structpb.Struct))
+	}
+	return interceptor(ctx, request, info, handler)
+}
+
+// authMetadataInterceptor copies the per-RPC authorization metadata into the headers the
+// security middleware authenticates, so a missing credential is rejected rather than
+// falling back to a default identity
+func authMetadataInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	headers := map[string]string{"Authorization": ""}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			headers["Authorization"] = values[0]
+		}
+		// Continue the caller's trace, if it sent one
+		traceHeaders := http.Header{}
+		for _, key := range []string{"traceparent", "tracestate", "baggage"} {
+			if values := md.Get(key); len(values) > 0 {
+				traceHeaders.Set(key, values[0])
+			}
+		}
+		ctx = tracing.Extract(ctx, traceHeaders)
+	}
+	return handler(context.WithValue(ctx, HeadersContextKey, headers), req)
+}
+
+// grpcCode maps the error code of a HandleToolCall error to a gRPC status code
+func grpcCode(err error) codes.Code {
+	switch types.ErrorCode(err) {
+	case types.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+	case types.ErrorCodeForbidden:
+		return codes.PermissionDenied
+	case types.ErrorCodeInvalidParams, types.ErrorCodeInvalidRequest:
+		return codes.InvalidArgument
+	case types.ErrorCodeResourceNotFound:
+		return codes.NotFound
+	case types.ErrorCodeMethodNotFound:
+		return codes.Unimplemented
+	case types.ErrorCodeTimeout:
+		return codes.DeadlineExceeded
+	case types.ErrorCodeClusterUnavailable:
+		return codes.Unavailable
+	case types.ErrorCodeRateLimited:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// toStruct converts tool result data to a Struct, normalizing values such as
+// timestamps and typed slices through their JSON encoding
+func toStruct(data map[string]interface{}) (*structpb.Struct, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(normalized)
+}
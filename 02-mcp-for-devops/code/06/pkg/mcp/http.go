@@ -0,0 +1,223 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/internal/tracing"
+	"kubernetes-mcp-server/pkg/k8s"
+	"kubernetes-mcp-server/pkg/security"
+	"kubernetes-mcp-server/pkg/tools"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+// The HTTP transports serve the MCP protocol itself, with the same tools and resources as
+// stdio, so remote clients can share one long-lived server. "http" is the streamable HTTP
+// transport at /mcp; "sse" is the older SSE transport at /sse and /message. Credentials
+// are read from the Authorization header in the "Bearer <jwt>" or "ApiKey <key>" form.
+
+// httpShutdownTimeout bounds how long open requests get to finish when the server stops
+const httpShutdownTimeout = 10 * time.Second
+
+// httpTransport is the lifecycle shared by the mcp-go HTTP servers
+type httpTransport interface {
+	Start(addr string) error
+	Shutdown(ctx context.Context) error
+}
+
+// StartHTTP serves MCP over the streamable HTTP transport on addr until ctx is cancelled
+func (s *Server) StartHTTP(ctx context.Context, addr string) error {
+	// Route /mcp ourselves so resource subscriptions are answered before mcp-go sees them
+	mux := http.NewServeMux()
+	transport := server.NewStreamableHTTPServer(s.mcpServer,
+		server.WithHTTPContextFunc(withRequestHeaders),
+		server.WithStreamableHTTPServer(&http.Server{Addr: addr, Handler: s.corsPolicy().Handler(mux)}),
+	)
+	mux.Handle("/mcp", s.subscriptionMiddleware(transport))
+	return s.serveHTTP(ctx, addr, "streamable HTTP", transport)
+}
+
+// StartSSE serves MCP over the SSE transport on addr until ctx is cancelled
+func (s *Server) StartSSE(ctx context.Context, addr string) error {
+	httpServer := &http.Server{Addr: addr}
+	transport := server.NewSSEServer(s.mcpServer,
+		server.WithSSEContextFunc(withRequestHeaders),
+		server.WithHTTPServer(httpServer),
+	)
+	httpServer.Handler = s.corsPolicy().Handler(transport)
+	return s.serveHTTP(ctx, addr, "SSE", transport)
+}
+
+// corsPolicy is the configured CORS policy of the HTTP transports
+func (s *Server) corsPolicy() security.CORSPolicy {
+	return security.CORSPolicy(s.config.Server.CORS)
+}
+
+// serveHTTP runs transport until it fails or ctx is cancelled, then shuts it down gracefully
+func (s *Server) serveHTTP(ctx context.Context, addr, name string, transport httpTransport) error {
+	s.logger.Infof("Starting Kubernetes MCP Server with %s transport on %s", name, addr)
+
+	// Tool operations outlive ctx, so calls in flight at shutdown can finish
+	s.ctx = context.WithoutCancel(ctx)
+	s.watchCtx = ctx
+	go s.logResourceCacheStatsEvery(ctx, s.config.Server.ResourceCache.StatsInterval)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- transport.Start(addr)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		s.logger.Errorf("MCP server error: %v", err)
+		return fmt.Errorf("MCP server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := transport.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("MCP server shutdown failed: %w", err)
+	}
+
+	s.logResourceCacheStats()
+	s.logger.Info("MCP Server stopped")
+	return nil
+}
+
+// withRequestHeaders passes the request's credentials to the handlers. The header is set
+// even when empty, so a missing credential is rejected rather than falling back to a
+// default identity.
+func withRequestHeaders(ctx context.Context, r *http.Request) context.Context {
+	// Continue the caller's trace, if it sent one
+	ctx = tracing.Extract(ctx, r.Header)
+	return context.WithValue(ctx, HeadersContextKey, map[string]string{
+		"Authorization": r.Header.Get("Authorization"),
+	})
+}
+
+// StartHTTP serves MCP over streamable HTTP, authenticating and authorizing every tool
+// call and resource read
+func (s *SecureMCPServer) StartHTTP(ctx context.Context, addr string) error {
+	s.secureHandlers()
+	return s.Server.StartHTTP(ctx, addr)
+}
+
+// StartSSE serves MCP over SSE, authenticating and authorizing every tool call and
+// resource read
+func (s *SecureMCPServer) StartSSE(ctx context.Context, addr string) error {
+	s.secureHandlers()
+	return s.Server.StartSSE(ctx, addr)
+}
+
+// secureHandlers replaces the tool and resource handlers with ones that check the caller
+// first. Stdio keeps the unchecked handlers, since its client is the local user.
+func (s *SecureMCPServer) secureHandlers() {
+	s.registerTools(s.handleSecureToolCall)
+	s.registerResources(s.handleSecureResourceRead)
+	s.authorizeSubscription = s.authorizeSecureSubscription
+	s.authorizeOperation = s.authorizeSecureOperation
+}
+
+// handleSecureToolCall authorizes an MCP tool call, then runs it like stdio does
+func (s *SecureMCPServer) handleSecureToolCall(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	startTime := time.Now()
+	toolName := request.Params.Name
+	requestID := logging.NewRequestID()
+	ctx = logging.WithRequestID(ctx, requestID)
+	ctx, span := tracer.Start(ctx, "HandleToolCall", trace.WithAttributes(
+		attribute.String("mcp.tool", toolName),
+		attribute.String("mcp.request_id", requestID),
+	))
+
+	arguments, _ := request.Params.Arguments.(map[string]interface{})
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+		request.Params.Arguments = arguments
+	}
+
+	authInfo, _, err := s.authorizeToolCall(ctx, startTime, toolName, arguments)
+	if err != nil {
+		tracing.End(span, err)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Type: "text",
+					Text: formatToolError(&tools.ExecuteResult{
+						Message:   "Tool call rejected",
+						Error:     err.Error(),
+						Code:      types.ErrorCode(err),
+						Timestamp: time.Now(),
+					}),
+				},
+			},
+		}, err
+	}
+	resource, namespace := parseToolArguments(toolName, arguments)
+
+	result, err := s.Server.handleToolCall(context.WithValue(ctx, AuthInfoContextKey, authInfo), request)
+	s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, err)
+	tracing.End(span, err)
+	return result, err
+}
+
+// handleSecureResourceRead authorizes a resource read as a list of that resource type,
+// then reads it like stdio does
+func (s *SecureMCPServer) handleSecureResourceRead(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	startTime := time.Now()
+
+	authInfo, err := s.security.AuthenticateRequest(ctx, extractHeadersFromContext(ctx))
+	if err != nil {
+		s.logger.WithError(err).Warn("Authentication failed")
+		return nil, types.NewError(types.ErrorCodeUnauthorized, "authentication failed: %v", err)
+	}
+
+	resourceType, namespace, _, _, err := parseResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+	resource := resourceType + "s"
+	if namespace == "" {
+		namespace = "*" // cluster-scoped
+	}
+
+	err = s.security.AuthorizeRequest(ctx, authInfo, "list", resource, namespace)
+	s.security.LogRequest(ctx, authInfo, "read_resource", resource, namespace, startTime, err)
+	if err != nil {
+		return nil, types.NewError(types.ErrorCodeForbidden, "access denied: %v", err)
+	}
+
+	return s.Server.handleResourceRead(context.WithValue(ctx, AuthInfoContextKey, authInfo), request)
+}
+
+// authorizeSecureSubscription authorizes a resource subscription like a read of the
+// resource, since each notification invites the client to read it again
+func (s *SecureMCPServer) authorizeSecureSubscription(ctx context.Context, object k8s.ResourceChange) error {
+	startTime := time.Now()
+
+	authInfo, err := s.security.AuthenticateRequest(ctx, extractHeadersFromContext(ctx))
+	if err != nil {
+		s.logger.WithError(err).Warn("Authentication failed")
+		return types.NewError(types.ErrorCodeUnauthorized, "authentication failed: %v", err)
+	}
+
+	resource := object.Kind + "s"
+	err = s.security.AuthorizeRequest(ctx, authInfo, "list", resource, object.Namespace)
+	s.security.LogRequest(ctx, authInfo, "subscribe_resource", resource, object.Namespace, startTime, err)
+	if err != nil {
+		return types.NewError(types.ErrorCodeForbidden, "access denied: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"kubernetes-mcp-server/pkg/tools"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+// inferNamespace fills in a missing namespace argument when the named resource exists in
+// exactly one permitted namespace. It returns the inferred namespace, or "" when inference
+// is disabled or does not apply to this call. permitted narrows the configured namespace
+// allow-list to those the caller may use; nil means the whole allow-list.
+func (s *Server) inferNamespace(ctx context.Context, toolName string, arguments map[string]interface{}, permitted func([]string) []string) (string, error) {
+	if !s.config.K8s.InferNamespace || tools.IsClusterScoped(toolName) {
+		return "", nil
+	}
+	if ns, _ := arguments["namespace"].(string); ns != "" {
+		return "", nil
+	}
+	name, _ := arguments["name"].(string)
+	if name == "" {
+		return "", nil
+	}
+
+	resource, _ := parseToolArguments(toolName, arguments)
+	candidates := s.config.K8s.Namespaces
+	if len(candidates) == 0 && permitted != nil {
+		// Without an allow-list, narrow every namespace to those the caller may use
+		namespaces, err := s.k8sClient.ListNamespaces(ctx)
+		if err != nil {
+			return "", fmt.Errorf("cannot infer namespace for %s %q: %w", resource, name, err)
+		}
+		for _, namespace := range namespaces {
+			candidates = append(candidates, namespace.Name)
+		}
+	}
+	if permitted != nil {
+		candidates = permitted(candidates)
+		if len(candidates) == 0 {
+			return "", fmt.Errorf("cannot infer namespace for %s %q: no permitted namespaces", resource, name)
+		}
+	}
+
+	matches, err := s.k8sClient.FindResourceNamespaces(ctx, resource, name, candidates)
+	if err != nil {
+		return "", fmt.Errorf("cannot infer namespace for %s %q: %w", resource, name, err)
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("cannot infer namespace: no %s named %q found in namespaces %s", resource, name, strings.Join(candidates, ", "))
+	case 1:
+		arguments["namespace"] = matches[0]
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("namespace is ambiguous: %s %q exists in namespaces %s; specify one", resource, name, strings.Join(matches, ", "))
+	}
+}
+
+// kindNamespace returns the namespace to authorize a call against for tools that address
+// an object of any kind: namespace for namespaced kinds, and "*" for cluster-scoped ones.
+// A namespace given for a cluster-scoped kind is refused, so a grant in one namespace
+// cannot reach nodes, namespaces or cluster roles.
+func (s *Server) kindNamespace(arguments map[string]interface{}, namespace string) (string, error) {
+	kind, _ := arguments["kind"].(string)
+	if kind == "" {
+		// Input validation rejects the call
+		return namespace, nil
+	}
+	clusterScoped, err := s.clusterScopedKind(kind)
+	if err != nil {
+		return "", err
+	}
+	if !clusterScoped {
+		return namespace, nil
+	}
+	if ns, _ := arguments["namespace"].(string); ns != "" {
+		return "", fmt.Errorf("%s is cluster-scoped; omit the namespace", kind)
+	}
+	return "*", nil
+}
+
+// checkNamespaceAllowed rejects a namespace outside the configured allow-list. Empty and
+// "*" namespaces, which address cluster-scoped objects or sweeps, are left to the tools.
+func (s *Server) checkNamespaceAllowed(namespace string) error {
+	if namespace == "" || namespace == "*" || s.config == nil || len(s.config.K8s.Namespaces) == 0 {
+		return nil
+	}
+	if !slices.Contains(s.config.K8s.Namespaces, namespace) {
+		return types.NewError(types.ErrorCodeForbidden, "namespace %q is not allowed; this server is restricted to namespaces %s",
+			namespace, strings.Join(s.config.K8s.Namespaces, ", "))
+	}
+	return nil
+}
+
+// noteInferredNamespace records an inferred namespace in a tool result so the caller can see it
+func noteInferredNamespace(result *tools.ExecuteResult, namespace string) {
+	if namespace == "" {
+		return
+	}
+	result.Message = fmt.Sprintf("%s (namespace %q inferred from the resource name)", result.Message, namespace)
+	if result.Data == nil {
+		result.Data = map[string]interface{}{}
+	}
+	result.Data["inferredNamespace"] = namespace
+}
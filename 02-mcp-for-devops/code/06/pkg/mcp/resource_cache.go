@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resourceCache keeps recent resource reads for a short TTL, so a client reading the same
+// object several times while reasoning about it does not reach the API server each time.
+// Least recently used entries are evicted once it holds maxEntries.
+type resourceCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used first
+	stats   ResourceCacheStats
+}
+
+// ResourceCacheStats counts resource reads served from and missing the resource cache
+type ResourceCacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	// Bypassed counts reads that asked for fresh content
+	Bypassed  uint64 `json:"bypassed"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+type resourceCacheEntry struct {
+	key      string
+	contents []mcp.ResourceContents
+	expires  time.Time
+}
+
+// newResourceCache returns a cache keeping reads for ttl, or nil, which caches nothing,
+// if ttl or maxEntries is not positive
+func newResourceCache(ttl time.Duration, maxEntries int) *resourceCache {
+	if ttl <= 0 || maxEntries <= 0 {
+		return nil
+	}
+	return &resourceCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// get returns the unexpired contents cached under key
+func (c *resourceCache) get(key string) ([]mcp.ResourceContents, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok {
+		entry := element.Value.(*resourceCacheEntry)
+		if c.now().Before(entry.expires) {
+			c.order.MoveToFront(element)
+			c.stats.Hits++
+			return entry.contents, true
+		}
+		c.remove(element)
+	}
+	c.stats.Misses++
+	return nil, false
+}
+
+// put caches contents under key for the TTL, evicting the least recently used entry if
+// the cache is full
+func (c *resourceCache) put(key string, contents []mcp.ResourceContents) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		entry := element.Value.(*resourceCacheEntry)
+		entry.contents, entry.expires = contents, expires
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&resourceCacheEntry{key: key, contents: contents, expires: expires})
+	if c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// invalidate drops the cached reads of the object at path, whatever their query
+func (c *resourceCache) invalidate(path string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, element := range c.entries {
+		if strings.HasPrefix(key, path+"?") {
+			c.remove(element)
+		}
+	}
+}
+
+// bypassed counts a read that skipped the cache for fresh content
+func (c *resourceCache) bypassed() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.stats.Bypassed++
+	c.mu.Unlock()
+}
+
+// snapshot returns the cache's counters so far
+func (c *resourceCache) snapshot() ResourceCacheStats {
+	if c == nil {
+		return ResourceCacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+func (c *resourceCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*resourceCacheEntry).key)
+}
+
+// withURI returns copies of contents carrying uri, so a cached read echoes the URI it
+// was requested with
+func withURI(contents []mcp.ResourceContents, uri string) []mcp.ResourceContents {
+	copied := make([]mcp.ResourceContents, len(contents))
+	for i, content := range contents {
+		switch c := content.(type) {
+		case *mcp.TextResourceContents:
+			text := *c
+			text.URI = uri
+			copied[i] = &text
+		case *mcp.BlobResourceContents:
+			blob := *c
+			blob.URI = uri
+			copied[i] = &blob
+		default:
+			copied[i] = content
+		}
+	}
+	return copied
+}
@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+
+	"kubernetes-mcp-server/internal/logging"
+)
+
+func TestResourceCacheExpiresAndEvicts(t *testing.T) {
+	now := time.Now()
+	cache := newResourceCache(5*time.Second, 2)
+	cache.now = func() time.Time { return now }
+	contents := func(uri string) []mcp.ResourceContents {
+		return []mcp.ResourceContents{&mcp.TextResourceContents{URI: uri, Text: uri}}
+	}
+
+	cache.put("web", contents("k8s://pod/default/web"))
+	cache.put("db", contents("k8s://pod/default/db"))
+	if _, ok := cache.get("web"); !ok {
+		t.Fatal("fresh entry missed")
+	}
+
+	// db is now the least recently used
+	cache.put("api", contents("k8s://pod/default/api"))
+	if _, ok := cache.get("db"); ok {
+		t.Fatal("least recently used entry survived eviction")
+	}
+	if _, ok := cache.get("web"); !ok {
+		t.Fatal("recently used entry was evicted")
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, ok := cache.get("web"); ok {
+		t.Fatal("expired entry served")
+	}
+
+	stats := cache.snapshot()
+	want := ResourceCacheStats{Hits: 2, Misses: 2, Evictions: 1, Entries: 1}
+	if stats != want {
+		t.Fatalf("got stats %+v, want %+v", stats, want)
+	}
+}
+
+func TestResourceCacheInvalidate(t *testing.T) {
+	cache := newResourceCache(time.Minute, 10)
+	cache.put("k8s://pod/default/web?", nil)
+	cache.put("k8s://pod/default/web?format=yaml", nil)
+	cache.put("k8s://pod/default/web-2?", nil)
+
+	cache.invalidate("k8s://pod/default/web")
+	if _, ok := cache.get("k8s://pod/default/web?format=yaml"); ok {
+		t.Fatal("invalidated read served")
+	}
+	if _, ok := cache.get("k8s://pod/default/web-2?"); !ok {
+		t.Fatal("read of another pod was invalidated")
+	}
+}
+
+func TestCachedContentsEchoRequestedURI(t *testing.T) {
+	cached := []mcp.ResourceContents{&mcp.TextResourceContents{URI: "k8s://pod/default/web?format=yaml&verbosity=summary", Text: "web"}}
+
+	served := withURI(cached, "k8s://pod/default/web?verbosity=summary&format=yaml")
+	if got := served[0].(*mcp.TextResourceContents).URI; got != "k8s://pod/default/web?verbosity=summary&format=yaml" {
+		t.Fatalf("got URI %q", got)
+	}
+	if got := cached[0].(*mcp.TextResourceContents).URI; got != "k8s://pod/default/web?format=yaml&verbosity=summary" {
+		t.Fatalf("cached entry changed to %q", got)
+	}
+}
+
+func TestDisabledResourceCache(t *testing.T) {
+	cache := newResourceCache(0, 100)
+	cache.put("web", nil)
+	if _, ok := cache.get("web"); ok {
+		t.Fatal("disabled cache served an entry")
+	}
+	if stats := cache.snapshot(); stats != (ResourceCacheStats{}) {
+		t.Fatalf("disabled cache counted %+v", stats)
+	}
+}
+
+func TestResourceCacheStatsAreLoggedWhileRunning(t *testing.T) {
+	logger := logging.NewLogger("info", "text")
+	logger.SetOutput(io.Discard)
+	hook := logtest.NewLocal(logger.Logger)
+	s := &Server{logger: logger, resourceCache: newResourceCache(time.Minute, 10)}
+	s.resourceCache.get("web")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.logResourceCacheStatsEvery(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for hook.LastEntry() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("no stats were logged while the server ran")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if entry := hook.LastEntry(); entry.Message != "Resource cache stats" || entry.Data["misses"] != uint64(1) {
+		t.Errorf("logged %q with %v, want the stats with one miss", entry.Message, entry.Data)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stats logging did not stop with its context")
+	}
+}
@@ -2,103 +2,171 @@ package mcp
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"kubernetes-mcp-server/pkg/k8s"
 	"kubernetes-mcp-server/pkg/types"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
 )
 
-// registerResources discovers and registers actual Kubernetes resources
-func (s *Server) registerResources() {
-	// For now, we'll register a few sample resources from common namespaces
-	// In a production implementation, this could be made more dynamic
+// readableResourceTypes lists the resource types handleResourceRead serves, with the
+// display name used in their resource template
+var readableResourceTypes = []struct {
+	resourceType string
+	displayName  string
+}{
+	{"pod", "Pod"},
+	{"service", "Service"},
+	{"deployment", "Deployment"},
+	{"configmap", "ConfigMap"},
+	{"namespace", "Namespace"},
+	{"statefulset", "StatefulSet"},
+	{"daemonset", "DaemonSet"},
+	{"node", "Node"},
+	{"persistentvolumeclaim", "PersistentVolumeClaim"},
+	{"persistentvolume", "PersistentVolume"},
+	{"job", "Job"},
+	{"cronjob", "CronJob"},
+	{"horizontalpodautoscaler", "HorizontalPodAutoscaler"},
+}
 
-	ctx := context.Background()
+// registerResources registers a URI template for each readable resource type, so any
+// object in the cluster can be read without being listed up front
+func (s *Server) registerResources(handler server.ResourceTemplateHandlerFunc) {
+	for _, readable := range readableResourceTypes {
+		path := "{namespace}/{name}"
+		description := fmt.Sprintf("Kubernetes %s by namespace and name", readable.displayName)
+		if clusterScopedResourceTypes[readable.resourceType] {
+			// Cluster-scoped types have no namespace segment
+			path = "{name}"
+			description = fmt.Sprintf("Kubernetes %s by name", readable.displayName)
+		}
+		description += ". verbosity is one of summary, normal, detailed; format is one of markdown (default), json, yaml; fresh=true skips the short-lived read cache"
 
-	// Get some actual pods and register them
-	pods, err := s.k8sClient.ListPods(ctx, "")
-	if err != nil {
-		s.logger.Errorf("Failed to list pods for registration: %v", err)
-	} else {
-		// Register first few pods as examples (limit to avoid too many resources)
-		count := 0
-		for _, pod := range pods {
-			if count >= 5 { // Limit to first 5 pods
-				break
-			}
-			resource := mcp.Resource{
-				URI:         fmt.Sprintf("k8s://pod/%s/%s", pod.Namespace, pod.Name),
-				Name:        fmt.Sprintf("Pod: %s/%s", pod.Namespace, pod.Name),
-				Description: fmt.Sprintf("Kubernetes Pod in namespace %s (Status: %s)", pod.Namespace, pod.Status),
-				MIMEType:    "application/json",
-			}
-			s.mcpServer.AddResource(resource, s.handleResourceRead)
-			count++
+		query := "{?verbosity,format,fresh}"
+		if readable.resourceType == "configmap" {
+			// A single ConfigMap key is returned as-is, binaryData keys as base64 blobs
+			query = "{?verbosity,format,fresh,key}"
+			description += "; key returns the raw value of one key"
 		}
+
+		template := mcp.NewResourceTemplate(
+			fmt.Sprintf("k8s://%s/%s%s", readable.resourceType, path, query),
+			readable.displayName,
+			mcp.WithTemplateDescription(description),
+		)
+		s.mcpServer.AddResourceTemplate(template, handler)
 	}
+}
+
+// clusterScopedResourceTypes are addressed as k8s://<resource-type>/<name>
+var clusterScopedResourceTypes = map[string]bool{
+	"namespace":        true,
+	"node":             true,
+	"persistentvolume": true,
+}
+
+// handleResourceRead handles resource read requests. Reads are served from the resource
+// cache for a few seconds; fresh=true skips both it and the informer cache.
+func (s *Server) handleResourceRead(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	s.logger.Infof("Handling read_resource request for URI: %s", uri)
 
-	// Get some actual services and register them
-	services, err := s.k8sClient.ListServices(ctx, "")
+	resourceType, namespace, name, query, err := parseResourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNamespaceAllowed(namespace); err != nil {
+		return nil, err
+	}
+	verbosity, err := types.ParseVerbosity(query.Get("verbosity"))
+	if err != nil {
+		return nil, err
+	}
+	format, err := types.ParseOutputFormat(query.Get("format"))
 	if err != nil {
-		s.logger.Errorf("Failed to list services for registration: %v", err)
-	} else {
-		// Register first few services as examples
-		count := 0
-		for _, service := range services {
-			if count >= 5 { // Limit to first 5 services
-				break
-			}
-			resource := mcp.Resource{
-				URI:         fmt.Sprintf("k8s://service/%s/%s", service.Namespace, service.Name),
-				Name:        fmt.Sprintf("Service: %s/%s", service.Namespace, service.Name),
-				Description: fmt.Sprintf("Kubernetes Service in namespace %s (Type: %s)", service.Namespace, service.Type),
-				MIMEType:    "application/json",
-			}
-			s.mcpServer.AddResource(resource, s.handleResourceRead)
-			count++
+		return nil, err
+	}
+	fresh := false
+	if query.Has("fresh") {
+		if fresh, err = strconv.ParseBool(query.Get("fresh")); err != nil {
+			return nil, fmt.Errorf("invalid fresh value %q: must be true or false", query.Get("fresh"))
 		}
+		query.Del("fresh")
+	}
+
+	// Reads differing only in fresh or the order of their query share an entry
+	path, _, _ := strings.Cut(uri, "?")
+	key := path + "?" + query.Encode()
+	if fresh {
+		s.resourceCache.bypassed()
+		ctx = k8s.WithLiveReads(ctx)
+	} else if contents, ok := s.resourceCache.get(key); ok {
+		s.logger.Debugf("Serving %s from the resource cache", uri)
+		return withURI(contents, uri), nil
 	}
 
-	// Get some actual deployments and register them
-	deployments, err := s.k8sClient.ListDeployments(ctx, "")
+	contents, err := s.readResource(ctx, uri, resourceType, namespace, name, query, verbosity, format)
 	if err != nil {
-		s.logger.Errorf("Failed to list deployments for registration: %v", err)
-	} else {
-		// Register first few deployments as examples
-		count := 0
-		for _, deployment := range deployments {
-			if count >= 5 { // Limit to first 5 deployments
-				break
-			}
-			resource := mcp.Resource{
-				URI:         fmt.Sprintf("k8s://deployment/%s/%s", deployment.Namespace, deployment.Name),
-				Name:        fmt.Sprintf("Deployment: %s/%s", deployment.Namespace, deployment.Name),
-				Description: fmt.Sprintf("Kubernetes Deployment in namespace %s (%d/%d replicas ready)", deployment.Namespace, deployment.ReadyReplicas, deployment.TotalReplicas),
-				MIMEType:    "application/json",
-			}
-			s.mcpServer.AddResource(resource, s.handleResourceRead)
-			count++
-		}
+		return nil, err
 	}
+	s.resourceCache.put(key, contents)
+	return contents, nil
 }
 
-// handleResourceRead handles resource read requests
-func (s *Server) handleResourceRead(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
-	uri := request.Params.URI
-	s.logger.Infof("Handling read_resource request for URI: %s", uri)
-
-	if !strings.HasPrefix(uri, "k8s://") {
-		return nil, fmt.Errorf("invalid URI format. Expected k8s://<resource-type>/<namespace>/<name>, got: %s", uri)
+// logResourceCacheStatsEvery logs the resource cache's counters every interval until ctx
+// is cancelled, so the hit rate can be followed while the server runs
+func (s *Server) logResourceCacheStatsEvery(ctx context.Context, interval time.Duration) {
+	if s.resourceCache == nil || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.logResourceCacheStats()
+		case <-ctx.Done():
+			return
+		}
 	}
+}
 
-	// Parse URI: k8s://<resource-type>/<namespace>/<name>
-	parts := strings.Split(strings.TrimPrefix(uri, "k8s://"), "/")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("invalid URI format. Expected k8s://<resource-type>/<namespace>/<name>, got %d parts", len(parts))
+// logResourceCacheStats logs the resource cache's counters, if reads are cached
+func (s *Server) logResourceCacheStats() {
+	if s.resourceCache == nil {
+		return
 	}
+	stats := s.resourceCache.snapshot()
+	s.logger.WithFields(logrus.Fields{
+		"hits":      stats.Hits,
+		"misses":    stats.Misses,
+		"bypassed":  stats.Bypassed,
+		"evictions": stats.Evictions,
+		"entries":   stats.Entries,
+	}).Info("Resource cache stats")
+}
+
+// readResource reads and formats the object a resource URI names
+func (s *Server) readResource(ctx context.Context, uri, resourceType, namespace, name string, query url.Values, verbosity types.Verbosity, format types.OutputFormat) ([]mcp.ResourceContents, error) {
 
-	resourceType, namespace, name := parts[0], parts[1], parts[2]
+	// A single ConfigMap key, e.g. k8s://configmap/default/assets?key=logo.png, is returned
+	// as-is: binaryData keys as base64 blobs, data keys as text
+	if resourceType == "configmap" && query.Has("key") {
+		content, err := s.k8sClient.GetConfigMapKey(ctx, namespace, name, query.Get("key"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get resource %s: %w", uri, err)
+		}
+		content.URI = uri // echo the requested URI, including the key
+		return []mcp.ResourceContents{toResourceContents(content)}, nil
+	}
 
 	var resourceTypeEnum types.K8sResourceType
 	switch resourceType {
@@ -108,8 +176,28 @@ func (s *Server) handleResourceRead(ctx context.Context, request mcp.ReadResourc
 		resourceTypeEnum = types.ResourceTypeService
 	case "deployment":
 		resourceTypeEnum = types.ResourceTypeDeployment
+	case "configmap":
+		resourceTypeEnum = types.ResourceTypeConfigMap
+	case "namespace":
+		resourceTypeEnum = types.ResourceTypeNamespace
+	case "statefulset":
+		resourceTypeEnum = types.ResourceTypeStatefulSet
+	case "daemonset":
+		resourceTypeEnum = types.ResourceTypeDaemonSet
+	case "node":
+		resourceTypeEnum = types.ResourceTypeNode
+	case "persistentvolumeclaim":
+		resourceTypeEnum = types.ResourceTypePersistentVolumeClaim
+	case "persistentvolume":
+		resourceTypeEnum = types.ResourceTypePersistentVolume
+	case "job":
+		resourceTypeEnum = types.ResourceTypeJob
+	case "cronjob":
+		resourceTypeEnum = types.ResourceTypeCronJob
+	case "horizontalpodautoscaler":
+		resourceTypeEnum = types.ResourceTypeHPA
 	default:
-		return nil, fmt.Errorf("unsupported resource type: %s. Supported types: pod, service, deployment", resourceType)
+		return nil, fmt.Errorf("unsupported resource type: %s. Supported types: pod, service, deployment, configmap, namespace, statefulset, daemonset, node, persistentvolumeclaim, persistentvolume, job, cronjob, horizontalpodautoscaler", resourceType)
 	}
 
 	content, err := s.k8sClient.GetResource(ctx, &types.ResourceIdentifier{
@@ -122,56 +210,65 @@ func (s *Server) handleResourceRead(ctx context.Context, request mcp.ReadResourc
 		return nil, fmt.Errorf("failed to get resource %s: %w", uri, err)
 	}
 
-	// Format the content using AI-optimized formatters
-	var formattedContent string
-	var mimeType string
-
-	switch resourceType {
-	case "pod":
-		formattedContent, err = s.formatter.FormatPodForAI(content)
-		if err != nil {
-			s.logger.Errorf("Failed to format pod data: %v", err)
-			// Fall back to raw JSON
-			formattedContent = content
-			mimeType = "application/json"
-		} else {
-			mimeType = "text/markdown"
-		}
-
-	case "service":
-		formattedContent, err = s.formatter.FormatServiceForAI(content)
-		if err != nil {
-			s.logger.Errorf("Failed to format service data: %v", err)
-			// Fall back to raw JSON
-			formattedContent = content
-			mimeType = "application/json"
-		} else {
-			mimeType = "text/markdown"
-		}
-
-	case "deployment":
-		formattedContent, err = s.formatter.FormatDeploymentForAI(content)
-		if err != nil {
-			s.logger.Errorf("Failed to format deployment data: %v", err)
-			// Fall back to raw JSON
-			formattedContent = content
-			mimeType = "application/json"
-		} else {
-			mimeType = "text/markdown"
-		}
-
-	default:
-		// For unsupported types, return raw JSON
+	// Format the content using AI-optimized formatters unless another format was requested
+	formattedContent, mimeType, err := s.formatter.Format(resourceType, content, format, verbosity)
+	if err != nil {
+		s.logger.Errorf("Failed to format %s data: %v", resourceType, err)
+		// Fall back to raw JSON
 		formattedContent = content
 		mimeType = "application/json"
 	}
 
 	// Return the formatted resource contents
 	return []mcp.ResourceContents{
-		&mcp.TextResourceContents{
+		toResourceContents(&types.ResourceContent{
 			URI:      uri,
-			MIMEType: mimeType,
+			MimeType: mimeType,
 			Text:     formattedContent,
-		},
+		}),
 	}, nil
 }
+
+// parseResourceURI splits k8s://<resource-type>/<namespace>/<name>, or
+// k8s://<resource-type>/<name> for cluster-scoped types, into its parts and optional
+// query parameters, e.g. k8s://pod/default/web?verbosity=summary&format=yaml
+func parseResourceURI(uri string) (resourceType, namespace, name string, query url.Values, err error) {
+	if !strings.HasPrefix(uri, "k8s://") {
+		return "", "", "", nil, fmt.Errorf("invalid URI format. Expected k8s://<resource-type>/<namespace>/<name>, got: %s", uri)
+	}
+
+	path, rawQuery, _ := strings.Cut(uri, "?")
+	query, err = url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", "", "", nil, fmt.Errorf("invalid URI query %q: %w", rawQuery, err)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "k8s://"), "/")
+	switch {
+	case len(parts) == 2 && clusterScopedResourceTypes[parts[0]]:
+		return parts[0], "", parts[1], query, nil
+	case len(parts) == 3 && !clusterScopedResourceTypes[parts[0]]:
+		return parts[0], parts[1], parts[2], query, nil
+	case clusterScopedResourceTypes[parts[0]]:
+		return "", "", "", nil, fmt.Errorf("invalid URI format. Expected k8s://%s/<name>, got %d parts", parts[0], len(parts))
+	default:
+		return "", "", "", nil, fmt.Errorf("invalid URI format. Expected k8s://<resource-type>/<namespace>/<name>, got %d parts", len(parts))
+	}
+}
+
+// toResourceContents converts resource content to its MCP form. Content carrying a
+// blob is sent base64-encoded as BlobResourceContents; everything else is text.
+func toResourceContents(content *types.ResourceContent) mcp.ResourceContents {
+	if content.Blob != nil {
+		return &mcp.BlobResourceContents{
+			URI:      content.URI,
+			MIMEType: content.MimeType,
+			Blob:     base64.StdEncoding.EncodeToString(content.Blob),
+		}
+	}
+	return &mcp.TextResourceContents{
+		URI:      content.URI,
+		MIMEType: content.MimeType,
+		Text:     content.Text,
+	}
+}
@@ -2,14 +2,22 @@ package mcp
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/internal/tracing"
 	"kubernetes-mcp-server/pkg/auth"
 	"kubernetes-mcp-server/pkg/security"
+	"kubernetes-mcp-server/pkg/tools"
+	"kubernetes-mcp-server/pkg/types"
 )
 
 // ContextKey is a custom type for context keys to avoid collisions
@@ -22,6 +30,8 @@ const (
 	AuthInfoContextKey ContextKey = "auth_info"
 )
 
+var tracer = tracing.Tracer("kubernetes-mcp-server/pkg/mcp")
+
 type SecureMCPServer struct {
 	*Server  // Embed the original server
 	security *security.SecurityMiddleware
@@ -36,66 +46,266 @@ func NewSecureMCPServer(originalServer *Server, securityMiddleware *security.Sec
 	}
 }
 
+// HandleToolCall authenticates, authorizes and runs a tool call. The call gets a request
+// ID, carried in the context, that appears in its audit events and log lines.
 func (s *SecureMCPServer) HandleToolCall(ctx context.Context, toolName string, arguments map[string]interface{}) (map[string]interface{}, error) {
+	requestID := logging.NewRequestID()
+	ctx = logging.WithRequestID(ctx, requestID)
+	ctx, span := tracer.Start(ctx, "HandleToolCall", trace.WithAttributes(
+		attribute.String("mcp.tool", toolName),
+		attribute.String("mcp.request_id", requestID),
+	))
+	data, err := s.callTool(ctx, toolName, arguments)
+	tracing.End(span, err)
+	return data, err
+}
+
+func (s *SecureMCPServer) callTool(ctx context.Context, toolName string, arguments map[string]interface{}) (map[string]interface{}, error) {
 	startTime := time.Now()
 
+	authInfo, inferredNamespace, err := s.authorizeToolCall(ctx, startTime, toolName, arguments)
+	if err != nil {
+		return nil, err
+	}
+	resource, namespace := parseToolArguments(toolName, arguments)
+
+	// Add authentication info to context for the actual tool execution
+	ctxWithAuth := context.WithValue(ctx, AuthInfoContextKey, authInfo)
+	if toolName == tools.BatchToolName {
+		ctxWithAuth = tools.WithOperationCheck(ctxWithAuth, func(_ context.Context, toolName string, arguments map[string]interface{}) error {
+			return s.authorizeSecureOperation(ctx, toolName, arguments)
+		})
+	}
+
+	// Call the original tool implementation through the tool executor
+	result := s.Server.toolExecutor.ExecuteTool(ctxWithAuth, toolName, arguments)
+	noteInferredNamespace(result, inferredNamespace)
+
+	// Log the request
+	s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, nil)
+
+	// Check if execution was successful
+	if !result.Success {
+		code := result.Code
+		if code == 0 {
+			code = types.ErrorCodeInternalError
+		}
+		return nil, types.NewError(code, "tool execution failed: %s", result.Error)
+	}
+
+	return result.Data, nil
+}
+
+// authorizeToolCall authenticates the caller from the request headers in ctx and checks
+// they may run the tool, inferring an omitted namespace first. It returns the inferred
+// namespace, if any; arguments are updated with it.
+func (s *SecureMCPServer) authorizeToolCall(ctx context.Context, startTime time.Time, toolName string, arguments map[string]interface{}) (*auth.AuthInfo, string, error) {
 	// Extract headers from context (this would come from the transport layer)
 	headers := extractHeadersFromContext(ctx)
 
 	// Authenticate request
 	authInfo, err := s.security.AuthenticateRequest(ctx, headers)
 	if err != nil {
-		s.logger.WithError(err).Warn("Authentication failed")
-		return nil, fmt.Errorf("authentication failed: %w", err)
+		s.logger.WithError(err).WithFields(logging.RequestFields(ctx, logrus.Fields{
+			"tool": toolName,
+		})).Warn("Authentication failed")
+		return nil, "", types.NewError(types.ErrorCodeUnauthorized, "authentication failed: %v", err)
+	}
+
+	// A batch grants nothing itself; each of its operations is authorized as it runs
+	if toolName == tools.BatchToolName {
+		return authInfo, "", nil
+	}
+
+	action := parseActionFromToolName(toolName)
+
+	// Resolve an omitted namespace before deriving the one to authorize against
+	inferredNamespace, err := s.Server.inferNamespace(ctx, toolName, arguments, func(namespaces []string) []string {
+		resource, _ := parseToolArguments(toolName, arguments)
+		return s.security.PermittedNamespaces(ctx, authInfo, action, resource, namespaces)
+	})
+	if err != nil {
+		return nil, "", types.NewError(types.ErrorCodeInvalidParams, "%v", err)
 	}
 
 	// Extract resource and namespace from tool call
 	resource, namespace := parseToolArguments(toolName, arguments)
-	action := parseActionFromToolName(toolName)
+	if kindTools[toolName] {
+		if namespace, err = s.Server.kindNamespace(arguments, namespace); err != nil {
+			return nil, "", types.NewError(types.ErrorCodeInvalidParams, "%v", err)
+		}
+	}
+
+	if err := s.Server.checkNamespaceAllowed(namespace); err != nil {
+		s.logger.WithError(err).WithFields(logging.RequestFields(ctx, logrus.Fields{
+			"user": authInfo.Identity,
+			"tool": toolName,
+		})).Warn("Namespace not allowed")
+		s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, err)
+		return nil, "", err
+	}
 
 	// Authorize request
 	err = s.security.AuthorizeRequest(ctx, authInfo, action, resource, namespace)
 	if err != nil {
-		s.logger.WithError(err).WithFields(logrus.Fields{
+		s.logger.WithError(err).WithFields(logging.RequestFields(ctx, logrus.Fields{
 			"user": authInfo.Identity,
 			"tool": toolName,
-		}).Warn("Authorization failed")
+		})).Warn("Authorization failed")
 
 		s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, err)
 
-		return nil, fmt.Errorf("access denied: %w", err)
+		return nil, "", types.NewError(types.ErrorCodeForbidden, "access denied: %v", err)
 	}
 
-	// Add authentication info to context for the actual tool execution
-	ctxWithAuth := context.WithValue(ctx, AuthInfoContextKey, authInfo)
+	if permissions, granting := s.grantedPermissions(ctx, toolName, arguments); granting {
+		if err := s.security.AuthorizeGrant(ctx, authInfo, resource, permissions); err != nil {
+			s.logger.WithError(err).WithFields(logging.RequestFields(ctx, logrus.Fields{
+				"user": authInfo.Identity,
+				"tool": toolName,
+			})).Warn("Grant refused")
+			s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, err)
+			return nil, "", types.NewError(types.ErrorCodeForbidden, "access denied: %v", err)
+		}
+	}
 
-	// Call the original tool implementation through the tool executor
-	result := s.Server.toolExecutor.ExecuteTool(ctxWithAuth, toolName, arguments)
+	if err := s.security.CheckRate(ctx, authInfo, action, resource, namespace); err != nil {
+		s.logger.WithError(err).WithFields(logging.RequestFields(ctx, logrus.Fields{
+			"user": authInfo.Identity,
+			"tool": toolName,
+		})).Warn("Rate limit exceeded")
+		return nil, "", rateLimitedError(err)
+	}
 
-	// Log the request
-	s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, nil)
+	return authInfo, inferredNamespace, nil
+}
 
-	// Check if execution was successful
-	if !result.Success {
-		return nil, fmt.Errorf("tool execution failed: %s", result.Error)
+// authorizeSecureOperation authorizes an operation of a batch like a tool call of its own
+// and audits it, since the operation runs without passing through HandleToolCall
+func (s *SecureMCPServer) authorizeSecureOperation(ctx context.Context, toolName string, arguments map[string]interface{}) error {
+	startTime := time.Now()
+	authInfo, _, err := s.authorizeToolCall(ctx, startTime, toolName, arguments)
+	if err != nil {
+		return err
 	}
+	resource, namespace := parseToolArguments(toolName, arguments)
+	s.security.LogRequest(ctx, authInfo, toolName, resource, namespace, startTime, nil)
+	return nil
+}
 
-	return result.Data, nil
+// rateLimitedError converts a rate limiter refusal to an MCP error carrying the number of
+// seconds to wait before retrying
+func rateLimitedError(err error) *types.MCPError {
+	mcpErr := types.NewError(types.ErrorCodeRateLimited, "%v", err)
+	var limited *security.RateLimitError
+	if errors.As(err, &limited) {
+		retryAfter := int(math.Ceil(limited.RetryAfter.Seconds()))
+		mcpErr.Data = map[string]string{"retryAfterSeconds": strconv.Itoa(retryAfter)}
+	}
+	return mcpErr
 }
 
+// extractHeadersFromContext returns the request headers the transport stored in ctx. A
+// call without them carries no credentials, so it fails authentication.
 func extractHeadersFromContext(ctx context.Context) map[string]string {
-	// This would extract headers from the actual transport context
-	// For now, we'll simulate headers for demonstration
-	// In a real implementation, this would depend on your transport layer (HTTP, gRPC, etc.)
 	if headers, ok := ctx.Value(HeadersContextKey).(map[string]string); ok {
 		return headers
 	}
+	return map[string]string{}
+}
 
-	// For demo purposes, create a mock authorization header
-	// In production, this would come from the actual transport
-	return map[string]string{
-		"Authorization": "apikey demo-admin-key-67890", // Demo admin key
+// toolResources and toolActions cover tools whose names don't follow the
+// k8s_<action>_<resource> pattern used to derive permissions
+var (
+	toolResources = map[string]string{
+		"k8s_who_can":             "rbac",
+		"k8s_pod_services":        "services",
+		"k8s_wait_for_log":        "pods",
+		"k8s_find_missing_probes": "deployments",
+		"k8s_image_audit":         "deployments",
+		"server_preflight":        "server",
+		"k8s_rollout_status":      "deployments",
+		"k8s_rollout_history":     "deployments",
+		"k8s_describe":            "resources",
+		"k8s_list_namespaces":     "namespaces",
+		"k8s_cluster_info":        "cluster",
+		"k8s_top_nodes":           "nodes",
+		"k8s_cordon_node":         "nodes",
+		"k8s_drain_node":          "nodes",
+		"k8s_patch_resource":      "resources",
+		"k8s_label_resource":      "resources",
+		"k8s_annotate_resource":   "resources",
+		"server_create_api_key":   "apikeys",
+		"server_rotate_api_key":   "apikeys",
+		"server_issue_token":      "tokens",
+		tools.BatchToolName:       "batch",
+	}
+	toolActions = map[string]string{
+		"k8s_who_can":             "read",
+		"k8s_pod_services":        "list",
+		"k8s_wait_for_log":        "get_logs",
+		"k8s_diagnose_pod":        "get_logs",
+		"k8s_find_missing_probes": "list",
+		"k8s_image_audit":         "list",
+		"server_preflight":        "preflight",
+		"k8s_rollout_status":      "list",
+		"k8s_rollout_history":     "list",
+		"k8s_describe":            "describe",
+		"k8s_top_pods":            "list",
+		"k8s_top_nodes":           "list",
+		"k8s_cluster_info":        "read",
+		"server_create_api_key":   "manage",
+		"server_rotate_api_key":   "manage",
+		"server_issue_token":      "issue",
 	}
+)
+
+// grantedPermissions returns the permissions a call hands out in a new credential, which
+// may not exceed the caller's own, and whether it hands out any. Rotating an API key hands
+// out a key with the old key's permissions.
+func (s *SecureMCPServer) grantedPermissions(ctx context.Context, toolName string, arguments map[string]interface{}) ([]string, bool) {
+	switch toolName {
+	case "server_create_api_key", "server_issue_token":
+		return requestedPermissions(arguments), true
+	case "server_rotate_api_key":
+		keyID, _ := arguments["keyId"].(string)
+		if s.Server.apiKeys == nil {
+			return nil, false
+		}
+		info, err := s.Server.apiKeys.GetAPIKey(ctx, keyID)
+		if err != nil {
+			// The tool reports the missing key
+			return nil, false
+		}
+		return info.Permissions, true
+	}
+	return nil, false
+}
+
+// requestedPermissions returns the "permissions" argument of a granting tool
+func requestedPermissions(arguments map[string]interface{}) []string {
+	switch listed := arguments["permissions"].(type) {
+	case []string:
+		return listed
+	case []interface{}:
+		permissions := make([]string, 0, len(listed))
+		for _, permission := range listed {
+			if permission, ok := permission.(string); ok {
+				permissions = append(permissions, permission)
+			}
+		}
+		return permissions
+	}
+	return nil
+}
+
+// kindTools address an object of any kind, so whether their namespace applies depends on
+// the kind and is resolved before authorizing
+var kindTools = map[string]bool{
+	"k8s_describe":          true,
+	"k8s_patch_resource":    true,
+	"k8s_label_resource":    true,
+	"k8s_annotate_resource": true,
 }
 
 func parseToolArguments(toolName string, arguments map[string]interface{}) (resource, namespace string) {
@@ -106,6 +316,10 @@ func parseToolArguments(toolName string, arguments map[string]interface{}) (reso
 
 	// Determine resource type from tool name
 	switch {
+	case toolResources[toolName] != "":
+		resource = toolResources[toolName]
+	case strings.Contains(toolName, "role"):
+		resource = "rbac"
 	case strings.Contains(toolName, "pod"):
 		resource = "pods"
 	case strings.Contains(toolName, "deployment"):
@@ -116,6 +330,10 @@ func parseToolArguments(toolName string, arguments map[string]interface{}) (reso
 		resource = "secrets"
 	case strings.Contains(toolName, "configmap"):
 		resource = "configmaps"
+	case strings.Contains(toolName, "manifest"):
+		resource = "manifests"
+	case strings.Contains(toolName, "job"):
+		resource = "jobs"
 	default:
 		resource = "unknown"
 	}
@@ -123,6 +341,9 @@ func parseToolArguments(toolName string, arguments map[string]interface{}) (reso
 	// Default values
 	if namespace == "" {
 		namespace = "default"
+		if tools.IsClusterScoped(toolName) {
+			namespace = "*"
+		}
 	}
 
 	return resource, namespace
@@ -133,6 +354,10 @@ func parseActionFromToolName(toolName string) string {
 	// Tool names follow pattern: k8s_<action>_<resource>
 	// Examples: k8s_list_pods -> "list", k8s_scale_deployment -> "scale"
 
+	if action, ok := toolActions[toolName]; ok {
+		return action
+	}
+
 	parts := strings.Split(toolName, "_")
 	if len(parts) >= 3 && parts[0] == "k8s" {
 		return parts[1] // Return the action part
@@ -0,0 +1,296 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"kubernetes-mcp-server/internal/config"
+	"kubernetes-mcp-server/pkg/audit"
+	"kubernetes-mcp-server/pkg/auth"
+	"kubernetes-mcp-server/pkg/rbac"
+	"kubernetes-mcp-server/pkg/security"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+func TestHandleToolCallWithoutHeadersIsUnauthenticated(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	// The store holds the demo admin key, which calls without headers used to fall back to
+	keys := auth.NewInMemoryAPIKeyStore(logger)
+	keys.AddAPIKey("demo-admin-key-67890", &auth.APIKeyInfo{ID: "admin", Permissions: []string{"k8s:*"}})
+	authenticator := auth.NewMultiAuthenticator()
+	authenticator.AddAuthenticator("apikey", auth.NewAPIKeyAuthenticator(keys, logger))
+	middleware := security.NewSecurityMiddleware(authenticator, rbac.NewRBACEnforcer(logger), audit.NewAuditLogger(logger), logger)
+	server := NewSecureMCPServer(nil, middleware, logger)
+
+	_, err := server.HandleToolCall(context.Background(), "k8s_list_pods", map[string]interface{}{"namespace": "default"})
+	if code := types.ErrorCode(err); code != types.ErrorCodeUnauthorized {
+		t.Fatalf("call without headers returned code %d (%v), want unauthorized", code, err)
+	}
+}
+
+func TestHandleToolCallTagsAuditEventsWithRequestID(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	authenticator := auth.NewMultiAuthenticator()
+	authenticator.AddAuthenticator("apikey", auth.NewAPIKeyAuthenticator(auth.NewInMemoryAPIKeyStore(logger), logger))
+	var events bytes.Buffer
+	auditLogger := audit.NewAuditLoggerWithOutput(&events, logger)
+	middleware := security.NewSecurityMiddleware(authenticator, rbac.NewRBACEnforcer(logger), auditLogger, logger)
+	server := NewSecureMCPServer(nil, middleware, logger)
+
+	requestIDs := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "Bearer unknown-key"})
+		if _, err := server.HandleToolCall(ctx, "k8s_list_pods", map[string]interface{}{"namespace": "default"}); err == nil {
+			t.Fatal("call with an unknown key succeeded")
+		}
+
+		var event audit.AuditEvent
+		if err := json.NewDecoder(&events).Decode(&event); err != nil {
+			t.Fatalf("failed to decode audit event: %v", err)
+		}
+		if event.RequestID == "" {
+			t.Fatalf("audit event %s has no request ID", event.EventType)
+		}
+		requestIDs[event.RequestID] = true
+	}
+	if len(requestIDs) != 2 {
+		t.Fatalf("two calls shared request ID %v", requestIDs)
+	}
+}
+
+func TestClusterScopedKindsAreAuthorizedClusterWide(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: dev-patcher
+    permissions: ["k8s:resources:patch"]
+    namespaces: ["dev"]
+`, "role:dev-patcher")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+	patch := func(kind, namespace string) error {
+		arguments := map[string]interface{}{"kind": kind, "name": "target", "patchType": "merge", "patch": "{}", "confirm": true}
+		if namespace != "" {
+			arguments["namespace"] = namespace
+		}
+		_, _, err := server.authorizeToolCall(ctx, time.Now(), "k8s_patch_resource", arguments)
+		return err
+	}
+
+	if err := patch("deployment", "dev"); err != nil {
+		t.Fatalf("patching a deployment in dev was refused: %v", err)
+	}
+	if code := types.ErrorCode(patch("clusterrolebinding", "dev")); code != types.ErrorCodeInvalidParams {
+		t.Fatalf("patching a ClusterRoleBinding through namespace dev returned code %d, want invalid params", code)
+	}
+	if code := types.ErrorCode(patch("clusterrolebinding", "")); code != types.ErrorCodeForbidden {
+		t.Fatalf("patching a ClusterRoleBinding with a dev-only grant returned code %d, want forbidden", code)
+	}
+}
+
+func TestDescribingClusterScopedKindsNeedsClusterWideGrant(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: dev-viewer
+    permissions: ["k8s:resources:describe"]
+    namespaces: ["dev"]
+`, "role:dev-viewer")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+
+	if _, _, err := server.authorizeToolCall(ctx, time.Now(), "k8s_describe", map[string]interface{}{"kind": "deployment", "namespace": "dev", "name": "web"}); err != nil {
+		t.Fatalf("describing a deployment in dev was refused: %v", err)
+	}
+	for _, namespace := range []string{"dev", ""} {
+		arguments := map[string]interface{}{"kind": "node", "name": "worker-1"}
+		if namespace != "" {
+			arguments["namespace"] = namespace
+		}
+		if _, _, err := server.authorizeToolCall(ctx, time.Now(), "k8s_describe", arguments); err == nil {
+			t.Errorf("describing a node with a dev-only grant and namespace %q was authorized", namespace)
+		}
+	}
+}
+
+func TestLabelingClusterScopedKindsNeedsClusterWideGrant(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: dev-editor
+    permissions: ["k8s:resources:label", "k8s:resources:annotate"]
+    namespaces: ["dev"]
+`, "role:dev-editor")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+
+	for tool, field := range map[string]string{"k8s_label_resource": "labels", "k8s_annotate_resource": "annotations"} {
+		for _, namespace := range []string{"dev", ""} {
+			arguments := map[string]interface{}{"kind": "namespace", "name": "dev", field: map[string]interface{}{"pod-security.kubernetes.io/enforce": "privileged"}}
+			if namespace != "" {
+				arguments["namespace"] = namespace
+			}
+			if _, _, err := server.authorizeToolCall(ctx, time.Now(), tool, arguments); err == nil {
+				t.Errorf("%s on a namespace with a dev-only grant and namespace %q was authorized", tool, namespace)
+			}
+		}
+	}
+}
+
+// newPolicyTestServer returns a secure server enforcing policyYAML, where the API key
+// test-key carries permissions and "clusterrolebinding", "node" and "namespace" are the
+// cluster-scoped kinds
+func newPolicyTestServer(t *testing.T, policyYAML string, permissions ...string) *SecureMCPServer {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	enforcer := rbac.NewRBACEnforcer(logger)
+	if err := enforcer.LoadPolicy([]byte(policyYAML)); err != nil {
+		t.Fatal(err)
+	}
+	keys := auth.NewInMemoryAPIKeyStore(logger)
+	keys.AddAPIKey("test-key", &auth.APIKeyInfo{ID: "test", Permissions: permissions})
+	authenticator := auth.NewMultiAuthenticator()
+	authenticator.AddAuthenticator("apikey", auth.NewAPIKeyAuthenticator(keys, logger))
+	middleware := security.NewSecurityMiddleware(authenticator, enforcer, audit.NewAuditLogger(logger), logger)
+
+	server := &Server{
+		config: &config.Config{},
+		clusterScopedKind: func(kind string) (bool, error) {
+			return kind == "clusterrolebinding" || kind == "node" || kind == "namespace", nil
+		},
+	}
+	return NewSecureMCPServer(server, middleware, logger)
+}
+
+func TestIssuedTokensCannotExceedTheCaller(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: platform
+    permissions: ["k8s:*"]
+    deny: ["k8s:pods:delete"]
+    deniedNamespaces: ["production"]
+`, "role:platform")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+	issue := func(permissions ...interface{}) error {
+		arguments := map[string]interface{}{"username": "ci", "permissions": permissions, "confirm": true}
+		_, _, err := server.authorizeToolCall(ctx, time.Now(), "server_issue_token", arguments)
+		return err
+	}
+
+	if code := types.ErrorCode(issue("k8s:*")); code != types.ErrorCodeForbidden {
+		t.Fatalf("platform issuing an unrestricted token returned code %d, want forbidden", code)
+	}
+	if err := issue("role:platform"); err != nil {
+		t.Fatalf("platform issuing a token with its own role was refused: %v", err)
+	}
+}
+
+func TestAdminsIssueTokensWithNarrowerRoles(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: viewer
+    permissions: ["k8s:pods:list", "k8s:pods:logs"]
+    namespaces: ["default", "development"]
+`, "k8s:*")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+
+	// The README's example of an admin issuing a token for CI
+	arguments := map[string]interface{}{"username": "ci-bot", "permissions": []interface{}{"role:viewer"}, "confirm": true}
+	if _, _, err := server.authorizeToolCall(ctx, time.Now(), "server_issue_token", arguments); err != nil {
+		t.Fatalf("k8s:* issuing a role:viewer token was refused: %v", err)
+	}
+}
+
+func TestAPIKeysCannotExceedTheCaller(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: key-manager
+    permissions: ["k8s:apikeys:manage", "k8s:pods:list"]
+`, "role:key-manager")
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	managed := auth.NewInMemoryAPIKeyStore(logger)
+	_, admin, err := managed.CreateAPIKey(context.Background(), "admin", []string{"k8s:*"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, reader, err := managed.CreateAPIKey(context.Background(), "reader", []string{"k8s:pods:list"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Server.apiKeys = managed
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+	call := func(toolName string, arguments map[string]interface{}) error {
+		_, _, err := server.authorizeToolCall(ctx, time.Now(), toolName, arguments)
+		return err
+	}
+
+	if code := types.ErrorCode(call("server_create_api_key", map[string]interface{}{"name": "escalate", "permissions": []interface{}{"k8s:*"}, "confirm": true})); code != types.ErrorCodeForbidden {
+		t.Fatalf("creating a k8s:* key with only apikeys:manage returned code %d, want forbidden", code)
+	}
+	if err := call("server_create_api_key", map[string]interface{}{"name": "ci", "permissions": []interface{}{"k8s:pods:list"}, "confirm": true}); err != nil {
+		t.Fatalf("creating a key with the caller's own permission was refused: %v", err)
+	}
+	if code := types.ErrorCode(call("server_rotate_api_key", map[string]interface{}{"keyId": admin.ID, "confirm": true})); code != types.ErrorCodeForbidden {
+		t.Fatalf("rotating an admin key with only apikeys:manage returned code %d, want forbidden", code)
+	}
+	if err := call("server_rotate_api_key", map[string]interface{}{"keyId": reader.ID, "confirm": true}); err != nil {
+		t.Fatalf("rotating a key within the caller's permissions was refused: %v", err)
+	}
+}
+
+func TestAdminsCreateAPIKeysWithNarrowerRoles(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: viewer
+    permissions: ["k8s:pods:list", "k8s:pods:logs"]
+    namespaces: ["default", "development"]
+  - name: operator
+    permissions: ["k8s:pods:*", "k8s:deployments:*"]
+    namespaces: ["default"]
+`, "k8s:*")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+
+	arguments := map[string]interface{}{"name": "dashboard", "permissions": []interface{}{"role:viewer"}, "confirm": true}
+	if _, _, err := server.authorizeToolCall(ctx, time.Now(), "server_create_api_key", arguments); err != nil {
+		t.Fatalf("k8s:* creating a role:viewer key was refused: %v", err)
+	}
+
+	viewer := newPolicyTestServer(t, `
+roles:
+  - name: viewer
+    permissions: ["k8s:pods:list", "k8s:apikeys:manage"]
+  - name: operator
+    permissions: ["k8s:pods:*"]
+`, "role:viewer")
+	arguments = map[string]interface{}{"name": "escalate", "permissions": []interface{}{"role:operator"}, "confirm": true}
+	if _, _, err := viewer.authorizeToolCall(ctx, time.Now(), "server_create_api_key", arguments); types.ErrorCode(err) != types.ErrorCodeForbidden {
+		t.Fatalf("viewer creating a role:operator key returned %v, want forbidden", err)
+	}
+}
+
+func TestSweepsHonorDeniedNamespaces(t *testing.T) {
+	server := newPolicyTestServer(t, `
+roles:
+  - name: no-prod
+    permissions: ["k8s:*"]
+    deniedNamespaces: ["production"]
+`, "role:no-prod")
+	ctx := context.WithValue(context.Background(), HeadersContextKey, map[string]string{"Authorization": "ApiKey test-key"})
+
+	for _, toolName := range []string{"k8s_search_pods", "k8s_find_missing_probes"} {
+		_, _, err := server.authorizeToolCall(ctx, time.Now(), toolName, map[string]interface{}{})
+		if code := types.ErrorCode(err); code != types.ErrorCodeForbidden {
+			t.Errorf("%s across every namespace with production denied returned code %d, want forbidden", toolName, code)
+		}
+		if _, _, err := server.authorizeToolCall(ctx, time.Now(), toolName, map[string]interface{}{"namespace": "staging"}); err != nil {
+			t.Errorf("%s in staging was refused: %v", toolName, err)
+		}
+	}
+}
@@ -2,11 +2,17 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"kubernetes-mcp-server/internal/config"
 	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/pkg/auth"
 	"kubernetes-mcp-server/pkg/k8s"
+	"kubernetes-mcp-server/pkg/preflight"
 	"kubernetes-mcp-server/pkg/tools"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/mark3labs/mcp-go/server"
 )
@@ -20,17 +26,37 @@ type Server struct {
 	toolExecutor *tools.ToolExecutor
 	formatter    *ResourceFormatter
 	ctx          context.Context // Store context for tool operations
+	toolCalls    sync.WaitGroup  // Tool calls in flight, waited for on shutdown
+
+	// resourceCache is nil when resource reads are not cached
+	resourceCache *resourceCache
+	subscriptions *subscriptionRegistry
+	// authorizeSubscription, if set, checks the caller may read a subscribed object
+	authorizeSubscription func(ctx context.Context, object k8s.ResourceChange) error
+	// authorizeOperation, if set, checks the caller may run an operation of a batch
+	authorizeOperation tools.OperationCheckFunc
+	// apiKeys is the store the API key tools manage, if any
+	apiKeys auth.ManagedAPIKeyStore
+	// clusterScopedKind reports whether a kind named in a tool call is cluster-scoped
+	clusterScopedKind func(kind string) (bool, error)
+	watchCtx          context.Context // Cancelled when the transport stops
+	watchOnce         sync.Once
+	watchErr          error
 }
 
+// shutdownTimeout bounds how long in-flight tool calls get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
 // NewServer creates a new MCP server instance with proper MCP protocol implementation
 func NewServer(cfg *config.Config, k8sClient *k8s.Client) *Server {
-	logger := logging.NewLogger("info", "text")
+	logger := logging.NewLogger(cfg.Log.Level, cfg.Log.Format)
 
 	// Create MCP server
 	mcpServer := server.NewMCPServer(
 		"k8s-mcp-server",
 		"1.0.0",
-		server.WithResourceCapabilities(true, true),
+		// The SSE transport cannot answer subscriptions; see subscriptions.go
+		server.WithResourceCapabilities(cfg.Server.Transport != config.TransportSSE, true),
 		server.WithToolCapabilities(true),
 	)
 
@@ -41,30 +67,83 @@ func NewServer(cfg *config.Config, k8sClient *k8s.Client) *Server {
 		mcpServer:    mcpServer,
 		toolExecutor: tools.NewToolExecutor(k8sClient, logger),
 		formatter:    NewResourceFormatter(),
+
+		clusterScopedKind: k8sClient.IsClusterScoped,
+
+		resourceCache: newResourceCache(cfg.Server.ResourceCache.TTL, cfg.Server.ResourceCache.MaxEntries),
+		subscriptions: newSubscriptionRegistry(),
+		watchCtx:      context.Background(),
 	}
 
+	s.toolExecutor.SetExecAllowedCommands(cfg.K8s.ExecAllowedCommands)
+	s.toolExecutor.SetAllowedNamespaces(cfg.K8s.Namespaces)
+	s.toolExecutor.SetLogSidecarContainers(cfg.K8s.LogSidecarContainers)
+	s.toolExecutor.SetTimeouts(cfg.K8s.ToolTimeout, cfg.K8s.LogToolTimeout)
+
 	// Register MCP resources
-	s.registerResources()
+	s.registerResources(s.handleResourceRead)
 
 	// Register MCP tools
-	s.registerTools()
+	s.registerTools(s.handleToolCall)
 
 	return s
 }
 
-// Start starts the MCP server with stdio transport
+// SetPreflight sets the configuration checks run by the server_preflight tool
+func (s *Server) SetPreflight(run func(ctx context.Context) *preflight.Report) {
+	s.toolExecutor.SetPreflight(run)
+}
+
+// SetAPIKeyStore sets the store the server_create_api_key and server_rotate_api_key
+// tools manage
+func (s *Server) SetAPIKeyStore(store auth.ManagedAPIKeyStore) {
+	s.apiKeys = store
+	s.toolExecutor.SetAPIKeyStore(store)
+}
+
+// SetTokenIssuer sets the issuer the server_issue_token tool signs tokens with, and the
+// lifetime of tokens minted without ttlMinutes
+func (s *Server) SetTokenIssuer(issuer auth.TokenIssuer, defaultTTL time.Duration) {
+	s.toolExecutor.SetTokenIssuer(issuer, defaultTTL)
+}
+
+// Start starts the MCP server with stdio transport and serves until stdin is closed or
+// ctx is cancelled
 func (s *Server) Start(ctx context.Context) error {
 	s.logger.Info("Starting Kubernetes MCP Server")
 
-	// Store the context for use in tool operations
-	s.ctx = ctx
+	// Tool operations outlive ctx, so calls in flight at shutdown can finish
+	s.ctx = context.WithoutCancel(ctx)
+	s.watchCtx = ctx
+	go s.logResourceCacheStatsEvery(ctx, s.config.Server.ResourceCache.StatsInterval)
 
-	// Use the convenient ServeStdio function
-	if err := server.ServeStdio(s.mcpServer); err != nil {
+	// Listen stops reading requests once ctx is cancelled. Tool calls run concurrently
+	// and write their own responses, so wait for them before returning.
+	stdout := &lockedWriter{w: os.Stdout}
+	stdin := s.interceptSubscriptions(ctx, os.Stdin, stdout)
+	err := server.NewStdioServer(s.mcpServer).Listen(ctx, stdin, stdout)
+	s.waitForToolCalls(shutdownTimeout)
+	if err != nil && !errors.Is(err, context.Canceled) {
 		s.logger.Errorf("MCP server error: %v", err)
 		return fmt.Errorf("MCP server failed: %w", err)
 	}
 
+	s.logResourceCacheStats()
 	s.logger.Info("MCP Server stopped")
 	return nil
 }
+
+// waitForToolCalls waits up to timeout for in-flight tool calls to finish
+func (s *Server) waitForToolCalls(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.toolCalls.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		s.logger.Warnf("Stopped waiting for in-flight tool calls after %s", timeout)
+	}
+}
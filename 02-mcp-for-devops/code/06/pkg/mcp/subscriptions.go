@@ -0,0 +1,315 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"kubernetes-mcp-server/pkg/k8s"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+// Clients subscribe to a pod or deployment resource with resources/subscribe and are sent
+// notifications/resources/updated whenever it changes, e.g. so they can tell when a
+// rollout finishes. mcp-go advertises the subscribe capability but does not route these
+// methods, so the stdio and streamable HTTP transports handle them before passing other
+// messages on. The SSE transport sends responses on a stream mcp-go keeps to itself and
+// does not offer subscriptions.
+
+const (
+	// stdioSessionID is the session ID mcp-go gives the stdio client
+	stdioSessionID = "stdio"
+	// maxSubscriptionsPerSession bounds the resources one client may watch
+	maxSubscriptionsPerSession = 100
+	// maxSubscriptionRequestBytes bounds the HTTP request bodies read to find subscriptions
+	maxSubscriptionRequestBytes = 4 << 20
+
+	methodResourcesSubscribe   mcp.MCPMethod = "resources/subscribe"
+	methodResourcesUnsubscribe mcp.MCPMethod = "resources/unsubscribe"
+)
+
+// subscribableResourceTypes are the resource types whose changes are watched
+var subscribableResourceTypes = map[string]bool{
+	"pod":        true,
+	"deployment": true,
+}
+
+type subscriber struct {
+	sessionID string
+	uri       string // as the client subscribed, including any query
+}
+
+// subscriptionRegistry tracks which sessions are subscribed to which objects
+type subscriptionRegistry struct {
+	mu sync.Mutex
+	// bySession maps a session to its subscribed URIs and the object each one names
+	bySession map[string]map[string]k8s.ResourceChange
+	// byObject maps an object to the subscriptions naming it
+	byObject map[k8s.ResourceChange]map[subscriber]bool
+}
+
+func newSubscriptionRegistry() *subscriptionRegistry {
+	return &subscriptionRegistry{
+		bySession: make(map[string]map[string]k8s.ResourceChange),
+		byObject:  make(map[k8s.ResourceChange]map[subscriber]bool),
+	}
+}
+
+func (r *subscriptionRegistry) subscribe(sessionID, uri string, object k8s.ResourceChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uris := r.bySession[sessionID]
+	if _, exists := uris[uri]; exists {
+		return nil
+	}
+	if len(uris) >= maxSubscriptionsPerSession {
+		return fmt.Errorf("a session may subscribe to at most %d resources", maxSubscriptionsPerSession)
+	}
+	if uris == nil {
+		uris = make(map[string]k8s.ResourceChange)
+		r.bySession[sessionID] = uris
+	}
+	uris[uri] = object
+	if r.byObject[object] == nil {
+		r.byObject[object] = make(map[subscriber]bool)
+	}
+	r.byObject[object][subscriber{sessionID: sessionID, uri: uri}] = true
+	return nil
+}
+
+func (r *subscriptionRegistry) unsubscribe(sessionID, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(sessionID, uri)
+}
+
+// removeSession drops every subscription of a session that has ended
+func (r *subscriptionRegistry) removeSession(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for uri := range r.bySession[sessionID] {
+		r.remove(sessionID, uri)
+	}
+}
+
+// remove drops one subscription. Callers hold mu.
+func (r *subscriptionRegistry) remove(sessionID, uri string) {
+	object, ok := r.bySession[sessionID][uri]
+	if !ok {
+		return
+	}
+	delete(r.bySession[sessionID], uri)
+	if len(r.bySession[sessionID]) == 0 {
+		delete(r.bySession, sessionID)
+	}
+	delete(r.byObject[object], subscriber{sessionID: sessionID, uri: uri})
+	if len(r.byObject[object]) == 0 {
+		delete(r.byObject, object)
+	}
+}
+
+// subscribers returns the subscriptions naming object
+func (r *subscriptionRegistry) subscribers(object k8s.ResourceChange) []subscriber {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subscribers := make([]subscriber, 0, len(r.byObject[object]))
+	for sub := range r.byObject[object] {
+		subscribers = append(subscribers, sub)
+	}
+	return subscribers
+}
+
+// subscriptionObject returns the object a subscribable resource URI names
+func subscriptionObject(uri string) (k8s.ResourceChange, error) {
+	resourceType, namespace, name, _, err := parseResourceURI(uri)
+	if err != nil {
+		return k8s.ResourceChange{}, err
+	}
+	if !subscribableResourceTypes[resourceType] {
+		return k8s.ResourceChange{}, fmt.Errorf("subscriptions are supported for pod and deployment resources, not %s", resourceType)
+	}
+	return k8s.ResourceChange{Kind: resourceType, Namespace: namespace, Name: name}, nil
+}
+
+// handleSubscriptionMessage answers a resources/subscribe or resources/unsubscribe request
+// from sessionID. It reports false, leaving the message to mcp-go, for anything else.
+func (s *Server) handleSubscriptionMessage(ctx context.Context, sessionID string, message []byte) (mcp.JSONRPCMessage, bool) {
+	var request struct {
+		ID     *mcp.RequestId `json:"id"`
+		Method mcp.MCPMethod  `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil || request.ID == nil {
+		return nil, false
+	}
+
+	switch request.Method {
+	case methodResourcesSubscribe:
+		if err := s.subscribe(ctx, sessionID, request.Params.URI); err != nil {
+			return subscriptionError(*request.ID, err), true
+		}
+	case methodResourcesUnsubscribe:
+		s.subscriptions.unsubscribe(sessionID, request.Params.URI)
+	default:
+		return nil, false
+	}
+	return mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPC_VERSION, ID: *request.ID, Result: mcp.EmptyResult{}}, true
+}
+
+// subscribe checks uri names a subscribable resource the caller may read, starts watching
+// the cluster on the first subscription, and records the subscription
+func (s *Server) subscribe(ctx context.Context, sessionID, uri string) error {
+	object, err := subscriptionObject(uri)
+	if err != nil {
+		return types.NewError(types.ErrorCodeInvalidParams, "%v", err)
+	}
+	if err := s.checkNamespaceAllowed(object.Namespace); err != nil {
+		return err
+	}
+	if s.authorizeSubscription != nil {
+		if err := s.authorizeSubscription(ctx, object); err != nil {
+			return err
+		}
+	}
+
+	s.watchOnce.Do(func() {
+		s.watchErr = s.k8sClient.WatchChanges(s.watchCtx, s.notifySubscribers)
+	})
+	if s.watchErr != nil {
+		return types.NewError(types.ErrorCodeInternalError, "failed to watch the cluster: %v", s.watchErr)
+	}
+
+	if err := s.subscriptions.subscribe(sessionID, uri, object); err != nil {
+		return types.NewError(types.ErrorCodeInvalidRequest, "%v", err)
+	}
+	s.logger.Infof("Session %s subscribed to %s", sessionID, uri)
+	return nil
+}
+
+// notifySubscribers tells every session subscribed to a changed object about the change.
+// Cached reads of the object are dropped first, so the reads the notification prompts
+// see the change.
+func (s *Server) notifySubscribers(change k8s.ResourceChange) {
+	s.resourceCache.invalidate(fmt.Sprintf("k8s://%s/%s/%s", change.Kind, change.Namespace, change.Name))
+	for _, sub := range s.subscriptions.subscribers(change) {
+		err := s.mcpServer.SendNotificationToSpecificClient(sub.sessionID, string(mcp.MethodNotificationResourceUpdated), map[string]any{
+			"uri": sub.uri,
+		})
+		if err != nil {
+			// A streamable HTTP client only receives notifications while it holds a GET stream open
+			s.logger.Debugf("Failed to notify session %s of a change to %s: %v", sub.sessionID, sub.uri, err)
+		}
+	}
+}
+
+// subscriptionError converts an error to a JSON-RPC error response
+func subscriptionError(id mcp.RequestId, err error) mcp.JSONRPCError {
+	response := mcp.JSONRPCError{JSONRPC: mcp.JSONRPC_VERSION, ID: id}
+	response.Error.Code = types.ErrorCode(err)
+	response.Error.Message = err.Error()
+	return response
+}
+
+// interceptSubscriptions returns a reader of the stdio client's messages with subscription
+// requests removed. Those are answered on out, which must be the writer the stdio server
+// writes to, so that responses are not interleaved.
+func (s *Server) interceptSubscriptions(ctx context.Context, in io.Reader, out io.Writer) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		reader := bufio.NewReader(in)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				if response, handled := s.handleSubscriptionMessage(ctx, stdioSessionID, line); handled {
+					if err := writeJSONLine(out, response); err != nil {
+						s.logger.Errorf("Failed to write subscription response: %v", err)
+					}
+				} else if _, err := pw.Write(line); err != nil {
+					return // the stdio server has stopped reading
+				}
+			}
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				pw.CloseWithError(err)
+				return
+			}
+		}
+	}()
+	return pr
+}
+
+// subscriptionMiddleware answers subscription requests to the streamable HTTP transport
+// and passes everything else to next. Subscriptions end with their session. Requests
+// without a session ID the transport would accept are left for it to reject.
+func (s *Server) subscriptionMiddleware(next http.Handler) http.Handler {
+	sessions := &server.InsecureStatefulSessionIdManager{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := r.Header.Get(server.HeaderKeySessionID)
+		if terminated, err := sessions.Validate(sessionID); err != nil || terminated {
+			next.ServeHTTP(w, r)
+			return
+		}
+		switch r.Method {
+		case http.MethodDelete:
+			next.ServeHTTP(w, r)
+			s.subscriptions.removeSession(sessionID)
+			return
+		case http.MethodPost:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSubscriptionRequestBytes))
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if response, handled := s.handleSubscriptionMessage(withRequestHeaders(r.Context(), r), sessionID, body); handled {
+			w.Header().Set("Content-Type", "application/json")
+			if err := writeJSONLine(w, response); err != nil {
+				s.logger.Errorf("Failed to write subscription response: %v", err)
+			}
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeJSONLine writes message as one line of JSON
+func writeJSONLine(w io.Writer, message any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// lockedWriter serializes writes from the stdio server and subscription responses
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/pkg/k8s"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+func TestSubscriptionRegistry(t *testing.T) {
+	registry := newSubscriptionRegistry()
+	web := k8s.ResourceChange{Kind: "deployment", Namespace: "default", Name: "web"}
+
+	if err := registry.subscribe("a", "k8s://deployment/default/web", web); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.subscribe("a", "k8s://deployment/default/web?format=yaml", web); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.subscribe("b", "k8s://deployment/default/web", web); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(registry.subscribers(web)); got != 3 {
+		t.Fatalf("got %d subscribers, want 3", got)
+	}
+
+	registry.unsubscribe("a", "k8s://deployment/default/web")
+	registry.removeSession("b")
+	subscribers := registry.subscribers(web)
+	if len(subscribers) != 1 || subscribers[0] != (subscriber{sessionID: "a", uri: "k8s://deployment/default/web?format=yaml"}) {
+		t.Fatalf("got subscribers %v after unsubscribing", subscribers)
+	}
+
+	for i := 1; i < maxSubscriptionsPerSession; i++ {
+		pod := k8s.ResourceChange{Kind: "pod", Namespace: "default", Name: fmt.Sprintf("web-%d", i)}
+		if err := registry.subscribe("a", "k8s://pod/default/"+pod.Name, pod); err != nil {
+			t.Fatalf("subscription %d failed: %v", i, err)
+		}
+	}
+	if err := registry.subscribe("a", "k8s://pod/default/one-too-many", k8s.ResourceChange{Kind: "pod", Namespace: "default", Name: "one-too-many"}); err == nil {
+		t.Fatal("subscription beyond the per-session limit succeeded")
+	}
+}
+
+func TestStdioSubscriptionRequestsAreAnswered(t *testing.T) {
+	s := &Server{
+		logger:        logging.NewLogger("error", "text"),
+		subscriptions: newSubscriptionRegistry(),
+		watchCtx:      context.Background(),
+	}
+	// Mark the watch as started, since there is no cluster to watch
+	s.watchOnce.Do(func() {})
+
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"k8s://pod/default/web-0"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/subscribe","params":{"uri":"k8s://configmap/default/settings"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/unsubscribe","params":{"uri":"k8s://pod/default/web-0"}}`,
+	}, "\n") + "\n"
+	var out bytes.Buffer
+
+	passed, err := io.ReadAll(s.interceptSubscriptions(context.Background(), strings.NewReader(in), &out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(passed), `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`+"\n"; got != want {
+		t.Fatalf("passed %q to the stdio server, want %q", got, want)
+	}
+
+	decoder := json.NewDecoder(&out)
+	var responses []map[string]interface{}
+	for decoder.More() {
+		var response map[string]interface{}
+		if err := decoder.Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+		responses = append(responses, response)
+	}
+	if len(responses) != 3 {
+		t.Fatalf("got %d responses, want 3: %v", len(responses), responses)
+	}
+	if responses[0]["id"] != 1.0 || responses[0]["result"] == nil {
+		t.Errorf("subscribe response = %v, want a result", responses[0])
+	}
+	if rpcError, _ := responses[1]["error"].(map[string]interface{}); responses[1]["id"] != 3.0 || rpcError["code"] != float64(types.ErrorCodeInvalidParams) {
+		t.Errorf("configmap subscribe response = %v, want an invalid params error", responses[1])
+	}
+	if responses[2]["id"] != 4.0 || responses[2]["result"] == nil {
+		t.Errorf("unsubscribe response = %v, want a result", responses[2])
+	}
+	if subscribers := s.subscriptions.subscribers(k8s.ResourceChange{Kind: "pod", Namespace: "default", Name: "web-0"}); len(subscribers) != 0 {
+		t.Errorf("still subscribed after unsubscribing: %v", subscribers)
+	}
+}
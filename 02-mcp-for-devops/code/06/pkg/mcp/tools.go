@@ -3,34 +3,92 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"kubernetes-mcp-server/internal/logging"
 	"kubernetes-mcp-server/pkg/tools"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/sirupsen/logrus"
 )
 
-func (s *Server) registerTools() {
+// registerTools registers every tool definition with handler. Registering again replaces
+// the handlers, which is how transports that authenticate callers install their own.
+func (s *Server) registerTools(handler server.ToolHandlerFunc) {
 	// Register tool capabilities
 	toolDefinitions := tools.GetToolDefinitions()
 
 	for _, toolDef := range toolDefinitions {
-		s.mcpServer.AddTool(toolDef, s.handleToolCall)
+		if s.config.K8s.InferNamespace {
+			toolDef = withOptionalNamespace(toolDef)
+		}
+		s.mcpServer.AddTool(toolDef, handler)
 		s.logger.Infof("Registered tool: %s", toolDef.Name)
 	}
 
 	s.logger.Infof("Registered %d tools", len(toolDefinitions))
 }
 
+// withOptionalNamespace drops namespace from the required inputs of tools that address a
+// resource by name, so clients may omit it and let the server infer it
+func withOptionalNamespace(toolDef mcp.Tool) mcp.Tool {
+	if _, hasName := toolDef.InputSchema.Properties["name"]; !hasName {
+		return toolDef
+	}
+
+	required := make([]string, 0, len(toolDef.InputSchema.Required))
+	for _, field := range toolDef.InputSchema.Required {
+		if field != "namespace" {
+			required = append(required, field)
+		}
+	}
+	toolDef.InputSchema.Required = required
+	return toolDef
+}
+
 // Add new method to handle tool calls
 func (s *Server) handleToolCall(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	s.toolCalls.Add(1)
+	defer s.toolCalls.Done()
+
 	toolName := request.Params.Name
-	arguments := request.Params.Arguments
+	args := request.Params.Arguments.(map[string]interface{})
+	s.logger.WithFields(logging.RequestFields(ctx, logrus.Fields{})).Infof("Handling tool call: %s with arguments: %v", toolName, logging.RedactArguments(toolName, args))
 
-	s.logger.Infof("Handling tool call: %s with arguments: %v", toolName, arguments)
+	inferredNamespace, err := s.inferNamespace(s.ctx, toolName, args, nil)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Type: "text",
+					Text: formatToolError(&tools.ExecuteResult{
+						Message:   "Failed to infer namespace",
+						Error:     err.Error(),
+						Timestamp: time.Now(),
+					}),
+				},
+			},
+		}, err
+	}
 
 	// Use the stored context from the server instead of the MCP framework context
 	// This prevents tool execution from being cancelled prematurely
-	result := s.toolExecutor.ExecuteTool(s.ctx, toolName, arguments.(map[string]interface{}))
+	execCtx := s.ctx
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		execCtx = logging.WithRequestID(execCtx, requestID)
+	}
+	if toolName == tools.BatchToolName {
+		execCtx = tools.WithOperationCheck(execCtx, func(_ context.Context, toolName string, arguments map[string]interface{}) error {
+			return s.checkBatchOperation(ctx, toolName, arguments)
+		})
+	}
+	if follow, _ := args["follow"].(bool); follow {
+		var cancel context.CancelFunc
+		execCtx, cancel = s.logStreamContext(ctx, request)
+		defer cancel()
+	}
+	result := s.toolExecutor.ExecuteTool(execCtx, toolName, args)
+	noteInferredNamespace(result, inferredNamespace)
 
 	// Convert result to MCP format
 	if result.Success {
@@ -54,6 +112,46 @@ func (s *Server) handleToolCall(ctx context.Context, request mcp.CallToolRequest
 	}
 }
 
+// checkBatchOperation prepares an operation of a batch called in requestCtx: it is
+// authorized like a call of its own when the transport authenticates callers, and its
+// namespace is inferred like any call's
+func (s *Server) checkBatchOperation(requestCtx context.Context, toolName string, arguments map[string]interface{}) error {
+	if s.authorizeOperation != nil {
+		return s.authorizeOperation(requestCtx, toolName, arguments)
+	}
+	_, err := s.inferNamespace(s.ctx, toolName, arguments, nil)
+	return err
+}
+
+// logStreamContext prepares the context for a follow-mode log call. A follow is open-ended,
+// so unlike other tools it stops when the client cancels the request. When the request
+// carries a progress token, each log line is sent to the client as a progress notification.
+func (s *Server) logStreamContext(requestCtx context.Context, request mcp.CallToolRequest) (context.Context, context.CancelFunc) {
+	execCtx, cancel := context.WithCancel(s.ctx)
+	stop := context.AfterFunc(requestCtx, cancel)
+
+	if request.Params.Meta != nil && request.Params.Meta.ProgressToken != nil {
+		token := request.Params.Meta.ProgressToken
+		lines := 0
+		execCtx = tools.WithLogStream(execCtx, func(line string) {
+			lines++
+			err := s.mcpServer.SendNotificationToClient(requestCtx, "notifications/progress", map[string]any{
+				"progressToken": token,
+				"progress":      lines,
+				"message":       line,
+			})
+			if err != nil {
+				s.logger.Debugf("Failed to stream log line: %v", err)
+			}
+		})
+	}
+
+	return execCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
 // formatToolResult formats successful tool execution results
 func formatToolResult(result *tools.ExecuteResult) string {
 	output := fmt.Sprintf("# ✅ %s\n\n", result.Message)
@@ -61,7 +159,8 @@ func formatToolResult(result *tools.ExecuteResult) string {
 
 	if len(result.Data) > 0 {
 		output += "## Result Details\n\n"
-		for key, value := range result.Data {
+		for _, key := range sortedKeys(result.Data) {
+			value := result.Data[key]
 			switch v := value.(type) {
 			case string:
 				if key == "logs" {
@@ -94,6 +193,9 @@ func formatToolResult(result *tools.ExecuteResult) string {
 func formatToolError(result *tools.ExecuteResult) string {
 	output := fmt.Sprintf("# ❌ %s\n\n", result.Message)
 	output += fmt.Sprintf("**Error**: %s\n\n", result.Error)
+	if result.Code != 0 {
+		output += fmt.Sprintf("**Code**: %d\n\n", result.Code)
+	}
 	output += fmt.Sprintf("**Timestamp**: %s\n\n", result.Timestamp.Format(time.RFC3339))
 
 	output += "## Troubleshooting\n\n"
@@ -0,0 +1,30 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"kubernetes-mcp-server/pkg/tools"
+)
+
+func TestFormatToolResultIsDeterministic(t *testing.T) {
+	result := &tools.ExecuteResult{
+		Success:   true,
+		Message:   "Scaled deployment",
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data: map[string]interface{}{
+			"namespace":   "default",
+			"deployment":  "web",
+			"replicas":    3,
+			"oldReplicas": 1,
+			"labels":      map[string]interface{}{"tier": "frontend", "app": "web"},
+		},
+	}
+
+	want := formatToolResult(result)
+	for i := 0; i < 20; i++ {
+		if got := formatToolResult(result); got != want {
+			t.Fatalf("output changed between calls:\n%s\n---\n%s", want, got)
+		}
+	}
+}
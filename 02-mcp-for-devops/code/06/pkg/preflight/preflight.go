@@ -0,0 +1,87 @@
+// Package preflight validates that the server's configuration is coherent before it
+// starts serving, and on demand through the server_preflight tool.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Severity decides whether a failing check blocks startup
+type Severity string
+
+const (
+	// SeverityError checks must pass for the server to start
+	SeverityError Severity = "error"
+	// SeverityWarning checks are reported but do not block startup
+	SeverityWarning Severity = "warning"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusWarn Status = "warn"
+)
+
+// Check is a single named configuration check
+type Check struct {
+	Name     string
+	Severity Severity
+	Run      func(ctx context.Context) error
+}
+
+// Result is the outcome of running one Check
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Message  string        `json:"message,omitempty"`
+	Duration time.Duration `json:"durationMs"`
+}
+
+// Report collects the results of a preflight run. OK is false when any
+// error-severity check failed.
+type Report struct {
+	OK      bool      `json:"ok"`
+	Results []Result  `json:"results"`
+	RanAt   time.Time `json:"ranAt"`
+}
+
+// Run executes every check in order and reports all results, rather than stopping at
+// the first failure, so one run shows everything that is misconfigured
+func Run(ctx context.Context, checks []Check) *Report {
+	report := &Report{OK: true, RanAt: time.Now()}
+
+	for _, check := range checks {
+		start := time.Now()
+		err := check.Run(ctx)
+
+		result := Result{Name: check.Name, Status: StatusPass, Duration: time.Since(start)}
+		if err != nil {
+			result.Message = err.Error()
+			result.Status = StatusWarn
+			if check.Severity != SeverityWarning {
+				result.Status = StatusFail
+				report.OK = false
+			}
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+// Failures returns a one-line summary of every failed and warning check
+func (r *Report) Failures() string {
+	var problems []string
+	for _, result := range r.Results {
+		if result.Status != StatusPass {
+			problems = append(problems, fmt.Sprintf("%s [%s]: %s", result.Name, result.Status, result.Message))
+		}
+	}
+	return strings.Join(problems, "; ")
+}
@@ -3,7 +3,10 @@ package rbac
 import (
 	"context"
 	"fmt"
+	"path"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -16,21 +19,43 @@ const (
 	PermissionListPods        Permission = "k8s:pods:list"
 	PermissionGetPodLogs      Permission = "k8s:pods:logs"
 	PermissionScaleDeployment Permission = "k8s:deployments:scale"
+	PermissionRollback        Permission = "k8s:deployments:rollback"
 	PermissionRestartPod      Permission = "k8s:pods:restart"
+	PermissionExecPod         Permission = "k8s:pods:exec"
 	PermissionListServices    Permission = "k8s:services:list"
 	PermissionListDeployments Permission = "k8s:deployments:list"
+	PermissionReadRBAC        Permission = "k8s:rbac:read"
+	PermissionManageNodes     Permission = "k8s:nodes:manage"
 
 	// Admin permissions
-	PermissionManageSecrets   Permission = "k8s:secrets:manage"
-	PermissionDeletePods      Permission = "k8s:pods:delete"
-	PermissionCreateResources Permission = "k8s:resources:create"
+	PermissionManageSecrets     Permission = "k8s:secrets:manage"
+	PermissionDeletePods        Permission = "k8s:pods:delete"
+	PermissionDeleteDeployments Permission = "k8s:deployments:delete"
+	PermissionCreateResources   Permission = "k8s:resources:create"
+	PermissionPatchResources    Permission = "k8s:resources:patch"
+	PermissionManageAPIKeys     Permission = "k8s:apikeys:manage"
+	// PermissionIssueTokens is held only by callers granted every permission. The tokens
+	// they mint are further limited by CheckGrant to what they hold themselves.
+	PermissionIssueTokens Permission = "k8s:*"
 )
 
+// Role grants Permissions in Namespaces, along with everything granted by the roles it
+// Inherits, each parent within its own namespaces. Deny and DeniedNamespaces carve exceptions out
+// of what a caller is allowed, and take precedence over every allow the caller holds,
+// from this role, other roles, or direct permissions:
+//   - Deny alone denies those permissions in every namespace
+//   - DeniedNamespaces alone denies every permission in those namespaces
+//   - both together deny the listed permissions in the listed namespaces
+//
+// Deny rules are inherited too.
 type Role struct {
-	Name        string       `yaml:"name"`
-	Description string       `yaml:"description"`
-	Permissions []Permission `yaml:"permissions"`
-	Namespaces  []string     `yaml:"namespaces,omitempty"` // Empty means all namespaces
+	Name             string       `yaml:"name"`
+	Description      string       `yaml:"description"`
+	Permissions      []Permission `yaml:"permissions"`
+	Namespaces       []string     `yaml:"namespaces,omitempty"` // Empty means all namespaces
+	Deny             []Permission `yaml:"deny,omitempty"`
+	DeniedNamespaces []string     `yaml:"deniedNamespaces,omitempty"`
+	Inherits         []string     `yaml:"inherits,omitempty"`
 }
 
 type Policy struct {
@@ -38,6 +63,7 @@ type Policy struct {
 }
 
 type RBACEnforcer struct {
+	mu     sync.RWMutex
 	policy *Policy
 	logger *logrus.Logger
 }
@@ -49,18 +75,114 @@ func NewRBACEnforcer(logger *logrus.Logger) *RBACEnforcer {
 	}
 }
 
+// ParsePolicy parses and checks an RBAC policy without loading it. A policy must define
+// at least one role, and every role needs a unique name, at least one permission, deny
+// rule or parent role, and well-formed namespace patterns. Parents must exist and must
+// not inherit, directly or transitively, from the role itself.
+func ParsePolicy(policyYAML []byte) (*Policy, error) {
+	policy := &Policy{}
+	if err := yaml.Unmarshal(policyYAML, policy); err != nil {
+		return nil, fmt.Errorf("failed to parse RBAC policy: %w", err)
+	}
+
+	if len(policy.Roles) == 0 {
+		return nil, fmt.Errorf("invalid RBAC policy: no roles defined")
+	}
+	seen := make(map[string]bool)
+	for i, role := range policy.Roles {
+		if role.Name == "" {
+			return nil, fmt.Errorf("invalid RBAC policy: role %d has no name", i)
+		}
+		if seen[role.Name] {
+			return nil, fmt.Errorf("invalid RBAC policy: duplicate role %q", role.Name)
+		}
+		seen[role.Name] = true
+		if len(role.Permissions) == 0 && len(role.Deny) == 0 && len(role.DeniedNamespaces) == 0 && len(role.Inherits) == 0 {
+			return nil, fmt.Errorf("invalid RBAC policy: role %q has no permissions, deny rules or parent roles", role.Name)
+		}
+		if err := checkNamespacePatterns(append(append([]string(nil), role.Namespaces...), role.DeniedNamespaces...)); err != nil {
+			return nil, fmt.Errorf("invalid RBAC policy: role %q: %w", role.Name, err)
+		}
+	}
+	if err := checkInheritance(policy); err != nil {
+		return nil, fmt.Errorf("invalid RBAC policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// checkInheritance rejects parents that are not defined and inheritance cycles
+func checkInheritance(policy *Policy) error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(policy.Roles))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("role inheritance cycle: %s", strings.Join(append(path, name), " -> "))
+		case done:
+			return nil
+		}
+		state[name] = visiting
+		role := policy.findRole(name)
+		for _, parent := range role.Inherits {
+			if policy.findRole(parent) == nil {
+				return fmt.Errorf("role %q inherits unknown role %q", name, parent)
+			}
+			if err := visit(parent, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = done
+		return nil
+	}
+
+	for _, role := range policy.Roles {
+		if err := visit(role.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadPolicy parses policyYAML and atomically replaces the active policy.
+// On a parse error the previously loaded policy stays in effect.
 func (r *RBACEnforcer) LoadPolicy(policyYAML []byte) error {
-	err := yaml.Unmarshal(policyYAML, r.policy)
+	policy, err := ParsePolicy(policyYAML)
 	if err != nil {
-		return fmt.Errorf("failed to parse RBAC policy: %w", err)
+		return err
 	}
 
-	r.logger.WithField("roles_count", len(r.policy.Roles)).Info("RBAC policy loaded")
+	r.mu.Lock()
+	r.policy = policy
+	r.mu.Unlock()
+
+	r.logger.WithField("roles_count", len(policy.Roles)).Info("RBAC policy loaded")
 	return nil
 }
 
+// CheckPermission returns nil when userPermissions allow requiredPermission in namespace.
+// Deny rules of the caller's roles are evaluated first, so an explicit deny always wins
+// over an allow, however broad the allow's wildcard.
 func (r *RBACEnforcer) CheckPermission(ctx context.Context, userPermissions []string, requiredPermission Permission, namespace string) error {
-	// First, check for direct permissions (non-role based)
+	userRoles := r.resolveRoles(r.getUserRoles(userPermissions))
+
+	for _, role := range userRoles {
+		if r.roleDenies(role, requiredPermission, namespace) {
+			r.logger.WithFields(logrus.Fields{
+				"role":                role.Name,
+				"required_permission": requiredPermission,
+				"namespace":           namespace,
+			}).Warn("Permission explicitly denied")
+			return fmt.Errorf("permission denied: %s in namespace %s is denied by role %s", requiredPermission, namespace, role.Name)
+		}
+	}
+
+	// Then check for direct permissions (non-role based)
 	for _, userPerm := range userPermissions {
 		if Permission(userPerm) == requiredPermission {
 			r.logger.WithFields(logrus.Fields{
@@ -93,20 +215,13 @@ func (r *RBACEnforcer) CheckPermission(ctx context.Context, userPermissions []st
 	}
 
 	// If no direct permissions found, try role-based permissions
-	userRoles := r.getUserRoles(userPermissions)
-
-	for _, roleName := range userRoles {
-		role := r.findRole(roleName)
-		if role == nil {
-			continue
-		}
-
+	for _, role := range userRoles {
 		// Check if role has the required permission
 		if r.roleHasPermission(role, requiredPermission) {
 			// Check namespace access
 			if r.roleHasNamespaceAccess(role, namespace) {
 				r.logger.WithFields(logrus.Fields{
-					"role":       roleName,
+					"role":       role.Name,
 					"permission": requiredPermission,
 					"namespace":  namespace,
 				}).Debug("Permission granted")
@@ -124,6 +239,91 @@ func (r *RBACEnforcer) CheckPermission(ctx context.Context, userPermissions []st
 	return fmt.Errorf("permission denied: %s in namespace %s", requiredPermission, namespace)
 }
 
+// CheckGrant returns nil when a credential carrying requested, such as a new API key or
+// token, would allow nothing that callerPermissions do not. Each requested permission
+// must be held by the caller in every namespace, and each permission of a requested role,
+// including those it inherits, in every namespace the role covers. Every role of the
+// caller with deny rules must be requested too, so the credential keeps the caller's
+// denies. Requesting "k8s:*" while holding a deny-restricted "k8s:*" is therefore refused.
+func (r *RBACEnforcer) CheckGrant(ctx context.Context, callerPermissions, requested []string) error {
+	callerRoles := r.resolveRoles(r.getUserRoles(callerPermissions))
+
+	requestedRoles := r.getUserRoles(requested)
+	for _, roleName := range requestedRoles {
+		if err := r.checkRoleGrant(ctx, callerPermissions, roleName); err != nil {
+			return err
+		}
+	}
+
+	for _, permission := range directPermissions(requested) {
+		if !r.holdsEverywhere(callerPermissions, callerRoles, permission) {
+			return fmt.Errorf("cannot grant %s: the caller does not hold it in every namespace", permission)
+		}
+	}
+
+	granted := make(map[string]bool)
+	for _, role := range r.resolveRoles(requestedRoles) {
+		granted[role.Name] = true
+	}
+	for _, role := range callerRoles {
+		if (len(role.Deny) > 0 || len(role.DeniedNamespaces) > 0) && !granted[role.Name] {
+			return fmt.Errorf("cannot grant without role %s, whose deny rules restrict the caller", role.Name)
+		}
+	}
+	return nil
+}
+
+// checkRoleGrant returns nil when callerPermissions allow every permission of roleName and
+// the roles it inherits, in every namespace each of those roles covers
+func (r *RBACEnforcer) checkRoleGrant(ctx context.Context, callerPermissions []string, roleName string) error {
+	roles := r.resolveRoles([]string{roleName})
+	if len(roles) == 0 {
+		return fmt.Errorf("cannot grant role %s: no such role", roleName)
+	}
+
+	for _, role := range roles {
+		namespaces := role.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = []string{"*"}
+		}
+		for _, permission := range role.Permissions {
+			for _, namespace := range namespaces {
+				if err := r.CheckPermission(ctx, callerPermissions, permission, namespace); err != nil {
+					return fmt.Errorf("cannot grant role %s: the caller does not hold %s in namespace %s", roleName, permission, namespace)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// holdsEverywhere reports whether permission, possibly a wildcard, is held in every
+// namespace: directly, or through a role not limited to some namespaces
+func (r *RBACEnforcer) holdsEverywhere(callerPermissions []string, callerRoles []*Role, permission Permission) bool {
+	if permissionListMatches(directPermissions(callerPermissions), permission) {
+		return true
+	}
+	for _, role := range callerRoles {
+		everywhere := len(role.Namespaces) == 0 || slices.Contains(role.Namespaces, "*")
+		if everywhere && r.roleHasPermission(role, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// directPermissions returns the entries of permissions that are permissions rather than
+// role names
+func directPermissions(permissions []string) []Permission {
+	var direct []Permission
+	for _, permission := range permissions {
+		if strings.Contains(permission, ":") && !strings.HasPrefix(permission, "role:") {
+			direct = append(direct, Permission(permission))
+		}
+	}
+	return direct
+}
+
 func (r *RBACEnforcer) getUserRoles(permissions []string) []string {
 	var roles []string
 	for _, permission := range permissions {
@@ -138,23 +338,85 @@ func (r *RBACEnforcer) getUserRoles(permissions []string) []string {
 	return roles
 }
 
-func (r *RBACEnforcer) findRole(roleName string) *Role {
-	for _, role := range r.policy.Roles {
-		if role.Name == roleName {
-			return &role
+// resolveRoles returns the named roles followed, transitively, by the roles they inherit
+// from, each once. Unknown names are skipped.
+func (r *RBACEnforcer) resolveRoles(roleNames []string) []*Role {
+	// Policies are replaced, never modified, so a snapshot stays consistent
+	r.mu.RLock()
+	policy := r.policy
+	r.mu.RUnlock()
+
+	var resolved []*Role
+	visited := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		// ParsePolicy rejects cycles; visited also keeps each role to one entry
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		role := policy.findRole(name)
+		if role == nil {
+			return
+		}
+		resolved = append(resolved, role)
+		for _, parent := range role.Inherits {
+			visit(parent)
+		}
+	}
+	for _, name := range roleNames {
+		visit(name)
+	}
+	return resolved
+}
+
+func (p *Policy) findRole(roleName string) *Role {
+	for i := range p.Roles {
+		if p.Roles[i].Name == roleName {
+			return &p.Roles[i]
 		}
 	}
 	return nil
 }
 
 func (r *RBACEnforcer) roleHasPermission(role *Role, permission Permission) bool {
-	for _, rolePermission := range role.Permissions {
-		if rolePermission == permission {
+	return permissionListMatches(role.Permissions, permission)
+}
+
+func (r *RBACEnforcer) roleHasNamespaceAccess(role *Role, namespace string) bool {
+	// Empty namespaces list means access to all namespaces
+	if len(role.Namespaces) == 0 {
+		return true
+	}
+	return namespaceListMatches(role.Namespaces, namespace)
+}
+
+// roleDenies reports whether a deny rule of role covers permission in namespace. A
+// request in namespace "*", such as a sweep of every namespace, reaches the denied
+// namespaces too, so any DeniedNamespaces entry covers it.
+func (r *RBACEnforcer) roleDenies(role *Role, permission Permission, namespace string) bool {
+	if len(role.Deny) == 0 && len(role.DeniedNamespaces) == 0 {
+		return false
+	}
+	if len(role.Deny) > 0 && !permissionListMatches(role.Deny, permission) {
+		return false
+	}
+	if len(role.DeniedNamespaces) > 0 && namespace != "*" && !namespaceListMatches(role.DeniedNamespaces, namespace) {
+		return false
+	}
+	return true
+}
+
+// permissionListMatches reports whether permission is in permissions, directly or
+// through a wildcard such as "k8s:pods:*"
+func permissionListMatches(permissions []Permission, permission Permission) bool {
+	for _, listed := range permissions {
+		if listed == permission {
 			return true
 		}
 		// Check for wildcard permissions
-		if strings.HasSuffix(string(rolePermission), ":*") {
-			prefix := strings.TrimSuffix(string(rolePermission), "*")
+		if strings.HasSuffix(string(listed), ":*") {
+			prefix := strings.TrimSuffix(string(listed), "*")
 			if strings.HasPrefix(string(permission), prefix) {
 				return true
 			}
@@ -163,16 +425,28 @@ func (r *RBACEnforcer) roleHasPermission(role *Role, permission Permission) bool
 	return false
 }
 
-func (r *RBACEnforcer) roleHasNamespaceAccess(role *Role, namespace string) bool {
-	// Empty namespaces list means access to all namespaces
-	if len(role.Namespaces) == 0 {
-		return true
-	}
-
-	for _, allowedNamespace := range role.Namespaces {
-		if allowedNamespace == namespace || allowedNamespace == "*" {
+// namespaceListMatches reports whether namespace matches an entry of namespaces. Entries
+// are exact names or path.Match globs such as "team-a-*" or "*-prod"; "*" matches every
+// namespace.
+func namespaceListMatches(namespaces []string, namespace string) bool {
+	for _, listed := range namespaces {
+		if listed == namespace || listed == "*" {
+			return true
+		}
+		// ParsePolicy rejects malformed patterns, so an error here means no match
+		if matched, _ := path.Match(listed, namespace); matched {
 			return true
 		}
 	}
 	return false
 }
+
+// checkNamespacePatterns returns an error for the first malformed glob in namespaces
+func checkNamespacePatterns(namespaces []string) error {
+	for _, pattern := range namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q", pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package rbac
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+const denyPolicy = `
+roles:
+  - name: platform
+    permissions: ["k8s:*"]
+    deny: ["k8s:pods:delete", "k8s:deployments:*"]
+    deniedNamespaces: ["production"]
+  - name: no-secrets
+    deny: ["k8s:secrets:manage"]
+  - name: no-prod
+    deniedNamespaces: ["production"]
+`
+
+func TestCheckPermissionDenyPrecedence(t *testing.T) {
+	enforcer := loadTestPolicy(t, denyPolicy)
+
+	tests := []struct {
+		name        string
+		permissions []string
+		required    Permission
+		namespace   string
+		allowed     bool
+	}{
+		{"wildcard allow outside denied namespace", []string{"platform"}, PermissionDeletePods, "staging", true},
+		{"deny wins over wildcard allow", []string{"platform"}, PermissionDeletePods, "production", false},
+		{"deny wildcard", []string{"platform"}, PermissionScaleDeployment, "production", false},
+		{"permission not denied", []string{"platform"}, PermissionListPods, "production", true},
+		{"deny wins over direct permission", []string{"k8s:*", "no-secrets"}, PermissionManageSecrets, "default", false},
+		{"deny-only role leaves other permissions", []string{"k8s:*", "no-secrets"}, PermissionListPods, "default", true},
+		{"denied namespace blocks everything", []string{"k8s:*", "no-prod"}, PermissionListPods, "production", false},
+		{"denied namespace leaves others", []string{"k8s:*", "no-prod"}, PermissionListPods, "default", true},
+		{"denied namespace blocks cluster-wide sweep", []string{"k8s:*", "no-prod"}, PermissionListPods, "*", false},
+		{"denied permission blocks cluster-wide sweep", []string{"platform"}, PermissionDeletePods, "*", false},
+		{"permission not denied across namespaces", []string{"platform"}, PermissionListPods, "*", true},
+	}
+	for _, tt := range tests {
+		err := enforcer.CheckPermission(context.Background(), tt.permissions, tt.required, tt.namespace)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%s: allowed = %v, want %v (err: %v)", tt.name, err == nil, tt.allowed, err)
+		}
+	}
+}
+
+func TestIssueTokensNeedsFullWildcard(t *testing.T) {
+	enforcer := loadTestPolicy(t, denyPolicy)
+
+	if err := enforcer.CheckPermission(context.Background(), []string{"k8s:*"}, PermissionIssueTokens, "*"); err != nil {
+		t.Errorf("k8s:* should be able to issue tokens: %v", err)
+	}
+	for _, permissions := range [][]string{{"k8s:pods:*"}, {"k8s:apikeys:manage"}} {
+		if err := enforcer.CheckPermission(context.Background(), permissions, PermissionIssueTokens, "*"); err == nil {
+			t.Errorf("%v should not be able to issue tokens", permissions)
+		}
+	}
+
+	// platform holds k8s:* with deny rules; an unrestricted token would escape them
+	if err := enforcer.CheckGrant(context.Background(), []string{"platform"}, []string{"k8s:*"}); err == nil {
+		t.Error("platform should not be able to issue a k8s:* token")
+	}
+}
+
+const grantPolicy = `
+roles:
+  - name: platform
+    permissions: ["k8s:*"]
+    deny: ["k8s:pods:delete"]
+    deniedNamespaces: ["production"]
+  - name: dev
+    permissions: ["k8s:pods:*"]
+    namespaces: ["dev"]
+  - name: viewer
+    permissions: ["k8s:pods:list", "k8s:services:list"]
+  - name: support
+    inherits: ["viewer"]
+    permissions: ["k8s:pods:logs"]
+  - name: operator
+    permissions: ["k8s:pods:*", "k8s:deployments:*"]
+    namespaces: ["default", "staging"]
+  - name: admin
+    permissions: ["k8s:*"]
+`
+
+func TestCheckGrant(t *testing.T) {
+	enforcer := loadTestPolicy(t, grantPolicy)
+
+	tests := []struct {
+		name      string
+		caller    []string
+		requested []string
+		allowed   bool
+	}{
+		{"admin grants anything", []string{"k8s:*"}, []string{"k8s:*"}, true},
+		{"wildcard covers narrower permission", []string{"k8s:pods:*"}, []string{"k8s:pods:list"}, true},
+		{"narrower permission does not cover wildcard", []string{"k8s:pods:list"}, []string{"k8s:pods:*"}, false},
+		{"permission the caller lacks", []string{"k8s:apikeys:manage"}, []string{"k8s:*"}, false},
+		{"role permission held everywhere", []string{"viewer"}, []string{"k8s:pods:list"}, true},
+		{"role permission held in one namespace", []string{"dev"}, []string{"k8s:pods:list"}, false},
+		{"caller's own role", []string{"dev"}, []string{"role:dev"}, true},
+		{"inherited role", []string{"support"}, []string{"viewer"}, true},
+		{"role the caller lacks", []string{"viewer"}, []string{"support"}, false},
+		{"admin grants a narrower role", []string{"k8s:*"}, []string{"role:viewer"}, true},
+		{"admin role grants a narrower role", []string{"role:admin"}, []string{"role:viewer"}, true},
+		{"admin grants a role and what it inherits", []string{"k8s:*"}, []string{"role:support"}, true},
+		{"namespaced role within the caller's namespaces", []string{"k8s:pods:*"}, []string{"role:dev"}, true},
+		{"namespaced caller cannot grant a cluster-wide role", []string{"dev"}, []string{"role:viewer"}, false},
+		{"viewer cannot grant operator", []string{"viewer"}, []string{"role:operator"}, false},
+		{"unknown role", []string{"k8s:*"}, []string{"role:nobody"}, false},
+		{"deny-restricted wildcard", []string{"platform"}, []string{"k8s:*"}, false},
+		{"denied permission", []string{"platform"}, []string{"k8s:pods:delete"}, false},
+		{"deny rules carried over", []string{"platform"}, []string{"role:platform"}, true},
+		{"deny rules carried over with more permissions", []string{"platform"}, []string{"platform", "k8s:pods:list"}, true},
+	}
+	for _, tt := range tests {
+		err := enforcer.CheckGrant(context.Background(), tt.caller, tt.requested)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%s: allowed = %v, want %v (err: %v)", tt.name, err == nil, tt.allowed, err)
+		}
+	}
+}
+
+const globPolicy = `
+roles:
+  - name: team-a
+    permissions: ["k8s:pods:list"]
+    namespaces: ["team-a-*"]
+  - name: teams
+    permissions: ["k8s:pods:logs"]
+    namespaces: ["team-*", "shared"]
+  - name: prod-readonly
+    deny: ["k8s:pods:restart"]
+    deniedNamespaces: ["*-prod"]
+  - name: ops
+    permissions: ["k8s:pods:*"]
+    namespaces: ["team-?-dev", "*-prod"]
+  - name: everywhere
+    permissions: ["k8s:services:list"]
+    namespaces: ["*"]
+`
+
+func TestCheckPermissionNamespacePatterns(t *testing.T) {
+	enforcer := loadTestPolicy(t, globPolicy)
+
+	tests := []struct {
+		roles     []string
+		required  Permission
+		namespace string
+		allowed   bool
+	}{
+		{[]string{"team-a"}, PermissionListPods, "team-a-dev", true},
+		{[]string{"team-a"}, PermissionListPods, "team-a", false},
+		{[]string{"team-a"}, PermissionListPods, "team-b-dev", false},
+		// Overlapping patterns: team-a-* is inside team-*, and either role may grant
+		{[]string{"team-a", "teams"}, PermissionGetPodLogs, "team-a-dev", true},
+		{[]string{"team-a", "teams"}, PermissionListPods, "team-b-dev", false},
+		{[]string{"teams"}, PermissionGetPodLogs, "shared", true},
+		{[]string{"teams"}, PermissionGetPodLogs, "shared-prod", false},
+		{[]string{"ops"}, PermissionRestartPod, "team-c-dev", true},
+		{[]string{"ops"}, PermissionRestartPod, "team-cc-dev", false},
+		{[]string{"ops"}, PermissionRestartPod, "team-a-prod", true},
+		// A denied pattern overlapping an allowed one wins
+		{[]string{"ops", "prod-readonly"}, PermissionRestartPod, "team-a-prod", false},
+		{[]string{"ops", "prod-readonly"}, PermissionListPods, "team-a-prod", true},
+		{[]string{"ops", "prod-readonly"}, PermissionRestartPod, "team-c-dev", true},
+		// A literal * still means every namespace
+		{[]string{"everywhere"}, PermissionListServices, "anything", true},
+	}
+	for _, tt := range tests {
+		err := enforcer.CheckPermission(context.Background(), tt.roles, tt.required, tt.namespace)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%v %s in %s: allowed = %v, want %v", tt.roles, tt.required, tt.namespace, err == nil, tt.allowed)
+		}
+	}
+}
+
+func TestParsePolicyRejectsMalformedNamespacePattern(t *testing.T) {
+	_, err := ParsePolicy([]byte(`
+roles:
+  - name: broken
+    permissions: ["k8s:pods:list"]
+    namespaces: ["team-[a"]
+`))
+	if err == nil {
+		t.Fatal("malformed namespace pattern accepted")
+	}
+}
+
+const inheritancePolicy = `
+roles:
+  - name: viewer
+    permissions: ["k8s:pods:list", "k8s:pods:logs"]
+  - name: editor
+    inherits: ["viewer"]
+    permissions: ["k8s:deployments:scale"]
+    namespaces: ["staging"]
+  - name: lead
+    inherits: ["editor"]
+    deny: ["k8s:pods:logs"]
+    deniedNamespaces: ["payments"]
+`
+
+func TestCheckPermissionInheritance(t *testing.T) {
+	enforcer := loadTestPolicy(t, inheritancePolicy)
+
+	tests := []struct {
+		roles     []string
+		required  Permission
+		namespace string
+		allowed   bool
+	}{
+		{[]string{"editor"}, PermissionScaleDeployment, "staging", true},
+		{[]string{"editor"}, PermissionScaleDeployment, "default", false},
+		// Inherited permissions keep the parent's namespaces
+		{[]string{"editor"}, PermissionListPods, "default", true},
+		// Grants and denies are inherited transitively
+		{[]string{"lead"}, PermissionListPods, "payments", true},
+		{[]string{"lead"}, PermissionScaleDeployment, "staging", true},
+		{[]string{"lead"}, PermissionGetPodLogs, "default", true},
+		{[]string{"lead"}, PermissionGetPodLogs, "payments", false},
+	}
+	for _, tt := range tests {
+		err := enforcer.CheckPermission(context.Background(), tt.roles, tt.required, tt.namespace)
+		if (err == nil) != tt.allowed {
+			t.Errorf("%v %s in %s: allowed = %v, want %v", tt.roles, tt.required, tt.namespace, err == nil, tt.allowed)
+		}
+	}
+}
+
+func TestParsePolicyRejectsBadInheritance(t *testing.T) {
+	policies := map[string]string{
+		"unknown parent": `
+roles:
+  - name: editor
+    inherits: ["missing"]
+`,
+		"self": `
+roles:
+  - name: editor
+    inherits: ["editor"]
+`,
+		"cycle": `
+roles:
+  - name: a
+    permissions: ["k8s:pods:list"]
+    inherits: ["b"]
+  - name: b
+    inherits: ["c"]
+  - name: c
+    inherits: ["a"]
+`,
+	}
+	for name, policy := range policies {
+		if _, err := ParsePolicy([]byte(policy)); err == nil {
+			t.Errorf("%s: policy accepted", name)
+		}
+	}
+}
+
+func loadTestPolicy(t *testing.T, policyYAML string) *RBACEnforcer {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	enforcer := NewRBACEnforcer(logger)
+	if err := enforcer.LoadPolicy([]byte(policyYAML)); err != nil {
+		t.Fatal(err)
+	}
+	return enforcer
+}
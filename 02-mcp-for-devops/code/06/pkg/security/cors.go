@@ -0,0 +1,83 @@
+package security
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy lets browser clients on other web origins call the HTTP endpoints. With no
+// AllowedOrigins, no CORS headers are sent, so only pages served from the server's own
+// origin can call it.
+type CORSPolicy struct {
+	// AllowedOrigins lists origins such as "https://console.example.com"; "*" allows any
+	AllowedOrigins []string
+	// AllowedMethods and AllowedHeaders answer preflight requests
+	AllowedMethods []string
+	AllowedHeaders []string
+	// MaxAge is how long browsers may cache a preflight answer; zero leaves it to them
+	MaxAge time.Duration
+}
+
+// corsExposedHeaders are response headers browser clients need to read: the streamable
+// HTTP session ID and the rate limiter's retry hint
+const corsExposedHeaders = "Mcp-Session-Id, Retry-After"
+
+// Handler wraps next with the policy. Preflight requests from allowed origins are
+// answered without reaching next; those from other origins are refused with 403.
+// Other requests always reach next, with CORS headers only for allowed origins, so a
+// browser on another origin cannot read the response.
+func (p CORSPolicy) Handler(next http.Handler) http.Handler {
+	if len(p.AllowedOrigins) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if !p.allowsOrigin(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if !preflight {
+			w.Header().Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !slices.Contains(p.AllowedMethods, r.Header.Get("Access-Control-Request-Method")) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+		if p.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// allowsOrigin reports whether origin is listed, ignoring case, or "*" is
+func (p CORSPolicy) allowsOrigin(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORSPolicy(t *testing.T) {
+	policy := CORSPolicy{
+		AllowedOrigins: []string{"https://console.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Authorization", "Content-Type"},
+		MaxAge:         10 * time.Minute,
+	}
+	handler := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func(method, origin, requestMethod string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/mcp/tools", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if requestMethod != "" {
+			r.Header.Set("Access-Control-Request-Method", requestMethod)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodOptions, "https://console.example.com", "POST")
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://console.example.com" ||
+		w.Header().Get("Access-Control-Allow-Headers") != "Authorization, Content-Type" || w.Header().Get("Access-Control-Max-Age") != "600" {
+		t.Errorf("preflight from an allowed origin: got %d %v", w.Code, w.Header())
+	}
+	if w := serve(http.MethodOptions, "https://console.example.com", "PUT"); w.Code != http.StatusForbidden {
+		t.Errorf("preflight for a method not allowed: got %d, want 403", w.Code)
+	}
+	if w := serve(http.MethodOptions, "https://evil.example.com", "POST"); w.Code != http.StatusForbidden {
+		t.Errorf("preflight from another origin: got %d, want 403", w.Code)
+	}
+
+	w = serve(http.MethodPost, "https://console.example.com", "")
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://console.example.com" {
+		t.Errorf("request from an allowed origin: got %d %v", w.Code, w.Header())
+	}
+	w = serve(http.MethodPost, "https://evil.example.com", "")
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("request from another origin got CORS headers %v", w.Header())
+	}
+	if w := serve(http.MethodPost, "", ""); w.Code != http.StatusOK {
+		t.Errorf("request without an origin: got %d, want 200", w.Code)
+	}
+
+	// Without allowed origins the policy adds nothing, leaving browsers at same-origin only
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodOptions, "/mcp/tools", nil)
+	r.Header.Set("Origin", "https://console.example.com")
+	r.Header.Set("Access-Control-Request-Method", "POST")
+	CORSPolicy{}.Handler(http.NotFoundHandler()).ServeHTTP(w, r)
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("default policy sent CORS headers %v", w.Header())
+	}
+}
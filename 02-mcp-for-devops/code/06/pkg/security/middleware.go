@@ -2,21 +2,28 @@ package security
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	"kubernetes-mcp-server/internal/tracing"
 	"kubernetes-mcp-server/pkg/audit"
 	"kubernetes-mcp-server/pkg/auth"
 	"kubernetes-mcp-server/pkg/rbac"
 )
 
+var tracer = tracing.Tracer("kubernetes-mcp-server/pkg/security")
+
 type SecurityMiddleware struct {
 	authenticator *auth.MultiAuthenticator
 	rbacEnforcer  *rbac.RBACEnforcer
 	auditLogger   *audit.AuditLogger
+	rateLimiter   *RateLimiter
 	logger        *logrus.Logger
 }
 
@@ -34,7 +41,23 @@ func NewSecurityMiddleware(
 	}
 }
 
+// SetRateLimiter limits how often each caller may make requests. Without one requests
+// are not limited.
+func (s *SecurityMiddleware) SetRateLimiter(limiter *RateLimiter) {
+	s.rateLimiter = limiter
+}
+
 func (s *SecurityMiddleware) AuthenticateRequest(ctx context.Context, headers map[string]string) (*auth.AuthInfo, error) {
+	ctx, span := tracer.Start(ctx, "AuthenticateRequest")
+	authInfo, err := s.authenticate(ctx, headers)
+	if authInfo != nil {
+		span.SetAttributes(attribute.String("enduser.id", authInfo.Identity))
+	}
+	tracing.End(span, err)
+	return authInfo, err
+}
+
+func (s *SecurityMiddleware) authenticate(ctx context.Context, headers map[string]string) (*auth.AuthInfo, error) {
 	// Extract authentication information from headers
 	authHeader := headers["Authorization"]
 	if authHeader == "" {
@@ -66,15 +89,59 @@ func (s *SecurityMiddleware) AuthorizeRequest(ctx context.Context, authInfo *aut
 	// Convert action to permission
 	permission := actionToPermission(action, resource)
 
+	ctx, span := tracer.Start(ctx, "AuthorizeRequest", trace.WithAttributes(
+		attribute.String("rbac.permission", string(permission)),
+		attribute.String("k8s.namespace.name", namespace),
+	))
+
 	// Check permission
 	err := s.rbacEnforcer.CheckPermission(ctx, authInfo.Permissions, permission, namespace)
 
 	// Log authorization decision
 	s.auditLogger.LogAuthorization(ctx, authInfo.Identity, action, resource, namespace, err == nil)
 
+	tracing.End(span, err)
 	return err
 }
 
+// AuthorizeGrant checks authInfo may hand out permissions in a new credential on resource,
+// such as an API key or token. The credential may allow nothing authInfo is not allowed.
+func (s *SecurityMiddleware) AuthorizeGrant(ctx context.Context, authInfo *auth.AuthInfo, resource string, permissions []string) error {
+	err := s.rbacEnforcer.CheckGrant(ctx, authInfo.Permissions, permissions)
+	s.auditLogger.LogAuthorization(ctx, authInfo.Identity, "grant", resource, "*", err == nil)
+	return err
+}
+
+// CheckRate takes a request from authInfo's rate limit for action on resource. Refused
+// requests are audited and return a *RateLimitError.
+func (s *SecurityMiddleware) CheckRate(ctx context.Context, authInfo *auth.AuthInfo, action, resource, namespace string) error {
+	if s.rateLimiter == nil {
+		return nil
+	}
+
+	err := s.rateLimiter.Allow(authInfo.Identity, actionToPermission(action, resource))
+	var limited *RateLimitError
+	if errors.As(err, &limited) {
+		s.auditLogger.LogRateLimit(ctx, authInfo.Identity, action, resource, namespace, string(limited.Permission), limited.RetryAfter)
+	}
+	return err
+}
+
+// PermittedNamespaces filters namespaces down to those where authInfo may perform action on
+// resource. Unlike AuthorizeRequest it does not audit each check, since it is used to narrow
+// a search rather than to authorize the request itself.
+func (s *SecurityMiddleware) PermittedNamespaces(ctx context.Context, authInfo *auth.AuthInfo, action, resource string, namespaces []string) []string {
+	permission := actionToPermission(action, resource)
+
+	var permitted []string
+	for _, namespace := range namespaces {
+		if s.rbacEnforcer.CheckPermission(ctx, authInfo.Permissions, permission, namespace) == nil {
+			permitted = append(permitted, namespace)
+		}
+	}
+	return permitted
+}
+
 func (s *SecurityMiddleware) LogRequest(ctx context.Context, authInfo *auth.AuthInfo, action, resource, namespace string, startTime time.Time, err error) {
 	s.auditLogger.LogMCPRequest(ctx, authInfo.Identity, action, resource, namespace, startTime, err)
 }
@@ -107,12 +174,31 @@ func actionToPermission(action, resource string) rbac.Permission {
 		return rbac.PermissionGetPodLogs
 	case action == "scale" && resource == "deployments":
 		return rbac.PermissionScaleDeployment
+	case action == "rollback" && resource == "deployments":
+		return rbac.PermissionRollback
+	case resource == "apikeys":
+		// The keys issued are further limited to the caller's own grants; see AuthorizeGrant
+		return rbac.PermissionManageAPIKeys
+	case resource == "tokens":
+		return rbac.PermissionIssueTokens
+	case action == "patch" && resource == "resources":
+		return rbac.PermissionPatchResources
+	case (action == "cordon" || action == "drain") && resource == "nodes":
+		return rbac.PermissionManageNodes
 	case action == "restart" && resource == "pods":
 		return rbac.PermissionRestartPod
+	case action == "exec" && resource == "pods":
+		return rbac.PermissionExecPod
 	case action == "list" && resource == "services":
 		return rbac.PermissionListServices
 	case action == "list" && resource == "deployments":
 		return rbac.PermissionListDeployments
+	case resource == "rbac":
+		return rbac.PermissionReadRBAC
+	case resource == "secrets":
+		// Even listing key names reveals what credentials exist, so every secret operation
+		// requires the admin permission
+		return rbac.PermissionManageSecrets
 	default:
 		return rbac.Permission(fmt.Sprintf("k8s:%s:%s", resource, action))
 	}
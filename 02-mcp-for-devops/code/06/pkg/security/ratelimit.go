@@ -0,0 +1,118 @@
+package security
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"kubernetes-mcp-server/pkg/rbac"
+)
+
+// rateLimiterIdleTTL is how long a caller's buckets are kept after their last request
+const rateLimiterIdleTTL = 10 * time.Minute
+
+// RateLimit is a token bucket: callers may make Burst requests at once, refilled at
+// RequestsPerSecond
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// RateLimitError reports a request refused by the rate limiter
+type RateLimitError struct {
+	Permission rbac.Permission
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit exceeded for %s, retry after %s", e.Permission, e.RetryAfter.Round(time.Second))
+}
+
+// RateLimiter keeps a token bucket per caller identity. Permissions with a limit of their
+// own get a separate bucket per caller, so stricter limits on mutating actions do not use
+// up the budget for reads; every other permission shares the caller's default bucket.
+type RateLimiter struct {
+	defaultLimit  RateLimit
+	perPermission map[rbac.Permission]RateLimit
+
+	mu        sync.Mutex
+	buckets   map[rateLimitKey]*rateBucket
+	lastSweep time.Time
+}
+
+type rateLimitKey struct {
+	identity   string
+	permission rbac.Permission // empty for the default bucket
+}
+
+type rateBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// NewRateLimiter creates a limiter applying defaultLimit to every permission without an
+// entry in perPermission. A limit with RequestsPerSecond of zero or less is unlimited.
+func NewRateLimiter(defaultLimit RateLimit, perPermission map[rbac.Permission]RateLimit) *RateLimiter {
+	return &RateLimiter{
+		defaultLimit:  defaultLimit,
+		perPermission: perPermission,
+		buckets:       make(map[rateLimitKey]*rateBucket),
+		lastSweep:     time.Now(),
+	}
+}
+
+// Allow takes a token from identity's bucket for permission, returning a *RateLimitError
+// when the bucket is empty
+func (l *RateLimiter) Allow(identity string, permission rbac.Permission) error {
+	limit, ok := l.perPermission[permission]
+	key := rateLimitKey{identity: identity}
+	if ok {
+		key.permission = permission
+	} else {
+		limit = l.defaultLimit
+	}
+	if limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		burst := limit.Burst
+		if burst < 1 {
+			burst = int(math.Max(1, math.Ceil(limit.RequestsPerSecond)))
+		}
+		bucket = &rateBucket{limiter: rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)}
+		l.buckets[key] = bucket
+	}
+	bucket.lastSeen = now
+
+	reservation := bucket.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		// Refused requests must not borrow from future tokens
+		reservation.CancelAt(now)
+		return &RateLimitError{Permission: permission, RetryAfter: delay}
+	}
+	return nil
+}
+
+// sweep drops buckets idle for longer than rateLimiterIdleTTL, at most once per TTL, so
+// callers who stop sending requests do not hold memory forever. Callers hold mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterIdleTTL {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > rateLimiterIdleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
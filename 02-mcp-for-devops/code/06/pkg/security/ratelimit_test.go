@@ -0,0 +1,46 @@
+package security
+
+import (
+	"errors"
+	"testing"
+
+	"kubernetes-mcp-server/pkg/rbac"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	limiter := NewRateLimiter(
+		RateLimit{RequestsPerSecond: 1, Burst: 2},
+		map[rbac.Permission]RateLimit{rbac.PermissionScaleDeployment: {RequestsPerSecond: 0.1, Burst: 1}},
+	)
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Allow("alice", rbac.PermissionListPods); err != nil {
+			t.Fatalf("request %d within burst refused: %v", i+1, err)
+		}
+	}
+	err := limiter.Allow("alice", rbac.PermissionGetPodLogs)
+	var limited *RateLimitError
+	if !errors.As(err, &limited) || limited.RetryAfter <= 0 {
+		t.Fatalf("request over the default burst returned %v", err)
+	}
+
+	// Permissions with their own limit and other callers have separate buckets
+	if err := limiter.Allow("alice", rbac.PermissionScaleDeployment); err != nil {
+		t.Errorf("first scale refused: %v", err)
+	}
+	if err := limiter.Allow("alice", rbac.PermissionScaleDeployment); err == nil {
+		t.Error("second scale allowed past its stricter limit")
+	}
+	if err := limiter.Allow("bob", rbac.PermissionListPods); err != nil {
+		t.Errorf("another caller was limited: %v", err)
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	limiter := NewRateLimiter(RateLimit{}, nil)
+	for i := 0; i < 100; i++ {
+		if err := limiter.Allow("alice", rbac.PermissionListPods); err != nil {
+			t.Fatalf("unlimited request refused: %v", err)
+		}
+	}
+}
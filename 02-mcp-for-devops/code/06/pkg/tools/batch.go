@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kubernetes-mcp-server/pkg/types"
+)
+
+// BatchToolName is the tool that runs a list of other tool calls
+const BatchToolName = "k8s_batch"
+
+// maxBatchOperations bounds the operations in one batch
+const maxBatchOperations = 20
+
+// OperationCheckFunc is called before each operation of a batch runs. An error fails the
+// operation without running it. It may update arguments, for example with an inferred
+// namespace.
+type OperationCheckFunc func(ctx context.Context, toolName string, arguments map[string]interface{}) error
+
+type operationCheckKey struct{}
+
+// WithOperationCheck returns a context that makes k8s_batch pass each operation to check,
+// such as an authorization check for the caller, before running it
+func WithOperationCheck(ctx context.Context, check OperationCheckFunc) context.Context {
+	return context.WithValue(ctx, operationCheckKey{}, check)
+}
+
+// batchOperation is one tool call of a batch
+type batchOperation struct {
+	tool      string
+	arguments map[string]interface{}
+}
+
+// batchOperations reads the operations of a k8s_batch call, which the schema has checked
+func batchOperations(inputs map[string]interface{}) []batchOperation {
+	items, _ := inputs["operations"].([]interface{})
+	operations := make([]batchOperation, 0, len(items))
+	for _, item := range items {
+		operation, _ := item.(map[string]interface{})
+		tool, _ := operation["tool"].(string)
+		arguments, _ := operation["arguments"].(map[string]interface{})
+		if arguments == nil {
+			arguments = map[string]interface{}{}
+		}
+		operations = append(operations, batchOperation{tool: tool, arguments: arguments})
+	}
+	return operations
+}
+
+// executeBatch runs each operation in turn and reports each one's result. A failed
+// operation doesn't stop the rest unless stopOnError is set, in which case the remaining
+// operations are skipped.
+func (e *ToolExecutor) executeBatch(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	operations := batchOperations(inputs)
+	stopOnError, _ := inputs["stopOnError"].(bool)
+	check, _ := ctx.Value(operationCheckKey{}).(OperationCheckFunc)
+
+	results := make([]map[string]interface{}, 0, len(operations))
+	succeeded, failed := 0, 0
+	for i, operation := range operations {
+		result := e.executeBatchOperation(ctx, check, operation)
+
+		entry := map[string]interface{}{
+			"index":   i,
+			"tool":    operation.tool,
+			"success": result.Success,
+			"message": result.Message,
+		}
+		if result.Success {
+			succeeded++
+			entry["data"] = result.Data
+		} else {
+			failed++
+			entry["error"] = result.Error
+			entry["code"] = result.Code
+		}
+		results = append(results, entry)
+
+		if !result.Success && stopOnError {
+			break
+		}
+	}
+	skipped := len(operations) - len(results)
+
+	message := fmt.Sprintf("Ran %d of %d operations: %d succeeded, %d failed", len(results), len(operations), succeeded, failed)
+	if skipped > 0 {
+		message += fmt.Sprintf(", %d skipped after the first failure", skipped)
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"allSucceeded": failed == 0 && skipped == 0,
+			"succeeded":    succeeded,
+			"failed":       failed,
+			"skipped":      skipped,
+			"results":      results,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeBatchOperation checks and runs one operation of a batch
+func (e *ToolExecutor) executeBatchOperation(ctx context.Context, check OperationCheckFunc, operation batchOperation) *ExecuteResult {
+	if check != nil {
+		if err := check(ctx, operation.tool, operation.arguments); err != nil {
+			return &ExecuteResult{
+				Success:   false,
+				Message:   "Operation rejected",
+				Error:     err.Error(),
+				Code:      types.ErrorCode(err),
+				Timestamp: time.Now(),
+			}
+		}
+	}
+	return e.ExecuteTool(ctx, operation.tool, operation.arguments)
+}
+
+// batchTimeout is the time a batch may take: the sum of its operations' timeouts
+func (e *ToolExecutor) batchTimeout(inputs map[string]interface{}) time.Duration {
+	var timeout time.Duration
+	for _, operation := range batchOperations(inputs) {
+		timeout += e.toolTimeout(operation.tool, operation.arguments)
+	}
+	return timeout
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/pkg/preflight"
+	"kubernetes-mcp-server/pkg/types"
+)
+
+func TestBatchReportsEachOperation(t *testing.T) {
+	executor := NewToolExecutor(nil, logging.NewLogger("error", "text"))
+	executor.SetPreflight(func(ctx context.Context) *preflight.Report {
+		return &preflight.Report{OK: true}
+	})
+	// Deny anything in kube-system, as RBAC would for most callers
+	ctx := WithOperationCheck(context.Background(), func(ctx context.Context, toolName string, arguments map[string]interface{}) error {
+		if arguments["namespace"] == "kube-system" {
+			return types.NewError(types.ErrorCodeForbidden, "access denied")
+		}
+		return nil
+	})
+	operations := []interface{}{
+		map[string]interface{}{"tool": "server_preflight"},
+		map[string]interface{}{"tool": "k8s_delete_pod", "arguments": map[string]interface{}{"namespace": "kube-system", "name": "etcd", "confirm": true}},
+		map[string]interface{}{"tool": "k8s_scale_deployment", "arguments": map[string]interface{}{"namespace": "default", "name": "web", "replicas": 500, "confirm": true}},
+		map[string]interface{}{"tool": "server_preflight"},
+	}
+
+	result := executor.ExecuteTool(ctx, BatchToolName, map[string]interface{}{"operations": operations})
+	if !result.Success {
+		t.Fatalf("batch failed: %s", result.Error)
+	}
+	if result.Data["succeeded"] != 2 || result.Data["failed"] != 2 || result.Data["allSucceeded"] != false {
+		t.Fatalf("got %v, want 2 succeeded and 2 failed", result.Data)
+	}
+	results := result.Data["results"].([]map[string]interface{})
+	if results[1]["code"] != types.ErrorCodeForbidden || results[2]["code"] != types.ErrorCodeInvalidParams {
+		t.Errorf("got codes %v and %v, want forbidden and invalid params", results[1]["code"], results[2]["code"])
+	}
+
+	result = executor.ExecuteTool(ctx, BatchToolName, map[string]interface{}{"operations": operations, "stopOnError": true})
+	if result.Data["succeeded"] != 1 || result.Data["failed"] != 1 || result.Data["skipped"] != 2 {
+		t.Fatalf("with stopOnError got %v, want 1 succeeded, 1 failed and 2 skipped", result.Data)
+	}
+
+	nested := []interface{}{map[string]interface{}{"tool": BatchToolName, "arguments": map[string]interface{}{"operations": operations}}}
+	if result := executor.ExecuteTool(ctx, BatchToolName, map[string]interface{}{"operations": nested}); result.Success {
+		t.Error("nested batch passed validation")
+	}
+}
@@ -2,6 +2,43 @@ package tools
 
 import "github.com/mark3labs/mcp-go/mcp"
 
+// verbosityProperty is the shared schema for the optional verbosity input
+var verbosityProperty = map[string]interface{}{
+	"type":        "string",
+	"description": "Level of detail: summary (status and counts only), normal, or detailed (full container info, conditions, and events). Defaults to normal",
+	"enum":        []string{"summary", "normal", "detailed"},
+	"default":     "normal",
+}
+
+// dryRunProperty is the shared schema for the optional dryRun input of mutating tools
+var dryRunProperty = map[string]interface{}{
+	"type":        "boolean",
+	"description": "Preview the change with a server-side dry run: the API server validates it and returns the result without applying it (optional)",
+	"default":     false,
+}
+
+// binaryDataProperty is the schema for the optional binaryData input of kind, for values
+// such as certificates and keystores that are not UTF-8 text
+func binaryDataProperty(kind string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "Key-value pairs for binary " + kind + " data, each value base64-encoded (optional; set data, binaryData, or both, without repeating a key)",
+		"additionalProperties": map[string]interface{}{
+			"type":            "string",
+			"contentEncoding": "base64",
+		},
+	}
+}
+
+// secretTypes are the secret types k8s_create_secret accepts
+var secretTypes = []string{
+	"Opaque",
+	"kubernetes.io/basic-auth",
+	"kubernetes.io/ssh-auth",
+	"kubernetes.io/tls",
+	"kubernetes.io/dockerconfigjson",
+}
+
 func GetToolDefinitions() []mcp.Tool {
 	return []mcp.Tool{
 		{
@@ -14,11 +51,13 @@ func GetToolDefinitions() []mcp.Tool {
 						"type":        "string",
 						"description": "Kubernetes namespace containing the deployment",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
 					},
 					"name": map[string]interface{}{
 						"type":        "string",
 						"description": "Name of the deployment to scale",
-						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
 					},
 					"replicas": map[string]interface{}{
 						"type":        "integer",
@@ -26,6 +65,7 @@ func GetToolDefinitions() []mcp.Tool {
 						"minimum":     0,
 						"maximum":     100,
 					},
+					"dryRun": dryRunProperty,
 					"confirm": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Confirmation that you want to perform this scaling operation",
@@ -45,12 +85,15 @@ func GetToolDefinitions() []mcp.Tool {
 						"type":        "string",
 						"description": "Kubernetes namespace containing the deployment",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
 					},
 					"name": map[string]interface{}{
 						"type":        "string",
 						"description": "Name of the deployment to restart",
-						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
 					},
+					"dryRun": dryRunProperty,
 					"confirm": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Confirmation that you want to restart this deployment",
@@ -70,16 +113,19 @@ func GetToolDefinitions() []mcp.Tool {
 						"type":        "string",
 						"description": "Kubernetes namespace containing the pod",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
 					},
 					"name": map[string]interface{}{
 						"type":        "string",
 						"description": "Name of the pod to get logs from",
-						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
 					},
 					"container": map[string]interface{}{
 						"type":        "string",
-						"description": "Container name (optional, defaults to first container)",
+						"description": "Container name (optional, defaults to the pod's main container, skipping service mesh sidecars)",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
 					},
 					"tailLines": map[string]interface{}{
 						"type":        "integer",
@@ -94,6 +140,32 @@ func GetToolDefinitions() []mcp.Tool {
 						"minimum":     1,
 						"maximum":     86400, // 24 hours max
 					},
+					"allContainers": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return logs from every container, each under a '=== container: name ===' header. tailLines applies per container (optional)",
+						"default":     false,
+					},
+					"includeSidecars": map[string]interface{}{
+						"type":        "boolean",
+						"description": "With allContainers, also return logs from service mesh sidecars such as istio-proxy, which are skipped by default (optional)",
+						"default":     false,
+					},
+					"previous": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return logs from the container's previous terminated instance, e.g. to see why a crash-looping container exited (optional)",
+						"default":     false,
+					},
+					"follow": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Keep streaming new log lines until followSeconds elapse or the container exits. Lines are sent as progress notifications when the request carries a progress token (optional)",
+						"default":     false,
+					},
+					"followSeconds": map[string]interface{}{
+						"type":        "integer",
+						"description": "How long to follow logs in follow mode (optional, defaults to 30)",
+						"minimum":     1,
+						"maximum":     300,
+					},
 				},
 				Required: []string{"namespace", "name"},
 			},
@@ -108,19 +180,22 @@ func GetToolDefinitions() []mcp.Tool {
 						"type":        "string",
 						"description": "Kubernetes namespace for the ConfigMap",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
 					},
 					"name": map[string]interface{}{
 						"type":        "string",
 						"description": "Name of the ConfigMap",
-						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
 					},
 					"data": map[string]interface{}{
 						"type":        "object",
-						"description": "Key-value pairs for the ConfigMap data",
+						"description": "Key-value pairs for the ConfigMap data, as UTF-8 text",
 						"additionalProperties": map[string]interface{}{
 							"type": "string",
 						},
 					},
+					"binaryData": binaryDataProperty("ConfigMap"),
 					"labels": map[string]interface{}{
 						"type":        "object",
 						"description": "Labels to apply to the ConfigMap (optional)",
@@ -128,8 +203,9 @@ func GetToolDefinitions() []mcp.Tool {
 							"type": "string",
 						},
 					},
+					"dryRun": dryRunProperty,
 				},
-				Required: []string{"namespace", "name", "data"},
+				Required: []string{"namespace", "name"},
 			},
 		},
 		{
@@ -138,10 +214,26 @@ func GetToolDefinitions() []mcp.Tool {
 			InputSchema: mcp.ToolInputSchema{
 				Type: "object",
 				Properties: map[string]interface{}{
+					"verbosity": verbosityProperty,
 					"namespace": map[string]interface{}{
 						"type":        "string",
 						"description": "Kubernetes namespace to list pods from",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"fieldSelector": map[string]interface{}{
+						"type":        "string",
+						"description": "Only list pods matching this field selector, e.g. 'status.phase=Running' or 'spec.nodeName=node-1' (optional)",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of pods to return. When more remain, the result includes a continueToken. Without a limit at most 100 pods are returned and truncated is set when more exist (optional)",
+						"minimum":     1,
+						"maximum":     1000,
+					},
+					"continue": map[string]interface{}{
+						"type":        "string",
+						"description": "continueToken returned by a previous call to fetch the next page (optional)",
 					},
 				},
 				Required: []string{"namespace"},
@@ -157,17 +249,20 @@ func GetToolDefinitions() []mcp.Tool {
 						"type":        "string",
 						"description": "Kubernetes namespace containing the pod",
 						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
 					},
 					"name": map[string]interface{}{
 						"type":        "string",
 						"description": "Name of the pod to delete",
-						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
 					},
 					"force": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Force delete the pod immediately (optional)",
 						"default":     false,
 					},
+					"dryRun": dryRunProperty,
 					"confirm": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Confirmation that you want to delete this pod",
@@ -177,5 +272,1024 @@ func GetToolDefinitions() []mcp.Tool {
 				Required: []string{"namespace", "name", "confirm"},
 			},
 		},
+		{
+			Name:        "k8s_delete_deployment",
+			Description: "Delete a Kubernetes deployment, reporting how many pods it owned (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the deployment",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the deployment to delete",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"cascade": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Delete the deployment's ReplicaSets and pods before the deployment itself; set to false to orphan them so they keep running (optional)",
+						"default":     true,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Confirmation that you want to delete this deployment",
+						"const":       true,
+					},
+				},
+				Required: []string{"namespace", "name", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_list_roles",
+			Description: "List Kubernetes RBAC Roles in a namespace, optionally including ClusterRoles, with their rules",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"verbosity": verbosityProperty,
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to list roles from",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"includeClusterRoles": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also list cluster-wide ClusterRoles (optional)",
+						"default":     false,
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "k8s_list_rolebindings",
+			Description: "List Kubernetes RBAC RoleBindings in a namespace, optionally including ClusterRoleBindings, with their subjects",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"verbosity": verbosityProperty,
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to list role bindings from",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"includeClusterBindings": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also list cluster-wide ClusterRoleBindings (optional)",
+						"default":     false,
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "k8s_who_can",
+			Description: "List the users, groups, and service accounts allowed to perform a verb on a resource in a namespace, based on Kubernetes RBAC bindings",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to check access in",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"verb": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes API verb (e.g. get, list, create, delete)",
+						"pattern":     "^[a-z]+$",
+					},
+					"resource": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes resource, optionally with subresource (e.g. pods, deployments, pods/exec)",
+						"pattern":     "^[a-z0-9.]+(/[a-z0-9]+)?$",
+					},
+					"apiGroup": map[string]interface{}{
+						"type":        "string",
+						"description": "API group of the resource (optional, e.g. apps). Omit to match any group",
+					},
+				},
+				Required: []string{"namespace", "verb", "resource"},
+			},
+		},
+		{
+			Name:        "k8s_search_pods",
+			Description: "Search pods across all namespaces by label selector, name, or phase. Results are paged: pass the returned cursor to continue where the previous call stopped",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"verbosity": verbosityProperty,
+					"labelSelector": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes label selector (optional, e.g. app=web,tier!=cache)",
+					},
+					"nameContains": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return pods whose name contains this substring (optional)",
+					},
+					"phase": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return pods in this phase (optional)",
+						"enum":        []string{"Pending", "Running", "Succeeded", "Failed", "Unknown"},
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of pods to return in this page (optional, defaults to 100)",
+						"minimum":     1,
+						"maximum":     500,
+						"default":     100,
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "Opaque cursor returned by a previous call to fetch the next page (optional)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "k8s_validate_manifest",
+			Description: "Validate a Kubernetes YAML or JSON manifest with a server-side dry-run and strict field validation, reporting unknown fields and schema errors without creating anything",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Namespace used for namespaced objects that do not set one",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"manifest": map[string]interface{}{
+						"type":        "string",
+						"description": "Manifest to validate; multiple YAML documents separated by --- are supported",
+						"maxLength":   1048576,
+					},
+				},
+				Required: []string{"namespace", "manifest"},
+			},
+		},
+		{
+			Name:        "k8s_pod_services",
+			Description: "List the services whose selectors match a pod's labels, i.e. which services route traffic to the pod",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the pod",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the pod",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        "k8s_wait_for_job",
+			Description: "Wait for a Kubernetes Job to complete or fail, returning its final status, pod counts, and on failure the reason and the failed pod's last log lines",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the job",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the job to wait for",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"timeoutSeconds": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum time to wait in seconds (optional, defaults to 300)",
+						"minimum":     1,
+						"maximum":     3600,
+						"default":     300,
+					},
+					"tailLines": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of log lines to return from the failed pod (optional, defaults to 50)",
+						"minimum":     1,
+						"maximum":     1000,
+						"default":     50,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        "k8s_wait_for_log",
+			Description: "Follow a pod's logs until a line matches a regular expression or a timeout elapses, returning the matching line with surrounding context",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the pod",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the pod to follow logs from",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"container": map[string]interface{}{
+						"type":        "string",
+						"description": "Container name (optional, defaults to the pod's main container, skipping service mesh sidecars)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"pattern": map[string]interface{}{
+						"type":        "string",
+						"description": "Regular expression (Go RE2 syntax) to match against each log line, e.g. \"listening on :8080\"",
+						"minLength":   1,
+					},
+					"timeoutSeconds": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum time to wait in seconds (optional, defaults to 60)",
+						"minimum":     1,
+						"maximum":     3600,
+						"default":     60,
+					},
+					"contextLines": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of lines to include before and after the match (optional, defaults to 5)",
+						"minimum":     0,
+						"maximum":     50,
+						"default":     5,
+					},
+				},
+				Required: []string{"namespace", "name", "pattern"},
+			},
+		},
+		{
+			Name:        "k8s_diagnose_job",
+			Description: "Diagnose a Kubernetes Job by collecting its status and events with its pods' statuses, last container termination reasons, events, and final log lines",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the job",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the job to diagnose",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"tailLines": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of log lines to return from each unsuccessful pod (optional, defaults to 50)",
+						"minimum":     1,
+						"maximum":     1000,
+						"default":     50,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        "k8s_diagnose_pod",
+			Description: "Diagnose a failing pod in one call: its status, restart count and crash-loop state, unmet conditions, last container termination reasons, recent events, and the final log lines of the failing container, from its previous instance if it restarted",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the pod",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the pod to diagnose",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"tailLines": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of log lines to return (optional, defaults to 50)",
+						"minimum":     1,
+						"maximum":     1000,
+						"default":     50,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        "k8s_find_missing_probes",
+			Description: "Find deployment containers that lack a readiness and/or liveness probe, in one namespace or across the cluster. Results are reported per container and paged: pass the returned cursor to continue where the previous call stopped",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to scan (optional, defaults to all namespaces)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of deployments to scan in this page (optional, defaults to 100)",
+						"minimum":     1,
+						"maximum":     500,
+						"default":     100,
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "Opaque cursor returned by a previous call to fetch the next page (optional)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "k8s_image_audit",
+			Description: "List the container images used by a namespace's deployments, statefulsets, and daemonsets, grouped by image with the workloads using each, flagging :latest and untagged images",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to audit",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"flaggedOnly": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Only return :latest and untagged images (optional, defaults to false)",
+						"default":     false,
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "server_preflight",
+			Description: "Check that the server configuration is coherent: cluster reachable, RBAC policy valid, JWT secret changed from the demo value, TLS certificates loadable, and audit sinks writable",
+			InputSchema: mcp.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "server_create_api_key",
+			Description: "Create an API key for this server with the given permissions. The key is returned once and cannot be retrieved later. Admin only",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Descriptive name for the key, such as the team or pipeline using it",
+						"pattern":     "^[A-Za-z0-9][A-Za-z0-9 ._-]{0,62}$",
+					},
+					"permissions": map[string]interface{}{
+						"type":        "array",
+						"description": "Permissions to grant, e.g. [\"k8s:pods:list\", \"k8s:pods:logs\"]",
+						"minItems":    1,
+						"items": map[string]interface{}{
+							"type":    "string",
+							"pattern": "^[a-z0-9]+(:([a-z0-9_-]+|\\*))*$",
+						},
+					},
+					"ttlHours": map[string]interface{}{
+						"type":        "integer",
+						"description": "Hours until the key expires (optional, defaults to 720, 30 days)",
+						"minimum":     1,
+						"maximum":     8760,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm creating the key",
+						"const":       true,
+					},
+				},
+				Required: []string{"name", "permissions", "confirm"},
+			},
+		},
+		{
+			Name:        "server_issue_token",
+			Description: "Issue a signed JWT access token for a user with the given permissions, e.g. to bootstrap access for a new AI client. The token is returned once and cannot be retrieved later. Requires the full k8s:* permission",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"username": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the user or client the token identifies",
+						"pattern":     "^[A-Za-z0-9][A-Za-z0-9@._-]{0,62}$",
+					},
+					"userId": map[string]interface{}{
+						"type":        "string",
+						"description": "Stable ID of the user (optional, defaults to username)",
+						"pattern":     "^[A-Za-z0-9][A-Za-z0-9@._-]{0,62}$",
+					},
+					"permissions": map[string]interface{}{
+						"type":        "array",
+						"description": "Permissions or roles to grant, e.g. [\"k8s:pods:list\", \"role:viewer\"]",
+						"minItems":    1,
+						"items": map[string]interface{}{
+							"type":    "string",
+							"pattern": "^[a-z0-9]+(:([a-z0-9_-]+|\\*))*$",
+						},
+					},
+					"ttlMinutes": map[string]interface{}{
+						"type":        "integer",
+						"description": "Minutes until the token expires (optional, defaults to the server's auth.jwt.issuedTokenTTL, 60 unless configured)",
+						"minimum":     1,
+						"maximum":     43200,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm issuing the token",
+						"const":       true,
+					},
+				},
+				Required: []string{"username", "permissions", "confirm"},
+			},
+		},
+		{
+			Name:        "server_rotate_api_key",
+			Description: "Issue a replacement for an API key with the same name, permissions and lifetime, and expire the old key after a grace period so clients can switch over. The new key is returned once. Admin only",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"keyId": map[string]interface{}{
+						"type":        "string",
+						"description": "ID of the key to rotate",
+						"pattern":     "^[A-Za-z0-9._-]{1,63}$",
+					},
+					"graceMinutes": map[string]interface{}{
+						"type":        "integer",
+						"description": "Minutes the old key keeps working (optional, defaults to 60; 0 expires it at once)",
+						"minimum":     0,
+						"maximum":     10080,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm the rotation",
+						"const":       true,
+					},
+				},
+				Required: []string{"keyId", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_exec_pod",
+			Description: "Run a diagnostic command such as 'cat /etc/resolv.conf' in a pod's container and return its combined output. Only allowlisted commands may run, without a shell. Requires confirmation",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the pod",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the pod",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"container": map[string]interface{}{
+						"type":        "string",
+						"description": "Container name (optional, defaults to the pod's default container)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"command": map[string]interface{}{
+						"type":        "array",
+						"description": "Command and arguments, e.g. [\"cat\", \"/etc/resolv.conf\"]. The first element must be an allowed command",
+						"items":       map[string]interface{}{"type": "string"},
+						"minItems":    1,
+					},
+					"timeoutSeconds": map[string]interface{}{
+						"type":        "integer",
+						"description": "How long the command may run (optional, defaults to 30)",
+						"minimum":     1,
+						"maximum":     300,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm running the command",
+						"const":       true,
+					},
+				},
+				Required: []string{"namespace", "name", "command", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_rollout_status",
+			Description: "Check whether a deployment's latest rollout is complete, still progressing, or stalled, e.g. after a restart or scale",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the deployment",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the deployment",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        "k8s_rollout_history",
+			Description: "List a deployment's rollout revisions with their images, change cause, and creation time",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the deployment",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the deployment",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        "k8s_rollback_deployment",
+			Description: "Roll a deployment back to an earlier revision by restoring that revision's pod template (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the deployment",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the deployment to roll back",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"revision": map[string]interface{}{
+						"type":        "integer",
+						"description": "Revision to roll back to, as listed by k8s_rollout_history (optional, defaults to the previous revision)",
+						"minimum":     1,
+						"maximum":     2147483647,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm the rollback",
+						"const":       true,
+					},
+				},
+				Required: []string{"namespace", "name", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_describe",
+			Description: "Describe any Kubernetes resource, including custom resources. Modelled types return a summarized view; other types return the full object as JSON with its recent events",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource type as accepted by kubectl: a kind (Deployment), resource (deployments), short name (deploy), or group-qualified name (certificates.cert-manager.io)",
+						"pattern":     "^[A-Za-z][A-Za-z0-9]*(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the resource (required for namespaced kinds, omitted for cluster-scoped ones)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the resource",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+				},
+				Required: []string{"kind", "name"},
+			},
+		},
+		{
+			Name:        "k8s_list_deployments",
+			Description: "List deployments in a Kubernetes namespace with their ready replicas and rollout strategy",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to list deployments from",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of deployments to return. When more remain, the result includes a continueToken (optional, defaults to all deployments)",
+						"minimum":     1,
+						"maximum":     1000,
+					},
+					"continue": map[string]interface{}{
+						"type":        "string",
+						"description": "continueToken returned by a previous call to fetch the next page (optional)",
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "k8s_list_services",
+			Description: "List services in a Kubernetes namespace with their type, cluster IP and ports",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to list services from",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "k8s_list_namespaces",
+			Description: "List all namespaces in the cluster with their status and age. A good first call to orient yourself in an unfamiliar cluster",
+			InputSchema: mcp.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "k8s_cluster_info",
+			Description: "Describe the cluster: Kubernetes version, node count, total capacity and allocatable resources, served API groups, and whether metrics-server and common add-ons such as cert-manager or Istio are installed. Call it to check which tools, like k8s_top_pods, can work on this cluster",
+			InputSchema: mcp.ToolInputSchema{
+				Type:       "object",
+				Properties: map[string]interface{}{},
+			},
+		},
+		{
+			Name:        "k8s_top_pods",
+			Description: "Show current CPU and memory usage of the pods in a namespace, highest first, e.g. to find which pod uses the most memory. Requires metrics-server",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to show pod usage for",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"sortBy": map[string]interface{}{
+						"type":        "string",
+						"description": "Sort by highest cpu or memory usage first (optional, defaults to memory)",
+						"enum":        []string{"cpu", "memory"},
+						"default":     "memory",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Return only the top N pods (optional, defaults to all pods)",
+						"minimum":     1,
+						"maximum":     1000,
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "k8s_top_nodes",
+			Description: "Show current CPU and memory usage of every node, with percentages of allocatable resources. Requires metrics-server",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"sortBy": map[string]interface{}{
+						"type":        "string",
+						"description": "Sort by highest cpu or memory usage first (optional, defaults to memory)",
+						"enum":        []string{"cpu", "memory"},
+						"default":     "memory",
+					},
+				},
+			},
+		},
+		{
+			Name:        "k8s_cordon_node",
+			Description: "Mark a node unschedulable so no new pods are placed on it, or make it schedulable again with uncordon (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the node",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"uncordon": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Make the node schedulable again instead of cordoning it (optional, defaults to false)",
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm the change",
+						"const":       true,
+					},
+					"dryRun": dryRunProperty,
+				},
+				Required: []string{"name", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_drain_node",
+			Description: "Cordon a node and evict its pods, respecting PodDisruptionBudgets. DaemonSet pods, static pods, pods without a controller and pods with emptyDir data are skipped; the result lists evicted and skipped pods (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the node to drain",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"deleteEmptyDirData": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Also evict pods with emptyDir volumes, losing that data (optional, defaults to false)",
+					},
+					"gracePeriodSeconds": map[string]interface{}{
+						"type":        "integer",
+						"description": "Termination grace period for evicted pods (optional, defaults to each pod's own)",
+						"minimum":     0,
+						"maximum":     3600,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm the drain",
+						"const":       true,
+					},
+					"dryRun": dryRunProperty,
+				},
+				Required: []string{"name", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_patch_resource",
+			Description: "Patch any Kubernetes resource, including custom resources, with a JSON patch, JSON merge patch or strategic merge patch. Use it for edits no dedicated tool covers, such as environment variables (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource type as accepted by kubectl: a kind (Deployment), resource (deployments), short name (deploy), or group-qualified name (certificates.cert-manager.io)",
+						"pattern":     "^[A-Za-z][A-Za-z0-9]*(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the resource (required for namespaced kinds, omitted for cluster-scoped ones)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the resource",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"patchType": map[string]interface{}{
+						"type":        "string",
+						"description": "json for a JSON patch (RFC 6902), merge for a JSON merge patch (RFC 7386), or strategic for a strategic merge patch (built-in types only)",
+						"enum":        []string{"json", "merge", "strategic"},
+					},
+					"patch": map[string]interface{}{
+						"type":        "string",
+						"description": "The patch document as JSON: an array of operations for json, an object for merge and strategic",
+						"maxLength":   1048576,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm the patch",
+						"const":       true,
+					},
+					"dryRun": dryRunProperty,
+				},
+				Required: []string{"kind", "name", "patchType", "patch", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_label_resource",
+			Description: "Add, change or remove labels on any Kubernetes resource except secrets, without writing a patch. Changing labels a selector matches can move pods out of a Service or make a controller replace them (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource type as accepted by kubectl: a kind (Deployment), resource (deployments), short name (deploy), or group-qualified name (certificates.cert-manager.io)",
+						"pattern":     "^[A-Za-z][A-Za-z0-9]*(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the resource (required for namespaced kinds, omitted for cluster-scoped ones)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the resource",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"labels": map[string]interface{}{
+						"type":          "object",
+						"description":   "Labels to set, e.g. {\"tier\": \"backend\"}. A null value removes the label; labels not listed are left alone",
+						"minProperties": 1,
+						"additionalProperties": map[string]interface{}{
+							"type":      []string{"string", "null"},
+							"pattern":   "^(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?$",
+							"maxLength": 63,
+						},
+					},
+					"dryRun": dryRunProperty,
+				},
+				Required: []string{"kind", "name", "labels"},
+			},
+		},
+		{
+			Name:        "k8s_annotate_resource",
+			Description: "Add, change or remove annotations on any Kubernetes resource except secrets, without writing a patch",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"kind": map[string]interface{}{
+						"type":        "string",
+						"description": "Resource type as accepted by kubectl: a kind (Deployment), resource (deployments), short name (deploy), or group-qualified name (certificates.cert-manager.io)",
+						"pattern":     "^[A-Za-z][A-Za-z0-9]*(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+					},
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the resource (required for namespaced kinds, omitted for cluster-scoped ones)",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the resource",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"annotations": map[string]interface{}{
+						"type":          "object",
+						"description":   "Annotations to set, e.g. {\"team\": \"payments\"}. A null value removes the annotation; annotations not listed are left alone",
+						"minProperties": 1,
+						"additionalProperties": map[string]interface{}{
+							"type": []string{"string", "null"},
+						},
+					},
+					"dryRun": dryRunProperty,
+				},
+				Required: []string{"kind", "name", "annotations"},
+			},
+		},
+		{
+			Name:        "k8s_list_secrets",
+			Description: "List Kubernetes Secrets in a namespace with their type and key names. Secret values are never returned",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace to list secrets from",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+				},
+				Required: []string{"namespace"},
+			},
+		},
+		{
+			Name:        "k8s_create_secret",
+			Description: "Create or update a Kubernetes Secret. Values are write-only: the result lists the key names but never the values (use with caution)",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace for the secret",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the secret",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+					"data": map[string]interface{}{
+						"type":        "object",
+						"description": "Key-value pairs for the secret data, as plain strings (they are base64-encoded for you)",
+						"additionalProperties": map[string]interface{}{
+							"type": "string",
+						},
+					},
+					"binaryData": binaryDataProperty("secret"),
+					"type": map[string]interface{}{
+						"type":        "string",
+						"description": "Secret type (optional, defaults to Opaque)",
+						"enum":        secretTypes,
+					},
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Must be set to true to confirm writing the secret",
+						"const":       true,
+					},
+					"dryRun": dryRunProperty,
+				},
+				Required: []string{"namespace", "name", "confirm"},
+			},
+		},
+		{
+			Name:        "k8s_get_secret",
+			Description: "Describe a Kubernetes Secret by its type, key names, and the byte length of each value. Secret values are never returned",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"namespace": map[string]interface{}{
+						"type":        "string",
+						"description": "Kubernetes namespace containing the secret",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$",
+						"maxLength":   63,
+					},
+					"name": map[string]interface{}{
+						"type":        "string",
+						"description": "Name of the secret",
+						"pattern":     "^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$",
+						"maxLength":   253,
+					},
+				},
+				Required: []string{"namespace", "name"},
+			},
+		},
+		{
+			Name:        BatchToolName,
+			Description: "Run several tool calls in one request, such as restarting a set of deployments. Operations run in order and each is authorized on its own, so a denied or failed operation doesn't block the rest unless stopOnError is set. Returns each operation's result and whether all succeeded",
+			InputSchema: mcp.ToolInputSchema{
+				Type: "object",
+				Properties: map[string]interface{}{
+					"operations": map[string]interface{}{
+						"type":        "array",
+						"description": "Tool calls to run, e.g. [{\"tool\": \"k8s_restart_deployment\", \"arguments\": {\"namespace\": \"default\", \"name\": \"web\", \"confirm\": true}}]",
+						"minItems":    1,
+						"maxItems":    maxBatchOperations,
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"tool": map[string]interface{}{
+									"type":        "string",
+									"description": "Name of the tool to call",
+								},
+								"arguments": map[string]interface{}{
+									"type":        "object",
+									"description": "Arguments of the tool call",
+								},
+							},
+							"required": []string{"tool"},
+						},
+					},
+					"stopOnError": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Skip the remaining operations after the first failure (optional, defaults to false)",
+						"default":     false,
+					},
+				},
+				Required: []string{"operations"},
+			},
+		},
 	}
 }
@@ -1,41 +1,167 @@
 package tools
 
 import (
+	"bufio"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/internal/tracing"
+	"kubernetes-mcp-server/pkg/auth"
 	"kubernetes-mcp-server/pkg/k8s"
+	"kubernetes-mcp-server/pkg/preflight"
+	"kubernetes-mcp-server/pkg/types"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 )
 
+var tracer = tracing.Tracer("kubernetes-mcp-server/pkg/tools")
+
 type ToolExecutor struct {
 	k8sClient *k8s.Client
 	validator *Validator
 	logger    *logging.Logger
+	preflight func(ctx context.Context) *preflight.Report
+	apiKeys   auth.ManagedAPIKeyStore
+	// tokens signs the tokens server_issue_token returns, valid for tokenTTL unless the
+	// call says otherwise; nil when the server cannot issue tokens
+	tokens     auth.TokenIssuer
+	tokenTTL   time.Duration
+	timeout    time.Duration
+	logTimeout time.Duration
+	// logSidecars are skipped when a log tool names no container
+	logSidecars []string
 }
 
 func NewToolExecutor(k8sClient *k8s.Client, logger *logging.Logger) *ToolExecutor {
 	return &ToolExecutor{
-		k8sClient: k8sClient,
-		validator: NewValidator(),
-		logger:    logger,
+		k8sClient:  k8sClient,
+		validator:  NewValidator(),
+		logger:     logger,
+		timeout:    defaultToolTimeout,
+		logTimeout: defaultLogToolTimeout,
+	}
+}
+
+// SetTimeouts sets how long a tool call may run, and how long log retrieval may run
+// instead. A zero duration keeps the current value.
+func (e *ToolExecutor) SetTimeouts(timeout, logTimeout time.Duration) {
+	if timeout > 0 {
+		e.timeout = timeout
+	}
+	if logTimeout > 0 {
+		e.logTimeout = logTimeout
 	}
 }
 
+// SetPreflight sets the configuration checks run by the server_preflight tool
+func (e *ToolExecutor) SetPreflight(run func(ctx context.Context) *preflight.Report) {
+	e.preflight = run
+}
+
+// SetAPIKeyStore sets the store the API key tools issue and rotate keys in
+func (e *ToolExecutor) SetAPIKeyStore(store auth.ManagedAPIKeyStore) {
+	e.apiKeys = store
+}
+
+// SetTokenIssuer sets the issuer server_issue_token signs tokens with, and the lifetime
+// of tokens minted without ttlMinutes
+func (e *ToolExecutor) SetTokenIssuer(issuer auth.TokenIssuer, defaultTTL time.Duration) {
+	e.tokens = issuer
+	e.tokenTTL = defaultTTL
+}
+
+// LogLineFunc receives each line of a followed log stream as it arrives
+type LogLineFunc func(line string)
+
+type logStreamKey struct{}
+
+// WithLogStream returns a context that makes k8s_get_pod_logs in follow mode pass each
+// line to fn as it is read, in addition to returning all lines in the result
+func WithLogStream(ctx context.Context, fn LogLineFunc) context.Context {
+	return context.WithValue(ctx, logStreamKey{}, fn)
+}
+
+const (
+	// defaultAPIKeyTTL is how long a key created without ttlHours lasts
+	defaultAPIKeyTTL = 30 * 24 * time.Hour
+	// defaultAPIKeyRotationGrace is how long a rotated key keeps working by default
+	defaultAPIKeyRotationGrace = time.Hour
+	// defaultToolTimeout bounds a tool call unless configured otherwise
+	defaultToolTimeout = 30 * time.Second
+	// defaultLogToolTimeout bounds log retrieval, which can be slow for chatty pods
+	defaultLogToolTimeout = 2 * time.Minute
+	// defaultWaitForJobSeconds, defaultWaitForLogSeconds and defaultExecSeconds are the
+	// waits used when the corresponding tool is called without timeoutSeconds
+	defaultWaitForJobSeconds = 300
+	defaultWaitForLogSeconds = 60
+	defaultExecSeconds       = 30
+	// defaultFollowSeconds is how long follow mode streams logs when no duration is given
+	defaultFollowSeconds = 30
+	// maxFollowedLogBytes caps the log output a follow keeps for its final result
+	maxFollowedLogBytes = 1 << 20
+	// maxFollowedLogLineBytes is the longest single line a follow reads before it stops
+	maxFollowedLogLineBytes = 1 << 20
+	// defaultPodListLimit caps the pods k8s_list_pods returns when called without a limit,
+	// so a busy namespace doesn't flood the caller's context
+	defaultPodListLimit = 100
+)
+
+// SetExecAllowedCommands sets the executables the k8s_exec_pod tool may run
+func (e *ToolExecutor) SetExecAllowedCommands(commands []string) {
+	e.validator.SetExecAllowedCommands(commands)
+}
+
+// SetLogSidecarContainers sets the sidecar containers, such as service mesh proxies, that
+// log tools skip when no container is named
+func (e *ToolExecutor) SetLogSidecarContainers(containers []string) {
+	e.logSidecars = containers
+}
+
+// SetAllowedNamespaces restricts the namespaces tools may target. An empty list allows
+// any namespace.
+func (e *ToolExecutor) SetAllowedNamespaces(namespaces []string) {
+	e.validator.SetAllowedNamespaces(namespaces)
+}
+
 // ExecuteResult represents the result of tool execution
 type ExecuteResult struct {
 	Success   bool                   `json:"success"`
 	Message   string                 `json:"message"`
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Error     string                 `json:"error,omitempty"`
+	Code      int                    `json:"code,omitempty"` // one of the types.ErrorCode* values when Success is false
 	Timestamp time.Time              `json:"timestamp"`
 }
 
 // ExecuteTool executes the specified tool with the provided input
 func (e *ToolExecutor) ExecuteTool(ctx context.Context, toolName string, inputs map[string]interface{}) *ExecuteResult {
+	ctx, span := tracer.Start(ctx, "ExecuteTool", trace.WithAttributes(attribute.String("mcp.tool", toolName)))
+	defer span.End()
+
+	result := e.executeTool(ctx, toolName, inputs)
+	if !result.Success {
+		span.SetAttributes(attribute.Int("mcp.error_code", result.Code))
+		span.SetStatus(codes.Error, result.Error)
+	}
+	return result
+}
+
+func (e *ToolExecutor) executeTool(ctx context.Context, toolName string, inputs map[string]interface{}) *ExecuteResult {
 	start := time.Now()
 
-	e.logger.LogMCPRequest("tool_call", toolName, inputs)
+	e.logger.LogMCPRequest(ctx, "tool_call", toolName, inputs)
 
 	// Validate input schema
 	validation := e.validator.ValidateToolInput(toolName, inputs)
@@ -44,13 +170,18 @@ func (e *ToolExecutor) ExecuteTool(ctx context.Context, toolName string, inputs
 			Success:   false,
 			Message:   "Input validation failed",
 			Error:     fmt.Sprintf("Validation errors: %v", validation.Errors),
+			Code:      types.ErrorCodeInvalidParams,
 			Timestamp: start,
 		}
-		e.logger.LogMCPResponse("tool_call", time.Since(start), fmt.Errorf("validation failed"))
+		e.logger.LogMCPResponse(ctx, "tool_call", time.Since(start), fmt.Errorf("validation failed"))
 
 		return result
 	}
 
+	timeout := e.toolTimeout(toolName, inputs)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	// Execute the tool based on its name
 	var result *ExecuteResult
 	switch toolName {
@@ -64,63 +195,175 @@ func (e *ToolExecutor) ExecuteTool(ctx context.Context, toolName string, inputs
 		result = e.executeCreateConfigMap(ctx, inputs)
 	case "k8s_delete_pod":
 		result = e.executeDeletePod(ctx, inputs)
+	case "k8s_delete_deployment":
+		result = e.executeDeleteDeployment(ctx, inputs)
 	case "k8s_list_pods":
 		result = e.executeListPods(ctx, inputs)
+	case "k8s_list_deployments":
+		result = e.executeListDeployments(ctx, inputs)
+	case "k8s_list_services":
+		result = e.executeListServices(ctx, inputs)
+	case "k8s_list_namespaces":
+		result = e.executeListNamespaces(ctx)
+	case "k8s_cluster_info":
+		result = e.executeClusterInfo(ctx)
+	case "k8s_top_pods":
+		result = e.executeTopPods(ctx, inputs)
+	case "k8s_top_nodes":
+		result = e.executeTopNodes(ctx, inputs)
+	case "k8s_list_roles":
+		result = e.executeListRoles(ctx, inputs)
+	case "k8s_list_rolebindings":
+		result = e.executeListRoleBindings(ctx, inputs)
+	case "k8s_who_can":
+		result = e.executeWhoCan(ctx, inputs)
+	case "k8s_search_pods":
+		result = e.executeSearchPods(ctx, inputs)
+	case "k8s_validate_manifest":
+		result = e.executeValidateManifest(ctx, inputs)
+	case "k8s_pod_services":
+		result = e.executePodServices(ctx, inputs)
+	case "k8s_wait_for_job":
+		result = e.executeWaitForJob(ctx, inputs)
+	case "k8s_wait_for_log":
+		result = e.executeWaitForLog(ctx, inputs)
+	case "k8s_diagnose_job":
+		result = e.executeDiagnoseJob(ctx, inputs)
+	case "k8s_diagnose_pod":
+		result = e.executeDiagnosePod(ctx, inputs)
+	case "k8s_find_missing_probes":
+		result = e.executeFindMissingProbes(ctx, inputs)
+	case "k8s_image_audit":
+		result = e.executeImageAudit(ctx, inputs)
+	case "server_preflight":
+		result = e.executeServerPreflight(ctx)
+	case "server_create_api_key":
+		result = e.executeCreateAPIKey(ctx, inputs)
+	case "server_rotate_api_key":
+		result = e.executeRotateAPIKey(ctx, inputs)
+	case "server_issue_token":
+		result = e.executeIssueToken(inputs)
+	case "k8s_exec_pod":
+		result = e.executeExecPod(ctx, inputs)
+	case "k8s_describe":
+		result = e.executeDescribe(ctx, inputs)
+	case "k8s_rollback_deployment":
+		result = e.executeRollbackDeployment(ctx, inputs)
+	case "k8s_rollout_status":
+		result = e.executeRolloutStatus(ctx, inputs)
+	case "k8s_rollout_history":
+		result = e.executeRolloutHistory(ctx, inputs)
+	case "k8s_cordon_node":
+		result = e.executeCordonNode(ctx, inputs)
+	case "k8s_drain_node":
+		result = e.executeDrainNode(ctx, inputs)
+	case "k8s_create_secret":
+		result = e.executeCreateSecret(ctx, inputs)
+	case "k8s_patch_resource":
+		result = e.executePatchResource(ctx, inputs)
+	case "k8s_label_resource":
+		result = e.executeEditMetadata(ctx, inputs, "labels")
+	case "k8s_annotate_resource":
+		result = e.executeEditMetadata(ctx, inputs, "annotations")
+	case "k8s_list_secrets":
+		result = e.executeListSecrets(ctx, inputs)
+	case "k8s_get_secret":
+		result = e.executeGetSecret(ctx, inputs)
+	case BatchToolName:
+		result = e.executeBatch(ctx, inputs)
 	default:
 		result = &ExecuteResult{
 			Success:   false,
 			Message:   "Unknown tool",
 			Error:     fmt.Sprintf("Tool '%s' is not supported", toolName),
+			Code:      types.ErrorCodeMethodNotFound,
 			Timestamp: start,
 		}
-		e.logger.LogMCPResponse("tool_call", time.Since(start), fmt.Errorf("unknown tool: %s", toolName))
+		e.logger.LogMCPResponse(ctx, "tool_call", time.Since(start), fmt.Errorf("unknown tool: %s", toolName))
+	}
+
+	// Whatever the tool returned after its deadline passed is the API call's context
+	// error, so report the timeout itself
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result = &ExecuteResult{
+			Success:   false,
+			Message:   fmt.Sprintf("%s operation timed out after %s", toolName, timeout),
+			Error:     fmt.Sprintf("operation timed out after %s", timeout),
+			Code:      types.ErrorCodeTimeout,
+			Timestamp: time.Now(),
+		}
+		e.logger.LogMCPResponse(ctx, "tool_call", time.Since(start), fmt.Errorf("%s timed out after %s", toolName, timeout))
 	}
 
 	return result
 }
 
+// toolTimeout returns how long a call to the tool may run. Tools that wait for a
+// caller-chosen time get that time on top of the usual timeout, so their own timeout
+// is reported first.
+func (e *ToolExecutor) toolTimeout(toolName string, inputs map[string]interface{}) time.Duration {
+	seconds := func(key string, fallback int) time.Duration {
+		return time.Duration(intInput(inputs, key, fallback)) * time.Second
+	}
+
+	switch toolName {
+	case "k8s_get_pod_logs":
+		if follow, _ := inputs["follow"].(bool); follow {
+			return e.logTimeout + seconds("followSeconds", defaultFollowSeconds)
+		}
+		return e.logTimeout
+	case "k8s_wait_for_log":
+		return e.logTimeout + seconds("timeoutSeconds", defaultWaitForLogSeconds)
+	case "k8s_wait_for_job":
+		return e.timeout + seconds("timeoutSeconds", defaultWaitForJobSeconds)
+	case "k8s_exec_pod":
+		return e.timeout + seconds("timeoutSeconds", defaultExecSeconds)
+	case BatchToolName:
+		return e.batchTimeout(inputs)
+	default:
+		return e.timeout
+	}
+}
+
 // executeScaleDeployment handles deployment scaling
 func (e *ToolExecutor) executeScaleDeployment(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
 	namespace := inputs["namespace"].(string)
 	name := inputs["name"].(string)
 
-	// Handle replicas as either int or float64
-	var replicas int32
-	switch v := inputs["replicas"].(type) {
-	case int:
-		replicas = int32(v)
-	case float64:
-		replicas = int32(v)
-	case int32:
-		replicas = v
-	default:
+	replicasInt, ok := toInt(inputs, "replicas")
+	if !ok {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Invalid replicas type",
-			Error:     fmt.Sprintf("replicas must be a number, got %T", v),
+			Error:     fmt.Sprintf("replicas must be a number, got %T", inputs["replicas"]),
+			Code:      types.ErrorCodeInvalidParams,
 			Timestamp: time.Now(),
 		}
 	}
+	replicas := int32(replicasInt)
+	dryRun, _ := inputs["dryRun"].(bool)
 
-	deployment, err := e.k8sClient.ScaleDeployment(ctx, namespace, name, replicas)
+	deployment, err := e.k8sClient.ScaleDeployment(ctx, namespace, name, replicas, dryRun)
 	if err != nil {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Failed to scale deployment",
 			Error:     err.Error(),
+			Code:      errorCode(err),
 			Timestamp: time.Now(),
 		}
 	}
 
 	return &ExecuteResult{
 		Success: true,
-		Message: fmt.Sprintf("Successfully scaled deployment %s/%s to %d replicas", namespace, name, replicas),
+		Message: fmt.Sprintf("Successfully scaled deployment %s/%s to %d replicas%s", namespace, name, replicas, dryRunNote(dryRun)),
 		Data: map[string]interface{}{
 			"namespace":      deployment.Namespace,
 			"name":           deployment.Name,
 			"targetReplicas": *deployment.Spec.Replicas,
 			"readyReplicas":  deployment.Status.ReadyReplicas,
 			"updatedAt":      deployment.ObjectMeta.CreationTimestamp.Time,
+			"dryRun":         dryRun,
 		},
 		Timestamp: time.Now(),
 	}
@@ -131,12 +374,15 @@ func (e *ToolExecutor) executeRestartDeployment(ctx context.Context, inputs map[
 	namespace := inputs["namespace"].(string)
 	name := inputs["name"].(string)
 
-	deployment, err := e.k8sClient.RestartDeployment(ctx, namespace, name)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	deployment, err := e.k8sClient.RestartDeployment(ctx, namespace, name, dryRun)
 	if err != nil {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Failed to restart deployment",
 			Error:     err.Error(),
+			Code:      errorCode(err),
 			Timestamp: time.Now(),
 		}
 	}
@@ -145,12 +391,13 @@ func (e *ToolExecutor) executeRestartDeployment(ctx context.Context, inputs map[
 
 	return &ExecuteResult{
 		Success: true,
-		Message: fmt.Sprintf("Successfully restarted deployment %s/%s", namespace, name),
+		Message: fmt.Sprintf("Successfully restarted deployment %s/%s%s", namespace, name, dryRunNote(dryRun)),
 		Data: map[string]interface{}{
 			"namespace":   deployment.Namespace,
 			"name":        deployment.Name,
 			"restartedAt": restartedAt,
 			"replicas":    *deployment.Spec.Replicas,
+			"dryRun":      dryRun,
 		},
 		Timestamp: time.Now(),
 	}
@@ -167,62 +414,56 @@ func (e *ToolExecutor) executeGetPodLogs(ctx context.Context, inputs map[string]
 		containerName = container.(string)
 	}
 
-	var tailLines *int64
-	if tl, exists := inputs["tailLines"]; exists {
-		lines := int64(tl.(float64))
-		tailLines = &lines
-	} else {
-		// Default to 100 lines
-		lines := int64(100)
-		tailLines = &lines
-	}
+	// Default to 100 lines
+	lines := int64(intInput(inputs, "tailLines", 100))
+	tailLines := &lines
 
 	var sinceSeconds *int64
-	if ss, exists := inputs["sinceSeconds"]; exists {
-		seconds := int64(ss.(float64))
+	if ss, ok := toInt(inputs, "sinceSeconds"); ok {
+		seconds := int64(ss)
 		sinceSeconds = &seconds
 	}
 
-	// If no container specified, get the first one
-	if containerName == "" {
-		containers, err := e.k8sClient.GetPodContainers(ctx, namespace, name)
-		if err != nil {
-			return &ExecuteResult{
-				Success:   false,
-				Message:   "Failed to get pod containers",
-				Error:     err.Error(),
-				Timestamp: time.Now(),
-			}
-		}
-		if len(containers) == 0 {
-			return &ExecuteResult{
-				Success:   false,
-				Message:   "Pod has no containers",
-				Error:     "No containers found in pod",
-				Timestamp: time.Now(),
-			}
-		}
-		containerName = containers[0]
+	if allContainers, _ := inputs["allContainers"].(bool); allContainers {
+		return e.executeGetAllPodLogs(ctx, namespace, name, tailLines, sinceSeconds, inputs)
+	}
+
+	containerName, failure := e.resolveLogContainer(ctx, namespace, name, containerName)
+	if failure != nil {
+		return failure
 	}
 
-	logs, err := e.k8sClient.GetPodLogs(ctx, namespace, name, containerName, tailLines, sinceSeconds)
+	if follow, _ := inputs["follow"].(bool); follow {
+		return e.followPodLogs(ctx, namespace, name, containerName, tailLines, intInput(inputs, "followSeconds", defaultFollowSeconds))
+	}
+
+	previous, _ := inputs["previous"].(bool)
+
+	logs, err := e.k8sClient.GetPodLogs(ctx, namespace, name, containerName, tailLines, sinceSeconds, previous)
 	if err != nil {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Failed to retrieve pod logs",
 			Error:     err.Error(),
+			Code:      errorCode(err),
 			Timestamp: time.Now(),
 		}
 	}
 
+	instance := ""
+	if previous {
+		instance = ", previous instance"
+	}
+
 	return &ExecuteResult{
 		Success: true,
-		Message: fmt.Sprintf("Successfully retrieved logs from pod %s/%s (container: %s)", namespace, name, containerName),
+		Message: fmt.Sprintf("Successfully retrieved logs from pod %s/%s (container: %s%s)", namespace, name, containerName, instance),
 		Data: map[string]interface{}{
 			"namespace": namespace,
 			"pod":       name,
 			"container": containerName,
 			"tailLines": *tailLines,
+			"previous":  previous,
 			"logs":      logs,
 			"logLength": len(logs),
 		},
@@ -236,11 +477,12 @@ func (e *ToolExecutor) executeCreateConfigMap(ctx context.Context, inputs map[st
 	name := inputs["name"].(string)
 
 	// Convert data interface{} to map[string]string
-	dataInterface := inputs["data"].(map[string]interface{})
+	dataInterface, _ := inputs["data"].(map[string]interface{})
 	data := make(map[string]string)
 	for key, value := range dataInterface {
 		data[key] = value.(string)
 	}
+	binaryData := decodeBinaryData(inputs)
 
 	// Handle optional labels
 	var labels map[string]string
@@ -252,30 +494,76 @@ func (e *ToolExecutor) executeCreateConfigMap(ctx context.Context, inputs map[st
 		}
 	}
 
-	configMap, err := e.k8sClient.CreateOrUpdateConfigMap(ctx, namespace, name, data, labels)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	configMap, err := e.k8sClient.CreateOrUpdateConfigMap(ctx, namespace, name, data, binaryData, labels, dryRun)
+	var quotaErr *k8s.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return &ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("ConfigMap %s/%s was not created because it would exceed the object-count quota", namespace, name),
+			Error:   err.Error(),
+			Code:    types.ErrorCodeForbidden,
+			Data: map[string]interface{}{
+				"quota":    quotaErr.Quota,
+				"resource": quotaErr.Resource,
+				"hard":     quotaErr.Hard,
+				"used":     quotaErr.Used,
+			},
+			Timestamp: time.Now(),
+		}
+	}
 	if err != nil {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Failed to create/update ConfigMap",
 			Error:     err.Error(),
+			Code:      errorCode(err),
 			Timestamp: time.Now(),
 		}
 	}
 
 	return &ExecuteResult{
 		Success: true,
-		Message: fmt.Sprintf("Successfully created/updated ConfigMap %s/%s", namespace, name),
+		Message: fmt.Sprintf("Successfully created/updated ConfigMap %s/%s%s", namespace, name, dryRunNote(dryRun)),
 		Data: map[string]interface{}{
 			"namespace": configMap.Namespace,
 			"name":      configMap.Name,
 			"data":      configMap.Data,
-			"labels":    configMap.Labels,
-			"createdAt": configMap.CreationTimestamp.Time,
+			// Binary values are described by their size rather than echoed back
+			"binaryDataBytes": binaryDataSizes(configMap.BinaryData),
+			"labels":          configMap.Labels,
+			"createdAt":       configMap.CreationTimestamp.Time,
+			"dryRun":          dryRun,
 		},
 		Timestamp: time.Now(),
 	}
 }
 
+// decodeBinaryData decodes the base64 values of the binaryData argument, which the
+// validator has already checked. It returns nil when the argument is absent.
+func decodeBinaryData(inputs map[string]interface{}) map[string][]byte {
+	encoded, ok := inputs["binaryData"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	binaryData := make(map[string][]byte, len(encoded))
+	for key, value := range encoded {
+		decoded, _ := base64.StdEncoding.DecodeString(value.(string))
+		binaryData[key] = decoded
+	}
+	return binaryData
+}
+
+// binaryDataSizes maps each binaryData key to the length of its value
+func binaryDataSizes(binaryData map[string][]byte) map[string]int {
+	sizes := make(map[string]int, len(binaryData))
+	for key, value := range binaryData {
+		sizes[key] = len(value)
+	}
+	return sizes
+}
+
 // executeDeletePod handles pod deletion
 func (e *ToolExecutor) executeDeletePod(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
 	namespace := inputs["namespace"].(string)
@@ -287,12 +575,15 @@ func (e *ToolExecutor) executeDeletePod(ctx context.Context, inputs map[string]i
 		force = forceValue.(bool)
 	}
 
-	err := e.k8sClient.DeletePod(ctx, namespace, name, force)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	err := e.k8sClient.DeletePod(ctx, namespace, name, force, dryRun)
 	if err != nil {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Failed to delete pod",
 			Error:     err.Error(),
+			Code:      errorCode(err),
 			Timestamp: time.Now(),
 		}
 	}
@@ -304,11 +595,52 @@ func (e *ToolExecutor) executeDeletePod(ctx context.Context, inputs map[string]i
 
 	return &ExecuteResult{
 		Success: true,
-		Message: fmt.Sprintf("Successfully deleted pod %s/%s%s", namespace, name, forceMsg),
+		Message: fmt.Sprintf("Successfully deleted pod %s/%s%s%s", namespace, name, forceMsg, dryRunNote(dryRun)),
 		Data: map[string]interface{}{
 			"namespace": namespace,
 			"name":      name,
 			"force":     force,
+			"dryRun":    dryRun,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeDeleteDeployment handles deployment deletion
+func (e *ToolExecutor) executeDeleteDeployment(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	// Cascade unless told otherwise, like kubectl delete
+	cascade := true
+	if cascadeValue, exists := inputs["cascade"]; exists {
+		cascade = cascadeValue.(bool)
+	}
+
+	ownedPods, err := e.k8sClient.DeleteDeployment(ctx, namespace, name, cascade)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to delete deployment",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	podsMsg := fmt.Sprintf("; its %d pods are being deleted", ownedPods)
+	if !cascade {
+		podsMsg = fmt.Sprintf("; its %d pods were orphaned and keep running", ownedPods)
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Successfully deleted deployment %s/%s%s", namespace, name, podsMsg),
+		Data: map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+			"cascade":   cascade,
+			"ownedPods": ownedPods,
 		},
 		Timestamp: time.Now(),
 	}
@@ -318,39 +650,1897 @@ func (e *ToolExecutor) executeDeletePod(ctx context.Context, inputs map[string]i
 func (e *ToolExecutor) executeListPods(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
 	namespace := inputs["namespace"].(string)
 
-	pods, err := e.k8sClient.ListPods(ctx, namespace)
+	fieldSelector, _ := inputs["fieldSelector"].(string)
+	continueToken, _ := inputs["continue"].(string)
+
+	// Without a limit every pod is read in one call, which the cache can serve, and the
+	// result is capped below
+	limit := intInput(inputs, "limit", 0)
+	pods, nextToken, err := e.k8sClient.ListPods(ctx, namespace, k8s.ListOptions{
+		FieldSelector: fieldSelector,
+		Limit:         int64(limit),
+		Continue:      continueToken,
+	})
 	if err != nil {
 		return &ExecuteResult{
 			Success:   false,
 			Message:   "Failed to list pods",
 			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	data := map[string]interface{}{
+		"namespace": namespace,
+		"truncated": nextToken != "",
+	}
+	if fieldSelector != "" {
+		data["fieldSelector"] = fieldSelector
+	}
+	if nextToken != "" {
+		data["continueToken"] = nextToken
+	}
+
+	verbosity := verbosityInput(inputs)
+	if verbosity == types.VerbositySummary {
+		data["phaseCounts"] = podPhaseCounts(pods)
+	}
+
+	message := fmt.Sprintf("Successfully listed %d pods in namespace %s", len(pods), namespace)
+	if limit == 0 {
+		totalCount := len(pods)
+		data["totalCount"] = totalCount
+		if verbosity != types.VerbositySummary {
+			var truncated bool
+			if pods, truncated = truncatePods(pods, defaultPodListLimit); truncated {
+				data["truncated"] = true
+				message = fmt.Sprintf("Showing %d of %d pods in namespace %s; pass limit and continue to page through them all, or narrow the list with fieldSelector",
+					len(pods), totalCount, namespace)
+			}
+		}
+	}
+	data["podCount"] = len(pods)
+
+	if verbosity != types.VerbositySummary {
+		// Convert pods to a format suitable for the response
+		podList := make([]map[string]interface{}, len(pods))
+		for i, pod := range pods {
+			podList[i] = map[string]interface{}{
+				"name":         pod.Name,
+				"namespace":    pod.Namespace,
+				"status":       pod.Status,
+				"phase":        pod.Phase,
+				"node":         pod.Node,
+				"labels":       pod.Labels,
+				"createdAt":    pod.CreatedAt.Format(time.RFC3339),
+				"restarts":     pod.Restarts,
+				"crashLooping": pod.CrashLooping,
+				"ownerKind":    pod.OwnerKind,
+				"ownerName":    pod.OwnerName,
+			}
+			if verbosity == types.VerbosityDetailed {
+				podList[i]["containers"] = pod.Containers
+			}
+		}
+		data["pods"] = podList
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// truncatePods returns the first max pods by name, and whether any were dropped. The cache
+// lists pods in no particular order, so they are sorted to keep the cut stable.
+func truncatePods(pods []k8s.PodInfo, max int) ([]k8s.PodInfo, bool) {
+	if len(pods) <= max {
+		return pods, false
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods[:max], true
+}
+
+// executeListDeployments handles listing deployments in a namespace
+func (e *ToolExecutor) executeListDeployments(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	continueToken, _ := inputs["continue"].(string)
+
+	deployments, nextToken, err := e.k8sClient.ListDeployments(ctx, namespace, k8s.ListOptions{
+		Limit:    int64(intInput(inputs, "limit", 0)),
+		Continue: continueToken,
+	})
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to list deployments",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	deploymentList := make([]map[string]interface{}, len(deployments))
+	for i, deployment := range deployments {
+		deploymentList[i] = map[string]interface{}{
+			"name":          deployment.Name,
+			"namespace":     deployment.Namespace,
+			"readyReplicas": deployment.ReadyReplicas,
+			"totalReplicas": deployment.TotalReplicas,
+			"strategy":      deployment.Strategy,
+		}
+	}
+
+	data := map[string]interface{}{
+		"namespace":       namespace,
+		"deploymentCount": len(deployments),
+		"deployments":     deploymentList,
+	}
+	if nextToken != "" {
+		data["continueToken"] = nextToken
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully listed %d deployments in namespace %s", len(deployments), namespace),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeListServices handles listing services in a namespace
+func (e *ToolExecutor) executeListServices(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+
+	services, _, err := e.k8sClient.ListServices(ctx, namespace, k8s.ListOptions{})
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to list services",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	serviceList := make([]map[string]interface{}, len(services))
+	for i, service := range services {
+		serviceList[i] = map[string]interface{}{
+			"name":      service.Name,
+			"type":      service.Type,
+			"clusterIP": service.ClusterIP,
+			"ports":     service.Ports,
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Successfully listed %d services in namespace %s", len(services), namespace),
+		Data: map[string]interface{}{
+			"namespace":    namespace,
+			"serviceCount": len(services),
+			"services":     serviceList,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeListNamespaces handles listing every namespace in the cluster
+func (e *ToolExecutor) executeListNamespaces(ctx context.Context) *ExecuteResult {
+	namespaces, err := e.k8sClient.ListNamespaces(ctx)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to list namespaces",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	namespaceList := make([]map[string]interface{}, len(namespaces))
+	for i, namespace := range namespaces {
+		namespaceList[i] = map[string]interface{}{
+			"name":      namespace.Name,
+			"status":    namespace.Status,
+			"age":       time.Since(namespace.CreatedAt).Round(time.Second).String(),
+			"createdAt": namespace.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Successfully listed %d namespaces", len(namespaces)),
+		Data: map[string]interface{}{
+			"namespaceCount": len(namespaces),
+			"namespaces":     namespaceList,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeClusterInfo describes the cluster and which optional capabilities it offers, so a
+// caller can tell in advance whether tools such as k8s_top_pods will work
+func (e *ToolExecutor) executeClusterInfo(ctx context.Context) *ExecuteResult {
+	info, err := e.k8sClient.GetClusterInfo(ctx)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to get cluster info",
+			Error:     err.Error(),
+			Code:      errorCode(err),
 			Timestamp: time.Now(),
 		}
 	}
 
-	// Convert pods to a format suitable for the response
-	podList := make([]map[string]interface{}, len(pods))
-	for i, pod := range pods {
-		podList[i] = map[string]interface{}{
-			"name":      pod.Name,
-			"namespace": pod.Namespace,
-			"status":    pod.Status,
-			"phase":     pod.Phase,
-			"node":      pod.Node,
-			"labels":    pod.Labels,
-			"createdAt": pod.CreatedAt.Format(time.RFC3339),
-			"restarts":  pod.Restarts,
+	message := fmt.Sprintf("Kubernetes %s with %d of %d nodes ready", info.ServerVersion, info.ReadyNodes, info.NodeCount)
+	if info.MetricsServer {
+		message += "; metrics-server is available"
+	} else {
+		message += "; metrics-server is not available, so k8s_top_pods and k8s_top_nodes will fail"
+	}
+
+	data := map[string]interface{}{
+		"serverVersion": info.ServerVersion,
+		"platform":      info.Platform,
+		"buildDate":     info.BuildDate,
+		"nodeCount":     info.NodeCount,
+		"readyNodes":    info.ReadyNodes,
+		"capacity":      info.Capacity,
+		"allocatable":   info.Allocatable,
+		"apiGroups":     info.APIGroups,
+		"metricsServer": info.MetricsServer,
+		"extensions":    info.Extensions,
+	}
+	if len(info.Warnings) > 0 {
+		data["warnings"] = info.Warnings
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeTopPods handles reporting pod resource usage, highest first
+func (e *ToolExecutor) executeTopPods(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	sortBy, _ := inputs["sortBy"].(string)
+	if sortBy == "" {
+		sortBy = "memory"
+	}
+
+	pods, err := e.k8sClient.TopPods(ctx, namespace)
+	if err != nil {
+		return topFailure("pod", err)
+	}
+
+	sort.SliceStable(pods, func(i, j int) bool {
+		if sortBy == "cpu" {
+			return pods[i].CPUMillicores > pods[j].CPUMillicores
 		}
+		return pods[i].MemoryBytes > pods[j].MemoryBytes
+	})
+	total := len(pods)
+	if limit := intInput(inputs, "limit", 0); limit > 0 && limit < len(pods) {
+		pods = pods[:limit]
 	}
 
 	return &ExecuteResult{
 		Success: true,
-		Message: fmt.Sprintf("Successfully listed %d pods in namespace %s", len(pods), namespace),
+		Message: fmt.Sprintf("Usage of %d of %d pods in namespace %s, by %s", len(pods), total, namespace, sortBy),
 		Data: map[string]interface{}{
 			"namespace": namespace,
-			"podCount":  len(pods),
-			"pods":      podList,
+			"sortBy":    sortBy,
+			"podCount":  total,
+			"pods":      pods,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeTopNodes handles reporting node resource usage, highest first
+func (e *ToolExecutor) executeTopNodes(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	sortBy, _ := inputs["sortBy"].(string)
+	if sortBy == "" {
+		sortBy = "memory"
+	}
+
+	nodes, err := e.k8sClient.TopNodes(ctx)
+	if err != nil {
+		return topFailure("node", err)
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if sortBy == "cpu" {
+			return nodes[i].CPUMillicores > nodes[j].CPUMillicores
+		}
+		return nodes[i].MemoryBytes > nodes[j].MemoryBytes
+	})
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Usage of %d nodes, by %s", len(nodes), sortBy),
+		Data: map[string]interface{}{
+			"sortBy":    sortBy,
+			"nodeCount": len(nodes),
+			"nodes":     nodes,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// topFailure reports a failed usage query, explaining a missing metrics-server instead
+// of passing on the API server's 404
+func topFailure(kind string, err error) *ExecuteResult {
+	message := fmt.Sprintf("Failed to get %s metrics", kind)
+	if errors.Is(err, k8s.ErrMetricsUnavailable) {
+		message = fmt.Sprintf("No %s metrics: metrics-server does not appear to be installed or running in this cluster", kind)
+	}
+	return &ExecuteResult{
+		Success:   false,
+		Message:   message,
+		Error:     err.Error(),
+		Code:      errorCode(err),
+		Timestamp: time.Now(),
+	}
+}
+
+// executeCordonNode cordons or uncordons a node
+func (e *ToolExecutor) executeCordonNode(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	name := inputs["name"].(string)
+	uncordon, _ := inputs["uncordon"].(bool)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	action, setSchedulable := "cordon", e.k8sClient.CordonNode
+	if uncordon {
+		action, setSchedulable = "uncordon", e.k8sClient.UncordonNode
+	}
+	if err := setSchedulable(ctx, name, dryRun); err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to %s node", action),
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Node %s %sed%s", name, action, dryRunNote(dryRun)),
+		Data: map[string]interface{}{
+			"node":          name,
+			"unschedulable": !uncordon,
+			"dryRun":        dryRun,
 		},
 		Timestamp: time.Now(),
 	}
 }
+
+// executeDrainNode cordons a node and evicts its pods
+func (e *ToolExecutor) executeDrainNode(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	name := inputs["name"].(string)
+	opts := k8s.DrainOptions{}
+	opts.DeleteEmptyDirData, _ = inputs["deleteEmptyDirData"].(bool)
+	opts.DryRun, _ = inputs["dryRun"].(bool)
+	if _, ok := inputs["gracePeriodSeconds"]; ok {
+		grace := int64(intInput(inputs, "gracePeriodSeconds", 0))
+		opts.GracePeriodSeconds = &grace
+	}
+
+	drain, err := e.k8sClient.DrainNode(ctx, name, opts)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to drain node",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Drained node %s: evicted %d pods, skipped %d%s", name, len(drain.Evicted), len(drain.Skipped), dryRunNote(opts.DryRun)),
+		Data: map[string]interface{}{
+			"node":    drain.Node,
+			"evicted": drain.Evicted,
+			"skipped": drain.Skipped,
+			"dryRun":  opts.DryRun,
+			"note":    "Evicted pods terminate in the background; the node stays cordoned until k8s_cordon_node is called with uncordon",
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeListRoles handles listing RBAC roles in a namespace
+func (e *ToolExecutor) executeListRoles(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+
+	includeClusterRoles := false
+	if value, exists := inputs["includeClusterRoles"]; exists {
+		includeClusterRoles = value.(bool)
+	}
+
+	roles, err := e.k8sClient.ListRoles(ctx, namespace, includeClusterRoles)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to list roles",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	data := map[string]interface{}{
+		"namespace": namespace,
+		"roleCount": len(roles),
+	}
+
+	verbosity := verbosityInput(inputs)
+	if verbosity != types.VerbositySummary {
+		roleList := make([]map[string]interface{}, len(roles))
+		for i, role := range roles {
+			roleList[i] = map[string]interface{}{
+				"name":      role.Name,
+				"namespace": role.Namespace,
+				"kind":      role.Kind,
+				"rules":     role.Rules,
+				"createdAt": role.CreatedAt.Format(time.RFC3339),
+			}
+			if verbosity == types.VerbosityDetailed {
+				roleList[i]["labels"] = role.Labels
+			}
+		}
+		data["roles"] = roleList
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully listed %d roles in namespace %s", len(roles), namespace),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeListRoleBindings handles listing RBAC role bindings in a namespace
+func (e *ToolExecutor) executeListRoleBindings(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+
+	includeClusterBindings := false
+	if value, exists := inputs["includeClusterBindings"]; exists {
+		includeClusterBindings = value.(bool)
+	}
+
+	bindings, err := e.k8sClient.ListRoleBindings(ctx, namespace, includeClusterBindings)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to list role bindings",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	data := map[string]interface{}{
+		"namespace":    namespace,
+		"bindingCount": len(bindings),
+	}
+
+	if verbosityInput(inputs) != types.VerbositySummary {
+		bindingList := make([]map[string]interface{}, len(bindings))
+		for i, binding := range bindings {
+			bindingList[i] = map[string]interface{}{
+				"name":      binding.Name,
+				"namespace": binding.Namespace,
+				"kind":      binding.Kind,
+				"role":      fmt.Sprintf("%s/%s", binding.RoleKind, binding.RoleName),
+				"subjects":  binding.Subjects,
+				"createdAt": binding.CreatedAt.Format(time.RFC3339),
+			}
+		}
+		data["bindings"] = bindingList
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Successfully listed %d role bindings in namespace %s", len(bindings), namespace),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeWhoCan handles RBAC access reviews for a verb and resource
+func (e *ToolExecutor) executeWhoCan(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	verb := inputs["verb"].(string)
+	resource := inputs["resource"].(string)
+
+	var apiGroup string
+	if value, exists := inputs["apiGroup"]; exists {
+		apiGroup = value.(string)
+	}
+
+	grants, err := e.k8sClient.WhoCan(ctx, namespace, verb, resource, apiGroup)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to review RBAC access",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	grantList := make([]map[string]interface{}, len(grants))
+	for i, grant := range grants {
+		entry := map[string]interface{}{
+			"subject": grant.Subject,
+			"binding": fmt.Sprintf("%s/%s", grant.BindingKind, grant.BindingName),
+			"role":    fmt.Sprintf("%s/%s", grant.RoleKind, grant.RoleName),
+		}
+		if len(grant.ResourceNames) > 0 {
+			entry["onlyResourceNames"] = grant.ResourceNames
+		}
+		grantList[i] = entry
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Found %d subjects that can %s %s in namespace %s", len(grants), verb, resource, namespace),
+		Data: map[string]interface{}{
+			"namespace":    namespace,
+			"verb":         verb,
+			"resource":     resource,
+			"apiGroup":     apiGroup,
+			"subjectCount": len(grants),
+			"subjects":     grantList,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeSearchPods handles paged pod searches across all namespaces
+func (e *ToolExecutor) executeSearchPods(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	var criteria k8s.PodSearchCriteria
+	if value, exists := inputs["labelSelector"]; exists {
+		criteria.LabelSelector = value.(string)
+	}
+	if value, exists := inputs["nameContains"]; exists {
+		criteria.NameContains = value.(string)
+	}
+	if value, exists := inputs["phase"]; exists {
+		criteria.Phase = value.(string)
+	}
+
+	limit, cursor := pagingInputs(inputs)
+
+	page, err := e.k8sClient.SearchPods(ctx, criteria, limit, cursor)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to search pods",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	data := map[string]interface{}{
+		"podCount": len(page.Items),
+		"hasMore":  page.NextCursor != "",
+	}
+
+	verbosity := verbosityInput(inputs)
+	if verbosity == types.VerbositySummary {
+		data["phaseCounts"] = podPhaseCounts(page.Items)
+	} else {
+		podList := make([]map[string]interface{}, len(page.Items))
+		for i, pod := range page.Items {
+			podList[i] = map[string]interface{}{
+				"name":         pod.Name,
+				"namespace":    pod.Namespace,
+				"status":       pod.Status,
+				"node":         pod.Node,
+				"restarts":     pod.Restarts,
+				"createdAt":    pod.CreatedAt.Format(time.RFC3339),
+				"crashLooping": pod.CrashLooping,
+				"ownerKind":    pod.OwnerKind,
+				"ownerName":    pod.OwnerName,
+			}
+			if verbosity == types.VerbosityDetailed {
+				podList[i]["labels"] = pod.Labels
+				podList[i]["containers"] = pod.Containers
+			}
+		}
+		data["pods"] = podList
+	}
+	if page.NextCursor != "" {
+		data["nextCursor"] = page.NextCursor
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d matching pods across the cluster", len(page.Items)),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeValidateManifest handles server-side manifest validation
+func (e *ToolExecutor) executeValidateManifest(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	manifest := inputs["manifest"].(string)
+
+	results, err := e.k8sClient.ValidateManifest(ctx, manifest, namespace)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to validate manifest",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	invalid := 0
+	for _, r := range results {
+		if !r.Valid {
+			invalid++
+		}
+	}
+
+	message := fmt.Sprintf("All %d objects in the manifest are valid", len(results))
+	if invalid > 0 {
+		message = fmt.Sprintf("%d of %d objects in the manifest failed validation", invalid, len(results))
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"namespace":    namespace,
+			"valid":        invalid == 0,
+			"objectCount":  len(results),
+			"invalidCount": invalid,
+			"objects":      results,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executePodServices handles finding the services that route to a pod
+func (e *ToolExecutor) executePodServices(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	services, err := e.k8sClient.GetPodServices(ctx, namespace, name)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to find services for pod",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	serviceList := make([]map[string]interface{}, len(services))
+	for i, svc := range services {
+		serviceList[i] = map[string]interface{}{
+			"name":      svc.Name,
+			"type":      svc.Type,
+			"clusterIP": svc.ClusterIP,
+			"ports":     svc.Ports,
+		}
+	}
+
+	message := fmt.Sprintf("Found %d services routing to pod %s/%s", len(services), namespace, name)
+	if len(services) == 0 {
+		message = fmt.Sprintf("No services select pod %s/%s; it receives no service traffic", namespace, name)
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"namespace":    namespace,
+			"pod":          name,
+			"serviceCount": len(services),
+			"services":     serviceList,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeWaitForJob handles waiting for a Job to finish
+func (e *ToolExecutor) executeWaitForJob(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	timeout := time.Duration(intInput(inputs, "timeoutSeconds", defaultWaitForJobSeconds)) * time.Second
+	tailLines := int64(intInput(inputs, "tailLines", 50))
+
+	job, err := e.k8sClient.WaitForJob(ctx, namespace, name, timeout)
+	if errors.Is(err, k8s.ErrWaitTimeout) && job != nil {
+		return &ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("Timed out after %s waiting for job %s/%s", timeout, namespace, name),
+			Error:   err.Error(),
+			Code:    errorCode(err),
+			Data: map[string]interface{}{
+				"namespace": namespace,
+				"name":      name,
+				"status":    job.Status,
+				"active":    job.Active,
+				"succeeded": job.Succeeded,
+				"failed":    job.Failed,
+			},
+			Timestamp: time.Now(),
+		}
+	}
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to wait for job",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	data := map[string]interface{}{
+		"namespace":   namespace,
+		"name":        name,
+		"status":      job.Status,
+		"completions": job.Completions,
+		"succeeded":   job.Succeeded,
+		"failed":      job.Failed,
+	}
+	if job.StartTime != nil && job.CompletionTime != nil {
+		data["duration"] = job.CompletionTime.Sub(*job.StartTime).String()
+	}
+
+	if job.Status == k8s.JobStatusFailed {
+		data["failureReason"] = job.FailureReason
+		data["failureMessage"] = job.FailureMessage
+
+		podName, logs, err := e.k8sClient.GetJobFailedPodLogs(ctx, namespace, name, tailLines)
+		if err != nil {
+			e.logger.Warnf("Failed to get logs for failed pod of job %s/%s: %v", namespace, name, err)
+		}
+		if podName != "" {
+			data["failedPod"] = podName
+			data["logs"] = logs
+		}
+
+		return &ExecuteResult{
+			Success:   true,
+			Message:   fmt.Sprintf("Job %s/%s failed: %s", namespace, name, job.FailureReason),
+			Data:      data,
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Job %s/%s completed successfully", namespace, name),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// resolveLogContainer returns the container to read logs from: the requested one once the
+// pod is known to have it, or the pod's main container, skipping sidecars, when none was
+// requested. A container the pod lacks fails validation with the names it does have,
+// rather than with the API server's error.
+func (e *ToolExecutor) resolveLogContainer(ctx context.Context, namespace, name, containerName string) (string, *ExecuteResult) {
+	containers, mainContainer, err := e.k8sClient.GetPodContainers(ctx, namespace, name, e.logSidecars)
+	if err != nil {
+		return "", &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to get pod containers",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+	if len(containers) == 0 {
+		return "", &ExecuteResult{
+			Success:   false,
+			Message:   "Pod has no containers",
+			Error:     "No containers found in pod",
+			Code:      types.ErrorCodeResourceNotFound,
+			Timestamp: time.Now(),
+		}
+	}
+	if containerName == "" {
+		return mainContainer, nil
+	}
+
+	for _, container := range containers {
+		if container == containerName {
+			return containerName, nil
+		}
+	}
+	return "", &ExecuteResult{
+		Success: false,
+		Message: "Input validation failed",
+		Error: fmt.Sprintf("Validation errors: %v", []ValidationError{{
+			Field:   "container",
+			Value:   containerName,
+			Message: fmt.Sprintf("pod %s/%s has no container %q; available containers: %s", namespace, name, containerName, strings.Join(containers, ", ")),
+		}}),
+		Code: types.ErrorCodeInvalidParams,
+		Data: map[string]interface{}{
+			"availableContainers": containers,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeWaitForLog handles following pod logs until a pattern matches
+func (e *ToolExecutor) executeWaitForLog(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	containerName, _ := inputs["container"].(string)
+	pattern := regexp.MustCompile(inputs["pattern"].(string)) // compiled successfully during validation
+	timeout := time.Duration(intInput(inputs, "timeoutSeconds", defaultWaitForLogSeconds)) * time.Second
+
+	containerName, failure := e.resolveLogContainer(ctx, namespace, name, containerName)
+	if failure != nil {
+		return failure
+	}
+
+	match, err := e.k8sClient.WaitForLog(ctx, namespace, name, k8s.LogWaitOptions{
+		Container:    containerName,
+		Pattern:      pattern,
+		Timeout:      timeout,
+		ContextLines: intInput(inputs, "contextLines", 5),
+	})
+	if err != nil {
+		message := "Failed to wait for log line"
+		switch {
+		case errors.Is(err, k8s.ErrWaitTimeout):
+			message = fmt.Sprintf("Timed out after %s waiting for %q in logs of pod %s/%s", timeout, pattern, namespace, name)
+		case errors.Is(err, k8s.ErrLogByteLimit):
+			message = fmt.Sprintf("Stopped following logs of pod %s/%s after reading the byte limit without a match", namespace, name)
+		}
+		result := &ExecuteResult{
+			Success:   false,
+			Message:   message,
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+		if match != nil {
+			result.Data = map[string]interface{}{
+				"bytesRead": match.BytesRead,
+			}
+		}
+		return result
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Found %q in logs of pod %s/%s at line %d", pattern, namespace, name, match.LineNumber),
+		Data: map[string]interface{}{
+			"namespace":  namespace,
+			"pod":        name,
+			"container":  containerName,
+			"line":       match.Line,
+			"lineNumber": match.LineNumber,
+			"before":     match.Before,
+			"after":      match.After,
+			"bytesRead":  match.BytesRead,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeDiagnoseJob handles collecting failure signals for a Job
+func (e *ToolExecutor) executeDiagnoseJob(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	tailLines := int64(intInput(inputs, "tailLines", 50))
+
+	diagnosis, err := e.k8sClient.DiagnoseJob(ctx, namespace, name, tailLines)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to diagnose job",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	message := fmt.Sprintf("Job %s/%s is %s (%d succeeded, %d failed, %d active)",
+		namespace, name, diagnosis.Job.Status, diagnosis.Job.Succeeded, diagnosis.Job.Failed, diagnosis.Job.Active)
+	if diagnosis.Job.FailureReason != "" {
+		message = fmt.Sprintf("%s: %s", message, diagnosis.Job.FailureReason)
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"job":          diagnosis.Job,
+			"backoffLimit": diagnosis.BackoffLimit,
+			"events":       diagnosis.Events,
+			"pods":         diagnosis.Pods,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeDiagnosePod handles collecting failure signals for a pod
+func (e *ToolExecutor) executeDiagnosePod(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	tailLines := int64(intInput(inputs, "tailLines", 50))
+
+	diagnosis, err := e.k8sClient.DiagnosePod(ctx, namespace, name, tailLines)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to diagnose pod",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	message := fmt.Sprintf("Pod %s/%s is %s with %d restarts", namespace, name, diagnosis.Pod.Status, diagnosis.Pod.Restarts)
+	if diagnosis.Pod.CrashLooping {
+		message += " and is crash-looping"
+	}
+	for _, termination := range diagnosis.Terminations {
+		if termination.ExitCode != 0 {
+			message = fmt.Sprintf("%s; %s last terminated with %s (exit %d)", message, termination.Container, termination.Reason, termination.ExitCode)
+			break
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"pod":          diagnosis.Pod,
+			"reason":       diagnosis.Reason,
+			"message":      diagnosis.Message,
+			"conditions":   diagnosis.Conditions,
+			"terminations": diagnosis.Terminations,
+			"events":       diagnosis.Events,
+			"logContainer": diagnosis.LogContainer,
+			"previousLogs": diagnosis.PreviousLogs,
+			"logs":         diagnosis.Logs,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeFindMissingProbes handles the readiness/liveness probe coverage sweep
+func (e *ToolExecutor) executeFindMissingProbes(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace, _ := inputs["namespace"].(string)
+	limit, cursor := pagingInputs(inputs)
+
+	page, err := e.k8sClient.FindMissingProbes(ctx, namespace, limit, cursor)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to scan deployments for probes",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	deployments := make(map[string]bool)
+	missingReadiness, missingLiveness := 0, 0
+	for _, gap := range page.Items {
+		deployments[gap.Namespace+"/"+gap.Deployment] = true
+		if gap.MissingReadiness {
+			missingReadiness++
+		}
+		if gap.MissingLiveness {
+			missingLiveness++
+		}
+	}
+
+	data := map[string]interface{}{
+		"deploymentCount":  len(deployments),
+		"containerCount":   len(page.Items),
+		"missingReadiness": missingReadiness,
+		"missingLiveness":  missingLiveness,
+		"containers":       page.Items,
+		"hasMore":          page.NextCursor != "",
+	}
+	if page.NextCursor != "" {
+		data["nextCursor"] = page.NextCursor
+	}
+
+	scope := "across the cluster"
+	if namespace != "" {
+		scope = fmt.Sprintf("in namespace %s", namespace)
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Found %d containers in %d deployments missing probes %s", len(page.Items), len(deployments), scope),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeImageAudit handles the workload image inventory
+func (e *ToolExecutor) executeImageAudit(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	flaggedOnly, _ := inputs["flaggedOnly"].(bool)
+
+	images, err := e.k8sClient.AuditImages(ctx, namespace)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to audit images",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	latestCount, untaggedCount := 0, 0
+	reported := make([]k8s.ImageUsage, 0, len(images))
+	for _, image := range images {
+		if image.Latest {
+			latestCount++
+		}
+		if image.Untagged {
+			untaggedCount++
+		}
+		if flaggedOnly && !image.Latest && !image.Untagged {
+			continue
+		}
+		reported = append(reported, image)
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Found %d images in namespace %s: %d use :latest, %d are untagged",
+			len(images), namespace, latestCount, untaggedCount),
+		Data: map[string]interface{}{
+			"namespace":     namespace,
+			"imageCount":    len(images),
+			"latestCount":   latestCount,
+			"untaggedCount": untaggedCount,
+			"images":        reported,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// apiKeysUnavailable reports that no API key store supports issuing keys
+func apiKeysUnavailable() *ExecuteResult {
+	return &ExecuteResult{
+		Success:   false,
+		Message:   "API key management is not available",
+		Error:     "the configured API key store cannot issue keys",
+		Code:      types.ErrorCodeInternalError,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeCreateAPIKey issues a new API key. The key appears only in this result.
+func (e *ToolExecutor) executeCreateAPIKey(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	if e.apiKeys == nil {
+		return apiKeysUnavailable()
+	}
+
+	name := inputs["name"].(string)
+	var permissions []string
+	for _, permission := range inputs["permissions"].([]interface{}) {
+		permissions = append(permissions, permission.(string))
+	}
+	ttl := defaultAPIKeyTTL
+	if hours, ok := toInt(inputs, "ttlHours"); ok {
+		ttl = time.Duration(hours) * time.Hour
+	}
+
+	key, info, err := e.apiKeys.CreateAPIKey(ctx, name, permissions, ttl)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to create API key",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Created API key %s (%s), expiring %s", info.ID, name, info.ExpiresAt.Format(time.RFC3339)),
+		Data: map[string]interface{}{
+			"key":         key,
+			"keyId":       info.ID,
+			"name":        info.Name,
+			"permissions": info.Permissions,
+			"expiresAt":   info.ExpiresAt,
+			"note":        "Store the key now; it cannot be retrieved again",
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeIssueToken mints a signed access token for a user. The token appears only in
+// this result.
+func (e *ToolExecutor) executeIssueToken(inputs map[string]interface{}) *ExecuteResult {
+	if e.tokens == nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Token issuing is not available",
+			Error:     "the server has no private signing key: set auth.jwt.secret or MCP_JWT_SECRET, with algorithm HS256",
+			Code:      types.ErrorCodeInternalError,
+			Timestamp: time.Now(),
+		}
+	}
+
+	username := inputs["username"].(string)
+	userID, _ := inputs["userId"].(string)
+	if userID == "" {
+		userID = username
+	}
+	var permissions []string
+	for _, permission := range inputs["permissions"].([]interface{}) {
+		permissions = append(permissions, permission.(string))
+	}
+	ttl := e.tokenTTL
+	if minutes, ok := toInt(inputs, "ttlMinutes"); ok {
+		ttl = time.Duration(minutes) * time.Minute
+	}
+
+	issuedAt := time.Now()
+	token, err := e.tokens.GenerateToken(userID, username, permissions, ttl)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to issue token",
+			Error:     err.Error(),
+			Code:      types.ErrorCodeInternalError,
+			Timestamp: time.Now(),
+		}
+	}
+
+	expiresAt := issuedAt.Add(ttl)
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Issued a token for %s, expiring %s", username, expiresAt.Format(time.RFC3339)),
+		Data: map[string]interface{}{
+			"token":       token,
+			"tokenType":   "Bearer",
+			"username":    username,
+			"userId":      userID,
+			"permissions": permissions,
+			"expiresAt":   expiresAt,
+			"note":        "Store the token now; it cannot be retrieved again. Send it as Authorization: Bearer <token>",
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeRotateAPIKey replaces an API key, expiring the old one after a grace period
+func (e *ToolExecutor) executeRotateAPIKey(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	if e.apiKeys == nil {
+		return apiKeysUnavailable()
+	}
+
+	keyID := inputs["keyId"].(string)
+	grace := defaultAPIKeyRotationGrace
+	if minutes, ok := toInt(inputs, "graceMinutes"); ok {
+		grace = time.Duration(minutes) * time.Minute
+	}
+
+	key, replacement, err := e.apiKeys.RotateAPIKey(ctx, keyID, grace)
+	if err != nil {
+		code := errorCode(err)
+		switch {
+		case errors.Is(err, auth.ErrAPIKeyNotFound):
+			code = types.ErrorCodeResourceNotFound
+		case errors.Is(err, auth.ErrAPIKeyExpired):
+			code = types.ErrorCodeInvalidParams
+		}
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to rotate API key",
+			Error:     err.Error(),
+			Code:      code,
+			Timestamp: time.Now(),
+		}
+	}
+
+	data := map[string]interface{}{
+		"key":           key,
+		"keyId":         replacement.ID,
+		"replacesKeyId": keyID,
+		"name":          replacement.Name,
+		"permissions":   replacement.Permissions,
+		"note":          "Store the key now; it cannot be retrieved again",
+	}
+	if replacement.ExpiresAt != nil {
+		data["expiresAt"] = replacement.ExpiresAt
+	}
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Rotated API key %s to %s; the old key stops working in %s, or at its own expiry if sooner", keyID, replacement.ID, grace),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeServerPreflight handles running the server configuration checks
+func (e *ToolExecutor) executeServerPreflight(ctx context.Context) *ExecuteResult {
+	if e.preflight == nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Preflight checks are not configured",
+			Error:     "no preflight checks registered",
+			Code:      types.ErrorCodeInternalError,
+			Timestamp: time.Now(),
+		}
+	}
+
+	report := e.preflight(ctx)
+	message := "All preflight checks passed"
+	if !report.OK {
+		message = fmt.Sprintf("Server is misconfigured: %s", report.Failures())
+	} else if failures := report.Failures(); failures != "" {
+		message = fmt.Sprintf("Preflight checks passed with warnings: %s", failures)
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: message,
+		Data: map[string]interface{}{
+			"ok":      report.OK,
+			"results": report.Results,
+			"ranAt":   report.RanAt,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeExecPod runs an allowlisted command in a container
+func (e *ToolExecutor) executeExecPod(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	container, _ := inputs["container"].(string)
+
+	var command []string
+	for _, arg := range inputs["command"].([]interface{}) {
+		command = append(command, arg.(string))
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, time.Duration(intInput(inputs, "timeoutSeconds", defaultExecSeconds))*time.Second)
+	defer cancel()
+
+	output, err := e.k8sClient.ExecInPod(execCtx, namespace, name, container, command)
+	data := map[string]interface{}{
+		"namespace": namespace,
+		"pod":       name,
+		"command":   command,
+		"output":    output,
+	}
+	if container != "" {
+		data["container"] = container
+	}
+
+	if err != nil {
+		// A command that ran and failed still produced useful output
+		if exitCode, ok := k8s.ExitCode(err); ok {
+			data["exitCode"] = exitCode
+			return &ExecuteResult{
+				Success:   true,
+				Message:   fmt.Sprintf("Command exited with code %d in pod %s/%s", exitCode, namespace, name),
+				Data:      data,
+				Timestamp: time.Now(),
+			}
+		}
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to exec in pod",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Data:      data,
+			Timestamp: time.Now(),
+		}
+	}
+
+	data["exitCode"] = 0
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Command completed in pod %s/%s", namespace, name),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeRollbackDeployment restores a deployment to an earlier revision
+func (e *ToolExecutor) executeRollbackDeployment(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	fromRevision, toRevision, err := e.k8sClient.RollbackDeployment(ctx, namespace, name, int64(intInput(inputs, "revision", 0)))
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to roll back deployment",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Rolled back deployment %s/%s from revision %d to revision %d", namespace, name, fromRevision, toRevision),
+		Data: map[string]interface{}{
+			"namespace":    namespace,
+			"deployment":   name,
+			"fromRevision": fromRevision,
+			"toRevision":   toRevision,
+			"note":         "The rollback creates a new revision; use k8s_rollout_status to follow it",
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeRolloutStatus reports the progress of a deployment's latest rollout
+func (e *ToolExecutor) executeRolloutStatus(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	status, err := e.k8sClient.GetRolloutStatus(ctx, namespace, name)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to get rollout status",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Rollout of deployment %s/%s is %s: %s", namespace, name, strings.ToLower(status.State), status.Message),
+		Data: map[string]interface{}{
+			"namespace":         namespace,
+			"deployment":        name,
+			"state":             status.State,
+			"message":           status.Message,
+			"revision":          status.Revision,
+			"desiredReplicas":   status.DesiredReplicas,
+			"updatedReplicas":   status.UpdatedReplicas,
+			"readyReplicas":     status.ReadyReplicas,
+			"availableReplicas": status.AvailableReplicas,
+			"conditions":        status.Conditions,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeRolloutHistory lists a deployment's revisions
+func (e *ToolExecutor) executeRolloutHistory(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	revisions, err := e.k8sClient.GetRolloutHistory(ctx, namespace, name)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to get rollout history",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	revisionList := make([]map[string]interface{}, len(revisions))
+	for i, revision := range revisions {
+		revisionList[i] = map[string]interface{}{
+			"revision":    revision.Revision,
+			"replicaSet":  revision.ReplicaSet,
+			"images":      revision.Images,
+			"changeCause": revision.ChangeCause,
+			"replicas":    revision.Replicas,
+			"current":     revision.Current,
+			"createdAt":   revision.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Found %d revisions of deployment %s/%s", len(revisions), namespace, name),
+		Data: map[string]interface{}{
+			"namespace":     namespace,
+			"deployment":    name,
+			"revisionCount": len(revisions),
+			"revisions":     revisionList,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeCreateSecret creates or updates a secret. The result names the keys written but
+// never echoes their values.
+func (e *ToolExecutor) executeCreateSecret(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	secretType, _ := inputs["type"].(string)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	// Plain string values and decoded binaryData values are stored alike
+	data := decodeBinaryData(inputs)
+	if data == nil {
+		data = make(map[string][]byte)
+	}
+	dataInterface, _ := inputs["data"].(map[string]interface{})
+	for key, value := range dataInterface {
+		data[key] = []byte(value.(string))
+	}
+
+	secret, err := e.k8sClient.CreateOrUpdateSecret(ctx, namespace, name, data, secretType, dryRun)
+	var quotaErr *k8s.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		return &ExecuteResult{
+			Success: false,
+			Message: fmt.Sprintf("Secret %s/%s was not created because it would exceed the object-count quota", namespace, name),
+			Error:   err.Error(),
+			Code:    types.ErrorCodeForbidden,
+			Data: map[string]interface{}{
+				"quota":    quotaErr.Quota,
+				"resource": quotaErr.Resource,
+				"hard":     quotaErr.Hard,
+				"used":     quotaErr.Used,
+			},
+			Timestamp: time.Now(),
+		}
+	}
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to create/update Secret",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Successfully created/updated Secret %s/%s%s", namespace, name, dryRunNote(dryRun)),
+		Data: map[string]interface{}{
+			"namespace": secret.Namespace,
+			"name":      secret.Name,
+			"type":      secret.Type,
+			"keys":      secret.Keys,
+			"dryRun":    dryRun,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeListSecrets lists secrets by key name only
+func (e *ToolExecutor) executeListSecrets(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+
+	secrets, err := e.k8sClient.ListSecrets(ctx, namespace)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to list secrets",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	secretList := make([]map[string]interface{}, len(secrets))
+	for i, secret := range secrets {
+		secretList[i] = map[string]interface{}{
+			"name":      secret.Name,
+			"type":      secret.Type,
+			"keys":      secret.Keys,
+			"createdAt": secret.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Found %d secrets in namespace %s", len(secrets), namespace),
+		Data: map[string]interface{}{
+			"namespace":   namespace,
+			"secretCount": len(secrets),
+			"secrets":     secretList,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeDescribe describes a resource of any kind
+func (e *ToolExecutor) executeDescribe(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	kind := inputs["kind"].(string)
+	namespace, _ := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	details, err := e.k8sClient.DescribeResource(ctx, kind, namespace, name)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to describe %s", kind),
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(details), &data); err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to decode %s details", kind),
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Described %s %s", kind, name),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executePatchResource applies a patch to any resource
+func (e *ToolExecutor) executePatchResource(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	kind := inputs["kind"].(string)
+	namespace, _ := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	patchType := inputs["patchType"].(string)
+	patch := inputs["patch"].(string)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	patched, err := e.k8sClient.PatchResource(ctx, kind, namespace, name, patchType, []byte(patch), dryRun)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to patch %s %s", kind, name),
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Patched %s %s%s", patched.Kind, name, dryRunNote(dryRun)),
+		Data: map[string]interface{}{
+			"kind":            patched.Kind,
+			"namespace":       patched.Namespace,
+			"name":            patched.Name,
+			"patchType":       patchType,
+			"resourceVersion": patched.ResourceVersion,
+			"generation":      patched.Generation,
+			"dryRun":          dryRun,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeEditMetadata handles setting and removing the labels or annotations, as field
+// says, of a resource. A null value in the input map removes its key.
+func (e *ToolExecutor) executeEditMetadata(ctx context.Context, inputs map[string]interface{}, field string) *ExecuteResult {
+	kind := inputs["kind"].(string)
+	namespace, _ := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+	dryRun, _ := inputs["dryRun"].(bool)
+
+	input, _ := inputs[field].(map[string]interface{})
+	changes := make(map[string]*string, len(input))
+	var set, removed []string
+	for key, value := range input {
+		if value == nil {
+			changes[key] = nil
+			removed = append(removed, key)
+			continue
+		}
+		str, _ := value.(string)
+		changes[key] = &str
+		set = append(set, key)
+	}
+	sort.Strings(set)
+	sort.Strings(removed)
+
+	patched, err := e.k8sClient.EditMetadata(ctx, kind, namespace, name, field, changes, dryRun)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to update %s of %s %s", field, kind, name),
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Updated %s of %s %s: %d set, %d removed%s", field, patched.Kind, name, len(set), len(removed), dryRunNote(dryRun)),
+		Data: map[string]interface{}{
+			"kind":            patched.Kind,
+			"namespace":       patched.Namespace,
+			"name":            patched.Name,
+			"set":             set,
+			"removed":         removed,
+			"resourceVersion": patched.ResourceVersion,
+			"dryRun":          dryRun,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// executeGetSecret describes a secret by key names and value sizes
+func (e *ToolExecutor) executeGetSecret(ctx context.Context, inputs map[string]interface{}) *ExecuteResult {
+	namespace := inputs["namespace"].(string)
+	name := inputs["name"].(string)
+
+	details, err := e.k8sClient.GetResource(ctx, &types.ResourceIdentifier{
+		Type:      types.ResourceTypeSecret,
+		Namespace: namespace,
+		Name:      name,
+	})
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to get secret",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(details), &data); err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to decode secret details",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	keys, _ := data["keys"].([]interface{})
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Secret %s/%s has %d keys", namespace, name, len(keys)),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// executeGetAllPodLogs returns the logs of every container in a pod, concatenated
+func (e *ToolExecutor) executeGetAllPodLogs(ctx context.Context, namespace, name string, tailLines, sinceSeconds *int64, inputs map[string]interface{}) *ExecuteResult {
+	previous, _ := inputs["previous"].(bool)
+	var sidecars []string
+	if includeSidecars, _ := inputs["includeSidecars"].(bool); !includeSidecars {
+		sidecars = e.logSidecars
+	}
+
+	logs, containers, err := e.k8sClient.GetAllPodLogs(ctx, namespace, name, tailLines, sinceSeconds, previous, sidecars)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to retrieve pod logs",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+
+	return &ExecuteResult{
+		Success: true,
+		Message: fmt.Sprintf("Successfully retrieved logs from %d containers in pod %s/%s", len(containers), namespace, name),
+		Data: map[string]interface{}{
+			"namespace":  namespace,
+			"pod":        name,
+			"containers": containers,
+			"tailLines":  *tailLines,
+			"previous":   previous,
+			"logs":       logs,
+			"logLength":  len(logs),
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// followPodLogs streams a container's logs for up to followSeconds, passing each line to
+// the context's LogLineFunc if one is set. The follow ends early if the container exits.
+func (e *ToolExecutor) followPodLogs(ctx context.Context, namespace, name, container string, tailLines *int64, followSeconds int) *ExecuteResult {
+	followCtx, cancel := context.WithTimeout(ctx, time.Duration(followSeconds)*time.Second)
+	defer cancel()
+
+	stream, err := e.k8sClient.FollowPodLogs(followCtx, namespace, name, container, tailLines)
+	if err != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Failed to follow pod logs",
+			Error:     err.Error(),
+			Code:      errorCode(err),
+			Timestamp: time.Now(),
+		}
+	}
+	defer stream.Close()
+
+	emit, _ := ctx.Value(logStreamKey{}).(LogLineFunc)
+
+	logs, truncated, readErr := readFollowedLogs(stream, emit)
+
+	// A cancelled caller is a failure; reaching followSeconds is the normal way a follow ends
+	if ctx.Err() != nil {
+		return &ExecuteResult{
+			Success:   false,
+			Message:   "Log follow was cancelled",
+			Error:     ctx.Err().Error(),
+			Code:      errorCode(ctx.Err()),
+			Timestamp: time.Now(),
+		}
+	}
+
+	ended := "container exited"
+	switch {
+	case errors.Is(readErr, bufio.ErrTooLong):
+		ended = fmt.Sprintf("a log line exceeded %d bytes", maxFollowedLogLineBytes)
+	case followCtx.Err() != nil:
+		ended = fmt.Sprintf("followed for %ds", followSeconds)
+	}
+
+	data := map[string]interface{}{
+		"namespace": namespace,
+		"pod":       name,
+		"container": container,
+		"follow":    true,
+		"ended":     ended,
+		"logs":      logs,
+	}
+	if truncated {
+		data["truncated"] = true
+	}
+
+	return &ExecuteResult{
+		Success:   true,
+		Message:   fmt.Sprintf("Followed logs from pod %s/%s (container: %s, %s)", namespace, name, container, ended),
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// readFollowedLogs reads a followed log stream line by line, passing each line to emit when
+// it is set and keeping up to maxFollowedLogBytes of output. Lines longer than
+// maxFollowedLogLineBytes end the read with bufio.ErrTooLong rather than growing without
+// bound; the output read so far is kept and reported as truncated.
+func readFollowedLogs(stream io.Reader, emit LogLineFunc) (string, bool, error) {
+	var logs strings.Builder
+	truncated := false
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFollowedLogLineBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if emit != nil {
+			emit(line)
+		}
+		if logs.Len()+len(line)+1 <= maxFollowedLogBytes {
+			logs.WriteString(line)
+			logs.WriteByte('\n')
+		} else {
+			truncated = true
+		}
+	}
+
+	err := scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		truncated = true
+	}
+	return logs.String(), truncated, err
+}
+
+// intInput reads an optional integer input, returning defaultValue when it is absent
+func intInput(inputs map[string]interface{}, key string, defaultValue int) int {
+	if value, ok := toInt(inputs, key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// toInt reads a numeric input as an int. JSON callers send float64, while Go callers
+// may pass any integer type; ok is false when the input is missing or not a number.
+func toInt(inputs map[string]interface{}, key string) (int, bool) {
+	switch v := inputs[key].(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+// verbosityInput returns the requested verbosity, defaulting to normal
+func verbosityInput(inputs map[string]interface{}) types.Verbosity {
+	value, _ := inputs["verbosity"].(string)
+	verbosity, err := types.ParseVerbosity(value)
+	if err != nil {
+		return types.VerbosityNormal
+	}
+	return verbosity
+}
+
+// podPhaseCounts counts pods per phase for summary output
+func podPhaseCounts(pods []k8s.PodInfo) map[string]int {
+	counts := make(map[string]int)
+	for _, pod := range pods {
+		counts[pod.Phase]++
+	}
+	return counts
+}
+
+// errorCode classifies a failed call's error as one of the types.ErrorCode* values, so
+// callers can tell a missing resource or a refused request from a server fault
+func errorCode(err error) int {
+	switch {
+	case apierrors.IsNotFound(err):
+		return types.ErrorCodeResourceNotFound
+	case apierrors.IsForbidden(err):
+		return types.ErrorCodeForbidden
+	case apierrors.IsUnauthorized(err):
+		return types.ErrorCodeUnauthorized
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err), apierrors.IsAlreadyExists(err), apierrors.IsConflict(err):
+		return types.ErrorCodeInvalidParams
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return types.ErrorCodeTimeout
+	case utilnet.IsConnectionRefused(err), apierrors.IsServiceUnavailable(err):
+		return types.ErrorCodeClusterUnavailable
+	default:
+		return types.ErrorCodeInternalError
+	}
+}
+
+// dryRunNote is appended to the message of a mutating tool so a dry run is never
+// mistaken for an applied change
+func dryRunNote(dryRun bool) string {
+	if dryRun {
+		return " (dry run, no changes applied)"
+	}
+	return ""
+}
+
+// pagingInputs extracts the optional limit and cursor used by paged tools
+func pagingInputs(inputs map[string]interface{}) (int, string) {
+	limit := intInput(inputs, "limit", k8s.DefaultSweepLimit)
+	cursor, _ := inputs["cursor"].(string)
+	return limit, cursor
+}
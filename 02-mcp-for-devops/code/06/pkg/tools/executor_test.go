@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"kubernetes-mcp-server/internal/logging"
+	"kubernetes-mcp-server/pkg/auth"
+	"kubernetes-mcp-server/pkg/k8s"
+	"kubernetes-mcp-server/pkg/types"
+
+	"github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestErrorCodeClassifiesWrappedAPIErrors(t *testing.T) {
+	pods := schema.GroupResource{Resource: "pods"}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", apierrors.NewNotFound(pods, "web"), types.ErrorCodeResourceNotFound},
+		{"forbidden", apierrors.NewForbidden(pods, "web", errors.New("no access")), types.ErrorCodeForbidden},
+		{"unauthorized", apierrors.NewUnauthorized("expired token"), types.ErrorCodeUnauthorized},
+		{"invalid", apierrors.NewBadRequest("bad selector"), types.ErrorCodeInvalidParams},
+		{"deadline", context.DeadlineExceeded, types.ErrorCodeTimeout},
+		{"other", errors.New("boom"), types.ErrorCodeInternalError},
+	}
+	for _, tt := range tests {
+		// Client methods wrap API errors with context, as in "failed to get pod default/web: ..."
+		err := fmt.Errorf("failed to get pod default/web: %w", tt.err)
+		if got := errorCode(err); got != tt.want {
+			t.Errorf("%s: errorCode = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncatePodsKeepsFirstByName(t *testing.T) {
+	pods := []k8s.PodInfo{{Name: "web-c"}, {Name: "web-a"}, {Name: "web-b"}}
+
+	kept, truncated := truncatePods(pods, 2)
+	if !truncated || len(kept) != 2 || kept[0].Name != "web-a" || kept[1].Name != "web-b" {
+		t.Errorf("truncatePods(3 pods, 2) = %v, %v; want web-a and web-b, truncated", kept, truncated)
+	}
+
+	kept, truncated = truncatePods(pods[:2], 2)
+	if truncated || len(kept) != 2 {
+		t.Errorf("truncatePods(2 pods, 2) = %v, %v; want both pods, not truncated", kept, truncated)
+	}
+}
+
+func TestReadFollowedLogsBoundsLineLength(t *testing.T) {
+	var emitted []string
+	emit := func(line string) { emitted = append(emitted, line) }
+
+	// A container writing one huge line without a newline
+	stream := io.MultiReader(strings.NewReader("starting\n"), strings.NewReader(strings.Repeat("x", maxFollowedLogLineBytes+1)))
+	logs, truncated, err := readFollowedLogs(stream, emit)
+	if !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("err = %v, want bufio.ErrTooLong", err)
+	}
+	if logs != "starting\n" || !truncated {
+		t.Errorf("readFollowedLogs = %q, %v; want the first line, truncated", logs, truncated)
+	}
+	if len(emitted) != 1 || emitted[0] != "starting" {
+		t.Errorf("emitted = %q, want only the first line", emitted)
+	}
+
+	logs, truncated, err = readFollowedLogs(strings.NewReader("a\nb"), nil)
+	if err != nil || truncated || logs != "a\nb\n" {
+		t.Errorf("readFollowedLogs(a, b) = %q, %v, %v; want both lines, not truncated", logs, truncated, err)
+	}
+}
+
+func TestIssuedTokensAuthenticate(t *testing.T) {
+	executor := NewToolExecutor(nil, logging.NewLogger("error", "text"))
+	inputs := map[string]interface{}{"username": "ci-bot", "permissions": []interface{}{"role:viewer"}, "confirm": true}
+	if result := executor.ExecuteTool(context.Background(), "server_issue_token", inputs); result.Success {
+		t.Fatal("issued a token without a configured signing key")
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	authenticator := auth.NewJWTAuthenticator([]byte("a-test-secret-that-is-at-least-32-bytes"), logger)
+	executor.SetTokenIssuer(authenticator, time.Hour)
+
+	result := executor.ExecuteTool(context.Background(), "server_issue_token", inputs)
+	if !result.Success {
+		t.Fatalf("server_issue_token failed: %s", result.Error)
+	}
+	info, err := authenticator.Authenticate(context.Background(), result.Data["token"].(string))
+	if err != nil {
+		t.Fatalf("issued token does not authenticate: %v", err)
+	}
+	if info.Identity != "ci-bot" || len(info.Permissions) != 1 || info.Permissions[0] != "role:viewer" {
+		t.Errorf("unexpected auth info %+v", info)
+	}
+	if expiresAt := result.Data["expiresAt"].(time.Time); time.Until(expiresAt) > time.Hour || time.Until(expiresAt) < 59*time.Minute {
+		t.Errorf("token expires at %s, want in about an hour", expiresAt)
+	}
+}
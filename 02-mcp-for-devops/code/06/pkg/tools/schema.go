@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// maxEchoedValueLength bounds the input values quoted back in schema errors, so an
+// oversized manifest or patch is not repeated in full
+const maxEchoedValueLength = 253
+
+// redactedFields are inputs whose values are never quoted in errors, since they may hold
+// secrets or unprintable bytes
+var redactedFields = map[string]bool{
+	"data":       true,
+	"binaryData": true,
+}
+
+// toolSchema is a tool's compiled input schema
+type toolSchema struct {
+	schema   *jsonschema.Schema
+	required []string
+}
+
+// compileToolSchemas compiles the InputSchema of every tool in GetToolDefinitions, so the
+// schemas advertised to clients are the ones inputs are checked against
+func compileToolSchemas() (map[string]*toolSchema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+
+	definitions := GetToolDefinitions()
+	for _, tool := range definitions {
+		document, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode input schema of %s: %w", tool.Name, err)
+		}
+		if err := compiler.AddResource(tool.Name+".json", bytes.NewReader(document)); err != nil {
+			return nil, fmt.Errorf("failed to load input schema of %s: %w", tool.Name, err)
+		}
+	}
+
+	schemas := make(map[string]*toolSchema, len(definitions))
+	for _, tool := range definitions {
+		schema, err := compiler.Compile(tool.Name + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile input schema of %s: %w", tool.Name, err)
+		}
+		schemas[tool.Name] = &toolSchema{schema: schema, required: tool.InputSchema.Required}
+	}
+	return schemas, nil
+}
+
+// validateSchema checks inputs against the tool's input schema: required fields, types,
+// patterns, lengths, ranges, enums and constants
+func (s *toolSchema) validateSchema(inputs map[string]interface{}, result *ValidationResult) {
+	// Inputs may hold Go ints and slices rather than decoded JSON, so normalize them first
+	encoded, err := json.Marshal(inputs)
+	if err != nil {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "arguments",
+			Message: fmt.Sprintf("arguments are not valid JSON: %v", err),
+		})
+		return
+	}
+	var document interface{}
+	if err := json.Unmarshal(encoded, &document); err != nil {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "arguments",
+			Message: fmt.Sprintf("arguments are not valid JSON: %v", err),
+		})
+		return
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if err := s.schema.Validate(document); !errors.As(err, &validationErr) {
+		return
+	}
+	for _, cause := range leafErrors(validationErr) {
+		if cause.InstanceLocation == "" && strings.HasSuffix(cause.KeywordLocation, "/required") {
+			for _, field := range s.required {
+				if _, exists := inputs[field]; !exists {
+					result.Errors = append(result.Errors, ValidationError{
+						Field:   field,
+						Message: fmt.Sprintf("%s is required", field),
+					})
+				}
+			}
+			continue
+		}
+
+		field := instanceField(cause.InstanceLocation)
+		message := strings.TrimPrefix(cause.Message, "value ")
+		if strings.HasSuffix(cause.KeywordLocation, "/const") && message == "must be true" {
+			message = "must be set to true to perform this operation"
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   field,
+			Value:   echoedValue(cause.InstanceLocation, document),
+			Message: fmt.Sprintf("%s %s", field, message),
+		})
+	}
+}
+
+// leafErrors flattens a validation error to the failures that caused it
+func leafErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
+
+// instanceField converts a JSON pointer to an input, such as /data/password, to the dotted
+// field name used in validation errors
+func instanceField(location string) string {
+	segments := strings.Split(strings.TrimPrefix(location, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+	}
+	return strings.Join(segments, ".")
+}
+
+// echoedValue returns the input at location for quoting in an error, or "" when it is
+// nested, redacted, an object or array, or too long to quote
+func echoedValue(location string, document interface{}) string {
+	segments := strings.Split(strings.TrimPrefix(location, "/"), "/")
+	if len(segments) != 1 {
+		return ""
+	}
+	inputs, _ := document.(map[string]interface{})
+	if redactedFields[segments[0]] {
+		return ""
+	}
+	switch value := inputs[segments[0]].(type) {
+	case string:
+		if len(value) > maxEchoedValueLength {
+			return ""
+		}
+		return value
+	case float64, bool:
+		return fmt.Sprintf("%v", value)
+	default:
+		return ""
+	}
+}
@@ -1,9 +1,19 @@
 package tools
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"kubernetes-mcp-server/pkg/k8s"
 )
 
 // ValidationError represents a validation failure with details
@@ -23,51 +33,136 @@ type ValidationResult struct {
 	Errors []ValidationError `json:"errors,omitempty"`
 }
 
+// secretKeyPattern matches the keys allowed in a secret's data
+var secretKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// maxConfigMapBytes is the API server's limit on the total size of a ConfigMap's keys
+// and values
+const maxConfigMapBytes = 1 << 20
+
+// clusterScopedTools lists tools that sweep every namespace or can address cluster-scoped
+// objects. A namespace input is optional for them and narrows the tool to that namespace
+// when it supports it.
+var clusterScopedTools = map[string]bool{
+	"k8s_search_pods":         true,
+	"k8s_find_missing_probes": true,
+	"server_preflight":        true,
+	"k8s_describe":            true,
+	"k8s_list_namespaces":     true,
+	"k8s_cluster_info":        true,
+	"k8s_top_nodes":           true,
+	"k8s_cordon_node":         true,
+	"k8s_drain_node":          true,
+	"k8s_patch_resource":      true,
+	"k8s_label_resource":      true,
+	"k8s_annotate_resource":   true,
+	"server_create_api_key":   true,
+	"server_rotate_api_key":   true,
+	"server_issue_token":      true,
+	BatchToolName:             true,
+}
+
+// podFieldSelectorPaths are the pod fields the API server can filter on
+var podFieldSelectorPaths = map[string]bool{
+	"metadata.name":            true,
+	"metadata.namespace":       true,
+	"spec.nodeName":            true,
+	"spec.restartPolicy":       true,
+	"spec.schedulerName":       true,
+	"spec.serviceAccountName":  true,
+	"spec.hostNetwork":         true,
+	"status.phase":             true,
+	"status.podIP":             true,
+	"status.nominatedNodeName": true,
+}
+
+// IsClusterScoped reports whether a tool operates across all namespaces
+func IsClusterScoped(toolName string) bool {
+	return clusterScopedTools[toolName]
+}
+
 // Validator provides comprehensive input validation for tool parameters
 type Validator struct {
-	kubernetesNamePattern *regexp.Regexp
+	// schemas holds each tool's compiled input schema, keyed by tool name
+	schemas             map[string]*toolSchema
+	execAllowedCommands map[string]bool
+	// allowedNamespaces restricts namespace inputs when non-empty
+	allowedNamespaces []string
 }
 
-// NewValidator creates a new validator with compiled patterns
+// NewValidator creates a new validator with the tools' compiled input schemas
 func NewValidator() *Validator {
-	return &Validator{
-		kubernetesNamePattern: regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`),
+	schemas, err := compileToolSchemas()
+	if err != nil {
+		panic(err)
+	}
+	return &Validator{schemas: schemas}
+}
+
+// SetExecAllowedCommands sets the executables k8s_exec_pod may run. Commands are matched
+// exactly against the first element of the command, so "cat" does not allow "/bin/cat".
+func (v *Validator) SetExecAllowedCommands(commands []string) {
+	allowed := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		allowed[command] = true
 	}
+	v.execAllowedCommands = allowed
+}
+
+// SetAllowedNamespaces restricts namespace inputs to namespaces. An empty list allows any
+// namespace.
+func (v *Validator) SetAllowedNamespaces(namespaces []string) {
+	v.allowedNamespaces = namespaces
 }
 
-// ValidateToolInput validates tool parameters based on the tool name and inputs
+// ValidateToolInput validates tool parameters based on the tool name and inputs. Inputs
+// are checked against the tool's input schema first, then for what a schema can't
+// express, such as the namespace allow-list, parsable selectors and the exec allowlist.
 func (v *Validator) ValidateToolInput(toolName string, inputs map[string]interface{}) *ValidationResult {
 	result := &ValidationResult{Valid: true, Errors: []ValidationError{}}
 
-	// Common validations for all tools
-	v.validateNamespace(inputs, result)
-
-	// Only validate resource name for tools that require a specific resource
-	if toolName != "k8s_list_pods" {
-		v.validateResourceName(inputs, result)
+	schema, ok := v.schemas[toolName]
+	if !ok {
+		result.Valid = false
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "toolName",
+			Value:   toolName,
+			Message: "unknown tool name",
+		})
+		return result
 	}
+	schema.validateSchema(inputs, result)
+
+	v.validateNamespaceAllowed(inputs, result)
 
 	// Tool-specific validations
 	switch toolName {
-	case "k8s_scale_deployment":
-		v.validateScaleOperation(inputs, result)
-	case "k8s_restart_deployment":
-		v.validateRestartOperation(inputs, result)
 	case "k8s_get_pod_logs":
 		v.validateLogOperation(inputs, result)
 	case "k8s_create_configmap":
 		v.validateConfigMapOperation(inputs, result)
-	case "k8s_delete_pod":
-		v.validateDeleteOperation(inputs, result)
 	case "k8s_list_pods":
-		v.validateListOperation(inputs, result)
-	default:
-		result.Valid = false
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "toolName",
-			Value:   toolName,
-			Message: "unknown tool name",
-		})
+		v.validatePodFieldSelector(inputs, result)
+	case "k8s_search_pods":
+		v.validateSearchPodsOperation(inputs, result)
+	case "k8s_validate_manifest":
+		v.validateManifestOperation(inputs, result)
+	case "k8s_exec_pod":
+		v.validateExecOperation(inputs, result)
+	case "k8s_create_secret":
+		v.validateSecretOperation(inputs, result)
+	case "k8s_patch_resource":
+		v.validatePatchOperation(inputs, result)
+	case "k8s_label_resource":
+		v.validateMetadataKeys("labels", "label", inputs, result)
+	case "k8s_annotate_resource":
+		v.validateMetadataKeys("annotations", "annotation", inputs, result)
+	case "k8s_wait_for_log":
+		v.validateWaitForLogOperation(inputs, result)
+	case "k8s_find_missing_probes":
+		v.validatePaging(inputs, result)
+	case BatchToolName:
+		v.validateBatchOperation(inputs, result)
 	}
 
 	if len(result.Errors) > 0 {
@@ -77,338 +172,386 @@ func (v *Validator) ValidateToolInput(toolName string, inputs map[string]interfa
 	return result
 }
 
-// validateNamespace checks if namespace parameter is valid
-func (v *Validator) validateNamespace(inputs map[string]interface{}, result *ValidationResult) {
-	namespace, exists := inputs["namespace"]
-	if !exists {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "namespace",
-			Value:   "",
-			Message: "namespace is required",
-		})
-		return
-	}
-
-	namespaceStr, ok := namespace.(string)
-	if !ok {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "namespace",
-			Value:   fmt.Sprintf("%v", namespace),
-			Message: "namespace must be a string",
-		})
+// validateNamespaceAllowed checks the namespace input, when given, against the allow-list
+func (v *Validator) validateNamespaceAllowed(inputs map[string]interface{}, result *ValidationResult) {
+	namespace, ok := inputs["namespace"].(string)
+	if !ok || len(v.allowedNamespaces) == 0 || slices.Contains(v.allowedNamespaces, namespace) {
 		return
 	}
+	result.Errors = append(result.Errors, ValidationError{
+		Field:   "namespace",
+		Value:   namespace,
+		Message: fmt.Sprintf("namespace is not allowed; this server is restricted to namespaces %s", strings.Join(v.allowedNamespaces, ", ")),
+	})
+}
 
-	if !v.kubernetesNamePattern.MatchString(namespaceStr) {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "namespace",
-			Value:   namespaceStr,
-			Message: "namespace must follow Kubernetes naming conventions (lowercase alphanumeric and hyphens)",
-		})
+// validateLogOperation validates the log options that cannot be combined
+func (v *Validator) validateLogOperation(inputs map[string]interface{}, result *ValidationResult) {
+	if allContainers, _ := inputs["allContainers"].(bool); allContainers {
+		_, hasContainer := inputs["container"]
+		follow, _ := inputs["follow"].(bool)
+		if hasContainer || follow {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "allContainers",
+				Value:   "true",
+				Message: "allContainers cannot be combined with container or follow",
+			})
+		}
 	}
 
-	if len(namespaceStr) > 63 {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "namespace",
-			Value:   namespaceStr,
-			Message: "namespace must be 63 characters or less",
-		})
+	// A terminated instance has no new output to follow
+	if previous, _ := inputs["previous"].(bool); previous {
+		if follow, _ := inputs["follow"].(bool); follow {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "previous",
+				Value:   "true",
+				Message: "previous cannot be combined with follow",
+			})
+		}
 	}
 }
 
-// validateResourceName checks if name parameter is valid
-func (v *Validator) validateResourceName(inputs map[string]interface{}, result *ValidationResult) {
-	name, exists := inputs["name"]
-	if !exists {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "name",
-			Value:   "",
-			Message: "name is required",
-		})
-		return
-	}
-
-	nameStr, ok := name.(string)
+// validateWaitForLogOperation validates log wait parameters, including that the pattern compiles
+func (v *Validator) validateWaitForLogOperation(inputs map[string]interface{}, result *ValidationResult) {
+	patternStr, ok := inputs["pattern"].(string)
 	if !ok {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "name",
-			Value:   fmt.Sprintf("%v", name),
-			Message: "name must be a string",
-		})
 		return
 	}
 
-	if !v.kubernetesNamePattern.MatchString(nameStr) {
+	if _, err := regexp.Compile(patternStr); err != nil {
 		result.Errors = append(result.Errors, ValidationError{
-			Field:   "name",
-			Value:   nameStr,
-			Message: "name must follow Kubernetes naming conventions (lowercase alphanumeric and hyphens)",
-		})
-	}
-
-	if len(nameStr) > 253 {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "name",
-			Value:   nameStr,
-			Message: "name must be 253 characters or less",
+			Field:   "pattern",
+			Value:   patternStr,
+			Message: fmt.Sprintf("pattern is not a valid regular expression: %v", err),
 		})
 	}
 }
 
-// validateScaleOperation validates scaling-specific parameters
-func (v *Validator) validateScaleOperation(inputs map[string]interface{}, result *ValidationResult) {
-	// Validate replicas
-	replicas, exists := inputs["replicas"]
-	if !exists {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "replicas",
-			Value:   "",
-			Message: "replicas is required for scaling operations",
-		})
+// validateConfigMapOperation validates ConfigMap creation parameters
+func (v *Validator) validateConfigMapOperation(inputs map[string]interface{}, result *ValidationResult) {
+	// Text goes in data and anything else in binaryData; at least one must be set
+	data, hasData := inputs["data"]
+	binaryData, binarySize := v.validateBinaryData(inputs, result)
+	if !hasData && binaryData == nil {
+		if _, hasBinaryData := inputs["binaryData"]; !hasBinaryData {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "data",
+				Value:   "",
+				Message: "data or binaryData is required for ConfigMap operations",
+			})
+		}
 		return
 	}
 
-	// Handle both int and float64 (JSON numbers can be float64)
-	var replicasInt int
-	switch r := replicas.(type) {
-	case int:
-		replicasInt = r
-	case float64:
-		replicasInt = int(r)
-	default:
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "replicas",
-			Value:   fmt.Sprintf("%v", replicas),
-			Message: "replicas must be an integer",
-		})
+	// The schema has already reported data that isn't an object
+	dataMap, ok := data.(map[string]interface{})
+	if hasData && !ok {
 		return
 	}
 
-	if replicasInt < 0 || replicasInt > 100 {
+	if len(dataMap) == 0 && len(binaryData) == 0 {
 		result.Errors = append(result.Errors, ValidationError{
-			Field:   "replicas",
-			Value:   fmt.Sprintf("%d", replicasInt),
-			Message: "replicas must be between 0 and 100",
+			Field:   "data",
+			Value:   "{}",
+			Message: "data and binaryData cannot both be empty",
 		})
 	}
 
-	v.validateConfirmation(inputs, result)
-}
+	// Validate each data key
+	size := binarySize
+	for key, value := range dataMap {
+		if key == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "data.key",
+				Value:   key,
+				Message: "data keys cannot be empty",
+			})
+		}
+		if _, ok := binaryData[key]; ok {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("binaryData.%s", key),
+				Value:   key,
+				Message: "a key cannot be set in both data and binaryData",
+			})
+		}
 
-// validateRestartOperation validates restart-specific parameters
-func (v *Validator) validateRestartOperation(inputs map[string]interface{}, result *ValidationResult) {
-	v.validateConfirmation(inputs, result)
-}
+		str, _ := value.(string)
+		size += len(key) + len(str)
+	}
 
-// validateLogOperation validates log retrieval parameters
-func (v *Validator) validateLogOperation(inputs map[string]interface{}, result *ValidationResult) {
-	// Validate optional tailLines
-	if tailLines, exists := inputs["tailLines"]; exists {
-		var tailLinesInt int
-		switch t := tailLines.(type) {
-		case int:
-			tailLinesInt = t
-		case float64:
-			tailLinesInt = int(t)
-		default:
+	if size > maxConfigMapBytes {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "data",
+			Value:   fmt.Sprintf("%d bytes", size),
+			Message: fmt.Sprintf("ConfigMap data and binaryData exceed the Kubernetes limit of %d bytes; split it across several ConfigMaps or mount large files from a volume", maxConfigMapBytes),
+		})
+	}
+
+	// Validate optional label keys
+	labelsMap, _ := inputs["labels"].(map[string]interface{})
+	for key := range labelsMap {
+		if !isValidLabelKey(key) {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "tailLines",
-				Value:   fmt.Sprintf("%v", tailLines),
-				Message: "tailLines must be an integer",
+				Field:   "labels.key",
+				Value:   key,
+				Message: "label key is invalid",
 			})
-			return
 		}
+	}
+}
 
-		if tailLinesInt < 1 || tailLinesInt > 10000 {
+// validateSecretOperation validates secret creation parameters. Unlike the ConfigMap
+// checks, errors never include a data value.
+func (v *Validator) validateSecretOperation(inputs map[string]interface{}, result *ValidationResult) {
+	binaryData, _ := v.validateBinaryData(inputs, result)
+	// The schema has already reported data that isn't an object
+	dataMap, ok := inputs["data"].(map[string]interface{})
+	if _, hasData := inputs["data"]; hasData && !ok {
+		return
+	}
+	if len(dataMap) == 0 && len(binaryData) == 0 {
+		if _, hasBinaryData := inputs["binaryData"]; !hasBinaryData || binaryData != nil {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "tailLines",
-				Value:   fmt.Sprintf("%d", tailLinesInt),
-				Message: "tailLines must be between 1 and 10000",
+				Field:   "data",
+				Value:   "",
+				Message: "data or binaryData must be a non-empty object",
 			})
 		}
+		return
 	}
 
-	// Validate optional sinceSeconds
-	if sinceSeconds, exists := inputs["sinceSeconds"]; exists {
-		var sinceSecondsInt int
-		switch s := sinceSeconds.(type) {
-		case int:
-			sinceSecondsInt = s
-		case float64:
-			sinceSecondsInt = int(s)
-		default:
+	for key := range dataMap {
+		if _, ok := binaryData[key]; ok {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "sinceSeconds",
-				Value:   fmt.Sprintf("%v", sinceSeconds),
-				Message: "sinceSeconds must be an integer",
+				Field:   fmt.Sprintf("binaryData.%s", key),
+				Value:   key,
+				Message: "a key cannot be set in both data and binaryData",
 			})
-			return
 		}
-
-		if sinceSecondsInt < 1 || sinceSecondsInt > 86400 {
+		if !secretKeyPattern.MatchString(key) {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "sinceSeconds",
-				Value:   fmt.Sprintf("%d", sinceSecondsInt),
-				Message: "sinceSeconds must be between 1 and 86400 (24 hours)",
+				Field:   "data.key",
+				Value:   key,
+				Message: "data keys must consist of alphanumeric characters, '-', '_' or '.'",
 			})
 		}
 	}
+}
+
+// validateBinaryData checks the optional binaryData argument of ConfigMaps and secrets: an
+// object whose values are base64-encoded bytes. It returns the decoded length of each
+// valid entry and the total size of keys and decoded values, or nil when binaryData is
+// absent or not an object. Values are never echoed, since they may be secret or unprintable.
+func (v *Validator) validateBinaryData(inputs map[string]interface{}, result *ValidationResult) (map[string]int, int) {
+	value, exists := inputs["binaryData"]
+	if !exists {
+		return nil, 0
+	}
+	// The schema has already reported binaryData that isn't an object of strings
+	binaryMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, 0
+	}
 
-	// Validate optional container name
-	if container, exists := inputs["container"]; exists {
-		containerStr, ok := container.(string)
+	lengths := make(map[string]int, len(binaryMap))
+	size := 0
+	for key, value := range binaryMap {
+		if !secretKeyPattern.MatchString(key) {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "binaryData.key",
+				Value:   key,
+				Message: "binaryData keys must consist of alphanumeric characters, '-', '_' or '.'",
+			})
+		}
+		encoded, ok := value.(string)
 		if !ok {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "container",
-				Value:   fmt.Sprintf("%v", container),
-				Message: "container must be a string",
+				Field:   fmt.Sprintf("binaryData.%s", key),
+				Value:   "<redacted>",
+				Message: "binaryData values must be standard base64-encoded strings",
 			})
-			return
+			continue
 		}
+		lengths[key] = len(decoded)
+		size += len(key) + len(decoded)
+	}
+	return lengths, size
+}
 
-		if !v.kubernetesNamePattern.MatchString(containerStr) {
+// validateMetadataKeys validates the label or annotation keys, named by noun, of the map
+// input field. Label and annotation keys share one syntax.
+func (v *Validator) validateMetadataKeys(field, noun string, inputs map[string]interface{}, result *ValidationResult) {
+	changes, _ := inputs[field].(map[string]interface{})
+	for _, key := range slices.Sorted(maps.Keys(changes)) {
+		if !isValidLabelKey(key) {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "container",
-				Value:   containerStr,
-				Message: "container name must follow Kubernetes naming conventions",
+				Field:   field + ".key",
+				Value:   key,
+				Message: fmt.Sprintf("%s key is invalid; use a name such as tier or example.com/tier", noun),
 			})
 		}
 	}
 }
 
-// validateConfigMapOperation validates ConfigMap creation parameters
-func (v *Validator) validateConfigMapOperation(inputs map[string]interface{}, result *ValidationResult) {
-	// Validate data field
-	data, exists := inputs["data"]
-	if !exists {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "data",
-			Value:   "",
-			Message: "data is required for ConfigMap operations",
-		})
+// validatePatchOperation validates that a patch parses as JSON of the shape its patch
+// type expects
+func (v *Validator) validatePatchOperation(inputs map[string]interface{}, result *ValidationResult) {
+	// The schema has already reported a missing or invalid patch type or patch
+	patchType, _ := inputs["patchType"].(string)
+	patch, ok := inputs["patch"].(string)
+	if !slices.Contains([]string{"json", "merge", "strategic"}, patchType) || !ok {
 		return
 	}
-
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
+	if strings.TrimSpace(patch) == "" {
 		result.Errors = append(result.Errors, ValidationError{
-			Field:   "data",
-			Value:   fmt.Sprintf("%v", data),
-			Message: "data must be an object with string keys and values",
+			Field:   "patch",
+			Value:   "",
+			Message: "patch cannot be empty",
 		})
 		return
 	}
 
-	if len(dataMap) == 0 {
+	var document interface{}
+	if err := json.Unmarshal([]byte(patch), &document); err != nil {
 		result.Errors = append(result.Errors, ValidationError{
-			Field:   "data",
-			Value:   "{}",
-			Message: "data cannot be empty",
+			Field:   "patch",
+			Value:   patch,
+			Message: fmt.Sprintf("patch is not valid JSON: %v", err),
 		})
+		return
 	}
 
-	// Validate each data key and value
-	for key, value := range dataMap {
-		if key == "" {
+	switch document.(type) {
+	case []interface{}:
+		if patchType != "json" {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "data.key",
-				Value:   key,
-				Message: "data keys cannot be empty",
+				Field:   "patch",
+				Value:   patch,
+				Message: fmt.Sprintf("a %s patch must be a JSON object; arrays of operations need patchType json", patchType),
 			})
 		}
-
-		if _, ok := value.(string); !ok {
+	case map[string]interface{}:
+		if patchType == "json" {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   fmt.Sprintf("data.%s", key),
-				Value:   fmt.Sprintf("%v", value),
-				Message: "data values must be strings",
+				Field:   "patch",
+				Value:   patch,
+				Message: `a json patch must be an array of operations, such as [{"op": "replace", "path": "/spec/replicas", "value": 3}]`,
 			})
 		}
+	default:
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "patch",
+			Value:   patch,
+			Message: "patch must be a JSON object or array",
+		})
 	}
+}
 
-	// Validate optional labels
-	if labels, exists := inputs["labels"]; exists {
-		labelsMap, ok := labels.(map[string]interface{})
-		if !ok {
-			result.Errors = append(result.Errors, ValidationError{
-				Field:   "labels",
-				Value:   fmt.Sprintf("%v", labels),
-				Message: "labels must be an object with string keys and values",
-			})
-			return
-		}
+// validateExecOperation validates a command to run in a container against the allowlist
+func (v *Validator) validateExecOperation(inputs map[string]interface{}, result *ValidationResult) {
+	// The schema has already reported a command that isn't a non-empty array of strings
+	command, _ := inputs["command"].([]interface{})
+	if len(command) == 0 {
+		return
+	}
+	executable, ok := command[0].(string)
+	if !ok {
+		return
+	}
 
-		for key, value := range labelsMap {
-			if !isValidLabelKey(key) {
-				result.Errors = append(result.Errors, ValidationError{
-					Field:   "labels.key",
-					Value:   key,
-					Message: "label key is invalid",
-				})
-			}
-
-			if _, ok := value.(string); !ok {
-				result.Errors = append(result.Errors, ValidationError{
-					Field:   fmt.Sprintf("labels.%s", key),
-					Value:   fmt.Sprintf("%v", value),
-					Message: "label values must be strings",
-				})
-			}
-		}
+	if !v.execAllowedCommands[executable] {
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   "command",
+			Value:   executable,
+			Message: fmt.Sprintf("%q is not an allowed command; allowed commands are %s", executable, strings.Join(sortedKeys(v.execAllowedCommands), ", ")),
+		})
 	}
 }
 
-// validateDeleteOperation validates deletion parameters
-func (v *Validator) validateDeleteOperation(inputs map[string]interface{}, result *ValidationResult) {
-	v.validateConfirmation(inputs, result)
-
-	// Validate optional force parameter
-	if force, exists := inputs["force"]; exists {
-		if _, ok := force.(bool); !ok {
+// validateBatchOperation checks that a batch doesn't contain another batch. Each
+// operation's arguments are validated when it runs.
+func (v *Validator) validateBatchOperation(inputs map[string]interface{}, result *ValidationResult) {
+	for i, operation := range batchOperations(inputs) {
+		if operation.tool == BatchToolName {
 			result.Errors = append(result.Errors, ValidationError{
-				Field:   "force",
-				Value:   fmt.Sprintf("%v", force),
-				Message: "force must be a boolean",
+				Field:   fmt.Sprintf("operations.%d.tool", i),
+				Value:   operation.tool,
+				Message: "batches cannot be nested",
 			})
 		}
 	}
 }
 
-// validateConfirmation ensures dangerous operations require explicit confirmation
-func (v *Validator) validateConfirmation(inputs map[string]interface{}, result *ValidationResult) {
-	confirm, exists := inputs["confirm"]
-	if !exists {
-		result.Errors = append(result.Errors, ValidationError{
-			Field:   "confirm",
-			Value:   "",
-			Message: "confirmation is required for this operation",
-		})
+// validatePodFieldSelector checks that a field selector parses and only uses pod fields
+// the API server supports
+func (v *Validator) validatePodFieldSelector(inputs map[string]interface{}, result *ValidationResult) {
+	selectorStr, ok := inputs["fieldSelector"].(string)
+	if !ok {
 		return
 	}
 
-	confirmBool, ok := confirm.(bool)
-	if !ok {
+	parsed, err := fields.ParseSelector(selectorStr)
+	if err != nil {
 		result.Errors = append(result.Errors, ValidationError{
-			Field:   "confirm",
-			Value:   fmt.Sprintf("%v", confirm),
-			Message: "confirm must be a boolean",
+			Field:   "fieldSelector",
+			Value:   selectorStr,
+			Message: fmt.Sprintf("fieldSelector is invalid: %v", err),
 		})
 		return
 	}
 
-	if !confirmBool {
+	for _, requirement := range parsed.Requirements() {
+		if !podFieldSelectorPaths[requirement.Field] {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "fieldSelector",
+				Value:   selectorStr,
+				Message: fmt.Sprintf("field %q is not supported for pods; use one of %s", requirement.Field, strings.Join(sortedKeys(podFieldSelectorPaths), ", ")),
+			})
+		}
+	}
+}
+
+// validateSearchPodsOperation validates cluster-wide pod search parameters
+func (v *Validator) validateSearchPodsOperation(inputs map[string]interface{}, result *ValidationResult) {
+	if selector, ok := inputs["labelSelector"].(string); ok {
+		if _, err := labels.Parse(selector); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "labelSelector",
+				Value:   selector,
+				Message: fmt.Sprintf("labelSelector is invalid: %v", err),
+			})
+		}
+	}
+
+	v.validatePaging(inputs, result)
+}
+
+// validateManifestOperation validates manifest validation parameters
+func (v *Validator) validateManifestOperation(inputs map[string]interface{}, result *ValidationResult) {
+	if manifest, ok := inputs["manifest"].(string); ok && strings.TrimSpace(manifest) == "" {
 		result.Errors = append(result.Errors, ValidationError{
-			Field:   "confirm",
-			Value:   "false",
-			Message: "you must set confirm=true to perform this operation",
+			Field:   "manifest",
+			Value:   "",
+			Message: "manifest cannot be empty",
 		})
 	}
 }
 
-// validateListOperation validates list operation parameters
-func (v *Validator) validateListOperation(inputs map[string]interface{}, result *ValidationResult) {
-	// For list operations, we only need namespace validation which is already done in common validation
-	// No additional validation required for listing pods
+// validatePaging validates the optional cursor used by paged tools
+func (v *Validator) validatePaging(inputs map[string]interface{}, result *ValidationResult) {
+	if cursor, ok := inputs["cursor"].(string); ok {
+		if _, err := k8s.DecodeSweepCursor(cursor); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "cursor",
+				Value:   cursor,
+				Message: "cursor is not a value returned by a previous call",
+			})
+		}
+	}
 }
 
 // isValidLabelKey validates Kubernetes label key format
@@ -436,3 +579,13 @@ func isValidLabelKey(key string) bool {
 
 	return true
 }
+
+// sortedKeys returns the keys of a set in sorted order, for stable error messages
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
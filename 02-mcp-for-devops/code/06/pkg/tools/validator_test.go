@@ -0,0 +1,286 @@
+package tools
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateResourceNameAcceptsDNSSubdomains(t *testing.T) {
+	v := NewValidator()
+	for _, name := range []string{
+		"web",
+		"my.app.config",
+		"api-v2.example.com",
+		"a",
+		"0.1",
+		strings.Repeat("a", 63) + "." + strings.Repeat("b", 63),
+	} {
+		result := v.ValidateToolInput("k8s_rollout_status", map[string]interface{}{
+			"namespace": "default",
+			"name":      name,
+		})
+		if len(result.Errors) != 0 {
+			t.Errorf("name %q: unexpected errors %v", name, result.Errors)
+		}
+	}
+}
+
+func TestValidateResourceNameRejectsInvalidNames(t *testing.T) {
+	v := NewValidator()
+	for _, name := range []string{
+		"",
+		"Web",
+		"my..config",
+		".config",
+		"config.",
+		"my.-app",
+		"my_app",
+		strings.Repeat("a", 254),
+	} {
+		result := v.ValidateToolInput("k8s_rollout_status", map[string]interface{}{
+			"namespace": "default",
+			"name":      name,
+		})
+		if !hasFieldError(result, "name") {
+			t.Errorf("name %q: expected a name error, got %v", name, result.Errors)
+		}
+	}
+}
+
+func TestNamespaceAndContainerRejectDots(t *testing.T) {
+	v := NewValidator()
+
+	result := v.ValidateToolInput("k8s_rollout_status", map[string]interface{}{
+		"namespace": "team.frontend",
+		"name":      "web",
+	})
+	if !hasFieldError(result, "namespace") {
+		t.Errorf("dotted namespace: expected a namespace error, got %v", result.Errors)
+	}
+
+	result = v.ValidateToolInput("k8s_get_pod_logs", map[string]interface{}{
+		"namespace": "default",
+		"name":      "web.v1",
+		"container": "app.main",
+	})
+	if !hasFieldError(result, "container") {
+		t.Errorf("dotted container: expected a container error, got %v", result.Errors)
+	}
+	if hasFieldError(result, "name") {
+		t.Errorf("dotted pod name: unexpected name error %v", result.Errors)
+	}
+}
+
+func TestSecretValidationNeverEchoesValues(t *testing.T) {
+	v := NewValidator()
+
+	result := v.ValidateToolInput("k8s_create_secret", map[string]interface{}{
+		"namespace": "default",
+		"name":      "db-credentials",
+		"confirm":   true,
+		"type":      "Opaque",
+		"data": map[string]interface{}{
+			"password": 12345678,
+			"bad key":  "hunter2",
+		},
+	})
+	if !hasFieldError(result, "data.password") || !hasFieldError(result, "data.key") {
+		t.Fatalf("expected errors for the non-string value and invalid key, got %v", result.Errors)
+	}
+	for _, err := range result.Errors {
+		if strings.Contains(err.Error(), "12345678") || strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("validation error leaks a secret value: %v", err)
+		}
+	}
+}
+
+func TestConfigMapValidationChecksTotalSize(t *testing.T) {
+	v := NewValidator()
+	configMap := func(data map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"namespace": "default", "name": "settings", "data": data}
+	}
+
+	// Keys count towards the limit as well as values
+	value := strings.Repeat("x", maxConfigMapBytes/2-len("a.txt"))
+	result := v.ValidateToolInput("k8s_create_configmap", configMap(map[string]interface{}{
+		"a.txt": value,
+		"b.txt": value,
+	}))
+	if !result.Valid {
+		t.Fatalf("ConfigMap of exactly %d bytes rejected: %v", maxConfigMapBytes, result.Errors)
+	}
+
+	result = v.ValidateToolInput("k8s_create_configmap", configMap(map[string]interface{}{
+		"a.txt": value,
+		"b.txt": value + "x",
+	}))
+	if result.Valid || !hasFieldError(result, "data") {
+		t.Fatalf("ConfigMap over %d bytes: expected a data error, got %v", maxConfigMapBytes, result.Errors)
+	}
+}
+
+func TestBinaryDataValidation(t *testing.T) {
+	v := NewValidator()
+	keystore := base64.StdEncoding.EncodeToString([]byte{0xfe, 0xed, 0xfe, 0xed, 0x00, 0x02})
+	tests := []struct {
+		name   string
+		inputs map[string]interface{}
+		field  string // the field expected to fail, or "" if valid
+	}{
+		{"binary only", map[string]interface{}{"binaryData": map[string]interface{}{"keystore.jks": keystore}}, ""},
+		{"text and binary", map[string]interface{}{
+			"data":       map[string]interface{}{"app.properties": "port=8080"},
+			"binaryData": map[string]interface{}{"keystore.jks": keystore},
+		}, ""},
+		{"neither", map[string]interface{}{}, "data"},
+		{"both empty", map[string]interface{}{"data": map[string]interface{}{}, "binaryData": map[string]interface{}{}}, "data"},
+		{"not base64", map[string]interface{}{"binaryData": map[string]interface{}{"keystore.jks": "not base64!"}}, "binaryData.keystore.jks"},
+		{"invalid key", map[string]interface{}{"binaryData": map[string]interface{}{"key store": keystore}}, "binaryData.key"},
+		{"repeated key", map[string]interface{}{
+			"data":       map[string]interface{}{"keystore.jks": "text"},
+			"binaryData": map[string]interface{}{"keystore.jks": keystore},
+		}, "binaryData.keystore.jks"},
+	}
+	for _, tool := range []string{"k8s_create_configmap", "k8s_create_secret"} {
+		for _, tt := range tests {
+			inputs := map[string]interface{}{"namespace": "default", "name": "tls-store", "confirm": true}
+			for key, value := range tt.inputs {
+				inputs[key] = value
+			}
+			result := v.ValidateToolInput(tool, inputs)
+			if tt.field == "" && !result.Valid {
+				t.Errorf("%s, %s: unexpected errors %v", tool, tt.name, result.Errors)
+			}
+			if tt.field != "" && !hasFieldError(result, tt.field) {
+				t.Errorf("%s, %s: expected a %s error, got %v", tool, tt.name, tt.field, result.Errors)
+			}
+		}
+	}
+}
+
+func TestPatchValidationChecksShapeForPatchType(t *testing.T) {
+	v := NewValidator()
+	tests := []struct {
+		patchType, patch string
+		valid            bool
+	}{
+		{"merge", `{"metadata": {"annotations": {"owner": "payments"}}}`, true},
+		{"strategic", `{"spec": {"template": {"spec": {"containers": [{"name": "app", "env": [{"name": "DEBUG", "value": "1"}]}]}}}}`, true},
+		{"json", `[{"op": "replace", "path": "/spec/replicas", "value": 3}]`, true},
+		{"json", `{"spec": {"replicas": 3}}`, false},
+		{"merge", `[{"op": "remove", "path": "/metadata/labels/tier"}]`, false},
+		{"merge", `{"spec": `, false},
+		{"merge", `"replicas"`, false},
+		{"apply", `{}`, false},
+	}
+	for _, tt := range tests {
+		result := v.ValidateToolInput("k8s_patch_resource", map[string]interface{}{
+			"kind":      "Deployment",
+			"namespace": "default",
+			"name":      "web",
+			"patchType": tt.patchType,
+			"patch":     tt.patch,
+			"confirm":   true,
+		})
+		if result.Valid != tt.valid {
+			t.Errorf("%s patch %s: valid = %t, want %t (%v)", tt.patchType, tt.patch, result.Valid, tt.valid, result.Errors)
+		}
+	}
+}
+
+func TestNamespaceAllowList(t *testing.T) {
+	v := NewValidator()
+	v.SetAllowedNamespaces([]string{"checkout", "payments"})
+
+	allowed := v.ValidateToolInput("k8s_list_pods", map[string]interface{}{"namespace": "checkout"})
+	if !allowed.Valid {
+		t.Errorf("listing pods in an allowed namespace failed validation: %v", allowed.Errors)
+	}
+	denied := v.ValidateToolInput("k8s_get_pod_logs", map[string]interface{}{"namespace": "kube-system", "name": "etcd"})
+	if denied.Valid || !hasFieldError(denied, "namespace") {
+		t.Errorf("getting a pod outside the allowed namespaces passed validation: %v", denied.Errors)
+	}
+	// Cluster-wide sweeps without a namespace are narrowed by the client instead
+	sweep := v.ValidateToolInput("k8s_search_pods", map[string]interface{}{})
+	if hasFieldError(sweep, "namespace") {
+		t.Errorf("searching pods without a namespace failed validation: %v", sweep.Errors)
+	}
+}
+
+func TestInputsAreValidatedAgainstToolSchemas(t *testing.T) {
+	v := NewValidator()
+	tests := []struct {
+		name   string
+		tool   string
+		inputs map[string]interface{}
+		field  string // the field expected to fail, or "" if valid
+	}{
+		{"replicas in range", "k8s_scale_deployment", map[string]interface{}{"namespace": "default", "name": "web", "replicas": 3, "confirm": true}, ""},
+		{"replicas over maximum", "k8s_scale_deployment", map[string]interface{}{"namespace": "default", "name": "web", "replicas": 101, "confirm": true}, "replicas"},
+		{"fractional replicas", "k8s_scale_deployment", map[string]interface{}{"namespace": "default", "name": "web", "replicas": 2.5, "confirm": true}, "replicas"},
+		{"confirm false", "k8s_cordon_node", map[string]interface{}{"name": "node-1", "confirm": false}, "confirm"},
+		{"confirm missing", "k8s_drain_node", map[string]interface{}{"name": "node-1"}, "confirm"},
+		{"unknown enum value", "k8s_top_nodes", map[string]interface{}{"sortBy": "disk"}, "sortBy"},
+		{"wrong type", "k8s_image_audit", map[string]interface{}{"namespace": "default", "flaggedOnly": "yes"}, "flaggedOnly"},
+		{"namespace too long", "k8s_list_services", map[string]interface{}{"namespace": strings.Repeat("a", 64)}, "namespace"},
+		{"permission pattern", "server_create_api_key", map[string]interface{}{"name": "ci", "permissions": []string{"K8S:pods"}, "confirm": true}, "permissions.0"},
+	}
+	for _, tt := range tests {
+		result := v.ValidateToolInput(tt.tool, tt.inputs)
+		if tt.field == "" && !result.Valid {
+			t.Errorf("%s: unexpected errors %v", tt.name, result.Errors)
+		}
+		if tt.field != "" && (result.Valid || !hasFieldError(result, tt.field)) {
+			t.Errorf("%s: expected a %s error, got %v", tt.name, tt.field, result.Errors)
+		}
+	}
+
+	if result := v.ValidateToolInput("k8s_create_secret", map[string]interface{}{
+		"namespace": "default",
+		"name":      "db-credentials",
+		"confirm":   true,
+		"data":      "hunter2",
+	}); !hasFieldError(result, "data") || strings.Contains(fmt.Sprint(result.Errors), "hunter2") {
+		t.Errorf("expected a data error that doesn't echo the value, got %v", result.Errors)
+	}
+}
+
+func TestLabelAndAnnotationInputs(t *testing.T) {
+	v := NewValidator()
+	target := func(field string, changes map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"kind": "deploy", "namespace": "default", "name": "web", field: changes}
+	}
+	tests := []struct {
+		name   string
+		tool   string
+		inputs map[string]interface{}
+		field  string // the field expected to fail, or "" if valid
+	}{
+		{"set and remove", "k8s_label_resource", target("labels", map[string]interface{}{"tier": "backend", "example.com/canary": nil}), ""},
+		{"invalid key", "k8s_label_resource", target("labels", map[string]interface{}{"-tier": "backend"}), "labels.key"},
+		{"invalid value", "k8s_label_resource", target("labels", map[string]interface{}{"tier": "back end"}), "labels.tier"},
+		{"no changes", "k8s_label_resource", target("labels", map[string]interface{}{}), "labels"},
+		{"free-form annotation", "k8s_annotate_resource", target("annotations", map[string]interface{}{"note": "owned by payments, see runbook"}), ""},
+		{"invalid annotation key", "k8s_annotate_resource", target("annotations", map[string]interface{}{"a/b/c": "x"}), "annotations.key"},
+	}
+	for _, tt := range tests {
+		result := v.ValidateToolInput(tt.tool, tt.inputs)
+		if tt.field == "" && !result.Valid {
+			t.Errorf("%s: unexpected errors %v", tt.name, result.Errors)
+		}
+		if tt.field != "" && (result.Valid || !hasFieldError(result, tt.field)) {
+			t.Errorf("%s: expected a %s error, got %v", tt.name, tt.field, result.Errors)
+		}
+	}
+}
+
+func hasFieldError(result *ValidationResult, field string) bool {
+	for _, err := range result.Errors {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
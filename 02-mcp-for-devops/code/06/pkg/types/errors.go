@@ -1 +1,47 @@
 package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MCPError represents structured errors for MCP responses
+type MCPError struct {
+	Code        int               `json:"code"`
+	Message     string            `json:"message"`
+	Data        map[string]string `json:"data,omitempty"`
+	Suggestions []string          `json:"suggestions,omitempty"`
+}
+
+func (e *MCPError) Error() string {
+	return fmt.Sprintf("MCP Error %d: %s", e.Code, e.Message)
+}
+
+// Common error codes
+const (
+	ErrorCodeInvalidRequest     = -32600
+	ErrorCodeMethodNotFound     = -32601
+	ErrorCodeInvalidParams      = -32602
+	ErrorCodeInternalError      = -32603
+	ErrorCodeResourceNotFound   = -32000
+	ErrorCodeUnauthorized       = -32001
+	ErrorCodeForbidden          = -32002
+	ErrorCodeTimeout            = -32003
+	ErrorCodeClusterUnavailable = -32004
+	ErrorCodeRateLimited        = -32005
+)
+
+// NewError returns an MCPError with the given code and message
+func NewError(code int, format string, args ...interface{}) *MCPError {
+	return &MCPError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ErrorCode returns the code of the MCPError in err's chain, or ErrorCodeInternalError
+// when there is none
+func ErrorCode(err error) int {
+	var mcpErr *MCPError
+	if errors.As(err, &mcpErr) {
+		return mcpErr.Code
+	}
+	return ErrorCodeInternalError
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // Resource represents a Kubernetes resource exposed through MCP
@@ -26,12 +27,20 @@ type ResourceContent struct {
 type K8sResourceType string
 
 const (
-	ResourceTypePod        K8sResourceType = "pod"
-	ResourceTypeService    K8sResourceType = "service"
-	ResourceTypeDeployment K8sResourceType = "deployment"
-	ResourceTypeConfigMap  K8sResourceType = "configmap"
-	ResourceTypeSecret     K8sResourceType = "secret"
-	ResourceTypeNamespace  K8sResourceType = "namespace"
+	ResourceTypePod                   K8sResourceType = "pod"
+	ResourceTypeService               K8sResourceType = "service"
+	ResourceTypeDeployment            K8sResourceType = "deployment"
+	ResourceTypeConfigMap             K8sResourceType = "configmap"
+	ResourceTypeSecret                K8sResourceType = "secret"
+	ResourceTypeNamespace             K8sResourceType = "namespace"
+	ResourceTypeStatefulSet           K8sResourceType = "statefulset"
+	ResourceTypeDaemonSet             K8sResourceType = "daemonset"
+	ResourceTypeNode                  K8sResourceType = "node"
+	ResourceTypePersistentVolumeClaim K8sResourceType = "persistentvolumeclaim"
+	ResourceTypePersistentVolume      K8sResourceType = "persistentvolume"
+	ResourceTypeJob                   K8sResourceType = "job"
+	ResourceTypeCronJob               K8sResourceType = "cronjob"
+	ResourceTypeHPA                   K8sResourceType = "horizontalpodautoscaler"
 )
 
 // ResourceIdentifier uniquely identifies a Kubernetes resource
@@ -47,3 +56,45 @@ func (r ResourceIdentifier) ToURI() string {
 	}
 	return string("k8s://" + string(r.Type) + "/" + r.Namespace + "/" + r.Name)
 }
+
+// Verbosity controls how much detail tools and formatters return
+type Verbosity string
+
+const (
+	VerbositySummary  Verbosity = "summary"
+	VerbosityNormal   Verbosity = "normal"
+	VerbosityDetailed Verbosity = "detailed"
+)
+
+// ParseVerbosity converts a string to a Verbosity. An empty string means normal.
+func ParseVerbosity(value string) (Verbosity, error) {
+	switch Verbosity(value) {
+	case "", VerbosityNormal:
+		return VerbosityNormal, nil
+	case VerbositySummary, VerbosityDetailed:
+		return Verbosity(value), nil
+	default:
+		return VerbosityNormal, fmt.Errorf("invalid verbosity %q: must be summary, normal, or detailed", value)
+	}
+}
+
+// OutputFormat selects how resource reads are rendered
+type OutputFormat string
+
+const (
+	OutputFormatMarkdown OutputFormat = "markdown"
+	OutputFormatJSON     OutputFormat = "json"
+	OutputFormatYAML     OutputFormat = "yaml"
+)
+
+// ParseOutputFormat converts a string to an OutputFormat. An empty string means markdown.
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	switch OutputFormat(value) {
+	case "", OutputFormatMarkdown:
+		return OutputFormatMarkdown, nil
+	case OutputFormatJSON, OutputFormatYAML:
+		return OutputFormat(value), nil
+	default:
+		return OutputFormatMarkdown, fmt.Errorf("invalid format %q: must be markdown, json, or yaml", value)
+	}
+}
@@ -23,12 +23,19 @@ func main() {
 	pprofAddr := flag.String("pprof-addr", "", "pprof listen address (empty = disabled)")
 	rps := flag.Float64("ratelimit-rps", 50, "per-identity requests/second")
 	burst := flag.Int("ratelimit-burst", 100, "per-identity burst")
+	toolCosts := flag.String("tool-costs", "", "per-tool rate-limit cost, e.g. k8s_search_pods=10,k8s_list_pods=2 (default 1)")
 	flag.Parse()
 
+	costs, err := ratelimit.ParseCosts(*toolCosts)
+	if err != nil {
+		log.Fatalf("tool-costs: %v", err)
+	}
+
 	rec := metrics.New("mcp")
 	limiter := ratelimit.New(*rps, *burst, 10*time.Minute)
 
 	srv := perfmcp.New("k8s-mcp-perf", "1.0.0", rec, limiter)
+	srv.SetToolCosts(costs)
 
 	// Example tool: echo back the request to exercise the middleware.
 	srv.AddTool(
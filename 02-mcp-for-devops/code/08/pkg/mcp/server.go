@@ -19,6 +19,7 @@ type Server struct {
 	mcp     *server.MCPServer
 	rec     *metrics.Recorder
 	limiter *ratelimit.Limiter
+	costs   ratelimit.Costs
 }
 
 // New builds a fresh server. Tools must be registered through AddTool so the
@@ -34,9 +35,14 @@ func New(name, version string, rec *metrics.Recorder, limiter *ratelimit.Limiter
 	}
 }
 
+// SetToolCosts sets how many rate-limit tokens each tool consumes per call.
+// Tools without an entry cost 1. Each tool's cost is fixed when it is
+// registered, so call this before AddTool.
+func (s *Server) SetToolCosts(costs ratelimit.Costs) { s.costs = costs }
+
 // AddTool registers a handler wrapped with metrics + rate limiting.
 func (s *Server) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
-	wrapped := s.instrument(tool.Name, handler)
+	wrapped := s.instrument(tool.Name, s.costs.Cost(tool.Name), handler)
 	s.mcp.AddTool(tool, wrapped)
 }
 
@@ -46,10 +52,10 @@ func (s *Server) MCP() *server.MCPServer { return s.mcp }
 // ServeStdio runs stdio transport.
 func (s *Server) ServeStdio() error { return server.ServeStdio(s.mcp) }
 
-func (s *Server) instrument(name string, h server.ToolHandlerFunc) server.ToolHandlerFunc {
+func (s *Server) instrument(name string, cost int, h server.ToolHandlerFunc) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		identity := identityFrom(ctx)
-		if s.limiter != nil && !s.limiter.Allow(identity) {
+		if s.limiter != nil && !s.limiter.AllowN(identity, cost) {
 			s.rec.RateLimited.WithLabelValues(identity).Inc()
 			return mcp.NewToolResultError(fmt.Sprintf("rate limit exceeded for %s", identity)), nil
 		}
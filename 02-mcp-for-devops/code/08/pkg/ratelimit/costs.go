@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Costs maps tool names to the number of tokens a call consumes. Tools that
+// are not listed cost 1, so a flat limiter is just an empty Costs.
+type Costs map[string]int
+
+// Cost returns the token cost of a tool call.
+func (c Costs) Cost(tool string) int {
+	if cost, ok := c[tool]; ok {
+		return cost
+	}
+	return 1
+}
+
+// ParseCosts parses a comma-separated list of tool=cost pairs, e.g.
+// "k8s_search_pods=10,k8s_list_pods=2".
+func ParseCosts(spec string) (Costs, error) {
+	costs := Costs{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		tool, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(tool) == "" {
+			return nil, fmt.Errorf("invalid tool cost %q, want tool=cost", pair)
+		}
+		cost, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || cost < 1 {
+			return nil, fmt.Errorf("invalid cost for tool %q: must be a positive integer", tool)
+		}
+		costs[strings.TrimSpace(tool)] = cost
+	}
+	return costs, nil
+}
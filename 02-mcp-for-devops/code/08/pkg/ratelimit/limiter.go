@@ -41,6 +41,20 @@ func New(rps float64, burst int, ttl time.Duration) *Limiter {
 
 // Allow returns true when the caller is permitted to proceed.
 func (l *Limiter) Allow(identity string) bool {
+	return l.AllowN(identity, 1)
+}
+
+// AllowN is like Allow but consumes n tokens, so expensive operations drain
+// the bucket faster. n is clamped to the burst size; otherwise a cost above
+// the burst could never be satisfied.
+func (l *Limiter) AllowN(identity string, n int) bool {
+	if n < 1 {
+		n = 1
+	}
+	if n > l.burst {
+		n = l.burst
+	}
+
 	now := time.Now()
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -60,5 +74,5 @@ func (l *Limiter) Allow(identity string) bool {
 			}
 		}
 	}
-	return b.limiter.Allow()
+	return b.limiter.AllowN(now, n)
 }
@@ -32,3 +32,39 @@ func TestLimiterRejectsBurst(t *testing.T) {
 		t.Fatal("third call should be rejected")
 	}
 }
+
+func TestLimiterChargesCost(t *testing.T) {
+	l := New(0.01, 10, time.Minute)
+	if !l.AllowN("u", 6) {
+		t.Fatal("first call costing 6 should succeed")
+	}
+	if l.AllowN("u", 6) {
+		t.Fatal("second call costing 6 should be rejected with 4 tokens left")
+	}
+	if !l.Allow("u") {
+		t.Fatal("a cheap call should still fit in the remaining tokens")
+	}
+}
+
+func TestLimiterClampsCostToBurst(t *testing.T) {
+	l := New(0.01, 5, time.Minute)
+	if !l.AllowN("u", 50) {
+		t.Fatal("a cost above the burst should be clamped, not rejected forever")
+	}
+}
+
+func TestParseCosts(t *testing.T) {
+	costs, err := ParseCosts("k8s_search_pods=10, k8s_list_pods=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := costs.Cost("k8s_search_pods"); got != 10 {
+		t.Fatalf("want cost 10 got %d", got)
+	}
+	if got := costs.Cost("k8s_get_pod"); got != 1 {
+		t.Fatalf("unlisted tool: want cost 1 got %d", got)
+	}
+	if _, err := ParseCosts("k8s_list_pods=0"); err == nil {
+		t.Fatal("want error for non-positive cost")
+	}
+}